@@ -0,0 +1,199 @@
+package apperror
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"fiber/skp/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Kind int
+
+const (
+	KindBadRequest Kind = iota + 1
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnavailable
+	KindTimeout
+	KindInternal
+)
+
+var kindStatus = map[Kind]int{
+	KindBadRequest:   fiber.StatusBadRequest,
+	KindValidation:   fiber.StatusBadRequest,
+	KindUnauthorized: fiber.StatusUnauthorized,
+	KindForbidden:    fiber.StatusForbidden,
+	KindNotFound:     fiber.StatusNotFound,
+	KindConflict:     fiber.StatusConflict,
+	KindUnavailable:  fiber.StatusServiceUnavailable,
+	KindTimeout:      fiber.StatusGatewayTimeout,
+	KindInternal:     fiber.StatusInternalServerError,
+}
+
+// Error adalah error domain yang aman dikirim ke client. Key (beserta Args)
+// adalah kunci pesan di katalog i18n dan diterjemahkan saat dirender, sedangkan
+// Err hanya dicatat di log server.
+type Error struct {
+	Kind   Kind
+	Code   string
+	Key    string
+	Args   []any
+	Fields []model.FieldError
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Key, e.Err)
+	}
+	return e.Code + ": " + e.Key
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status mengembalikan status HTTP untuk error ini.
+func (e *Error) Status() int {
+	if status, ok := kindStatus[e.Kind]; ok {
+		return status
+	}
+	return fiber.StatusInternalServerError
+}
+
+// Wrap menyimpan penyebab internal tanpa mengubah pesan untuk client.
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+func newError(kind Kind, code, key string, args ...any) *Error {
+	return &Error{Kind: kind, Code: code, Key: key, Args: args}
+}
+
+func BadRequest(code, key string, args ...any) *Error {
+	return newError(KindBadRequest, code, key, args...)
+}
+
+func Validation(code, key string, fields []model.FieldError) *Error {
+	e := newError(KindValidation, code, key)
+	e.Fields = fields
+	return e
+}
+
+func Unauthorized(code, key string, args ...any) *Error {
+	return newError(KindUnauthorized, code, key, args...)
+}
+
+func Forbidden(code, key string, args ...any) *Error {
+	return newError(KindForbidden, code, key, args...)
+}
+
+func NotFound(code, key string, args ...any) *Error {
+	return newError(KindNotFound, code, key, args...)
+}
+
+func Conflict(code, key string, args ...any) *Error {
+	return newError(KindConflict, code, key, args...)
+}
+
+func Internal(err error) *Error {
+	return newError(KindInternal, CodeInternal, "error.internal").Wrap(err)
+}
+
+// From mengubah error apa pun menjadi *Error. Error domain dikembalikan apa
+// adanya, error driver dipetakan ke NotFound/Timeout/Unavailable, dan sisanya
+// menjadi Internal.
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fromFiber(fiberErr)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, mongo.ErrNoDocuments) {
+		return NotFound(CodeNotFound, "error.not_found").Wrap(err)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err) {
+		return newError(KindTimeout, CodeTimeout, "error.timeout").Wrap(err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pgQueryCanceled:
+			return newError(KindTimeout, CodeTimeout, "error.timeout").Wrap(err)
+		case pgUniqueViolation:
+			return Conflict(CodeConflict, "error.conflict").Wrap(err)
+		}
+	}
+
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		mongo.IsNetworkError(err) {
+		return newError(KindUnavailable, CodeUnavailable, "error.unavailable").Wrap(err)
+	}
+
+	return Internal(err)
+}
+
+// Translate seperti From, tetapi memakai notFound saat err menandakan data tidak ada.
+func Translate(err error, notFound *Error) *Error {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, mongo.ErrNoDocuments) {
+		return notFound.Wrap(err)
+	}
+	return From(err)
+}
+
+// Status mengembalikan status HTTP untuk err apa pun.
+func Status(err error) int {
+	if err == nil {
+		return fiber.StatusOK
+	}
+	return From(err).Status()
+}
+
+func fromFiber(err *fiber.Error) *Error {
+	switch err.Code {
+	case fiber.StatusNotFound:
+		return NotFound(CodeRouteNotFound, "error.route_not_found").Wrap(err)
+	case fiber.StatusMethodNotAllowed:
+		return newError(KindBadRequest, CodeMethodNotAllowed, "error.method_not_allowed").Wrap(err)
+	case fiber.StatusRequestEntityTooLarge:
+		return BadRequest(CodePayloadTooLarge, "error.payload_too_large").Wrap(err)
+	case fiber.StatusRequestTimeout, fiber.StatusGatewayTimeout:
+		return newError(KindTimeout, CodeTimeout, "error.timeout").Wrap(err)
+	case fiber.StatusServiceUnavailable:
+		return newError(KindUnavailable, CodeUnavailable, "error.unavailable").Wrap(err)
+	}
+	if err.Code >= fiber.StatusBadRequest && err.Code < fiber.StatusInternalServerError {
+		return BadRequest(CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	return Internal(err)
+}
+
+const (
+	pgQueryCanceled   = "57014"
+	pgUniqueViolation = "23505"
+)
@@ -0,0 +1,123 @@
+package apperror
+
+// Kode error yang stabil dan dapat dibaca mesin. Jangan mengganti nilai yang
+// sudah dipakai client; tambahkan kode baru bila perlu.
+const (
+	// Umum
+	CodeInternal         = "INTERNAL_ERROR"
+	CodeUnavailable      = "SERVICE_UNAVAILABLE"
+	CodeTimeout          = "TIMEOUT"
+	CodeNotFound         = "NOT_FOUND"
+	CodeConflict         = "CONFLICT"
+	CodeInvalidInput     = "INVALID_INPUT"
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeRouteNotFound    = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	CodePayloadTooLarge  = "PAYLOAD_TOO_LARGE"
+
+	// Auth
+	CodeTokenMissing          = "TOKEN_MISSING"
+	CodeTokenInvalidFormat    = "TOKEN_INVALID_FORMAT"
+	CodeTokenInvalid          = "TOKEN_INVALID"
+	CodeTokenTypeInvalid      = "TOKEN_TYPE_INVALID"
+	CodeTokenRevoked          = "TOKEN_REVOKED"
+	CodeTokenClaimsIncomplete = "TOKEN_CLAIMS_INCOMPLETE"
+	CodeCredentialsRequired   = "CREDENTIALS_REQUIRED"
+	CodeInvalidCredentials    = "INVALID_CREDENTIALS"
+	CodeRefreshTokenInvalid   = "REFRESH_TOKEN_INVALID"
+	CodePermissionDenied      = "PERMISSION_DENIED"
+
+	// User
+	CodeInvalidUserID        = "INVALID_USER_ID"
+	CodeUserNotFound         = "USER_NOT_FOUND"
+	CodeRoleInvalid          = "ROLE_INVALID"
+	CodeStudentDataRequired  = "STUDENT_DATA_REQUIRED"
+	CodeLecturerDataRequired = "LECTURER_DATA_REQUIRED"
+	CodeUsernameTaken        = "USERNAME_TAKEN"
+
+	// Akademik
+	CodeInvalidStudentID        = "INVALID_STUDENT_ID"
+	CodeStudentNotFound         = "STUDENT_NOT_FOUND"
+	CodeStudentProfileNotFound  = "STUDENT_PROFILE_NOT_FOUND"
+	CodeInvalidLecturerID       = "INVALID_LECTURER_ID"
+	CodeLecturerNotFound        = "LECTURER_NOT_FOUND"
+	CodeNotStudentAdvisor       = "NOT_STUDENT_ADVISOR"
+	CodeInvalidCSV              = "INVALID_CSV"
+	CodeAdvisorPlanStale        = "ADVISOR_PLAN_STALE"
+	CodeInvalidStudentStatus    = "INVALID_STUDENT_STATUS"
+	CodeStudentNotActive        = "STUDENT_NOT_ACTIVE"
+	CodeStudentStatusTransition = "STUDENT_STATUS_TRANSITION_NOT_ALLOWED"
+	CodeStudentPendingReviews   = "STUDENT_PENDING_REVIEWS"
+	CodeSKPFrozen               = "SKP_FROZEN"
+
+	// Achievement
+	CodeInvalidAchievementID     = "INVALID_ACHIEVEMENT_ID"
+	CodeAchievementNotFound      = "ACHIEVEMENT_NOT_FOUND"
+	CodeAchievementNotOwner      = "ACHIEVEMENT_NOT_OWNER"
+	CodeAchievementNotDraft      = "ACHIEVEMENT_NOT_DRAFT"
+	CodeAchievementNotEditable   = "ACHIEVEMENT_NOT_EDITABLE"
+	CodeAchievementNotSubmitted  = "ACHIEVEMENT_NOT_SUBMITTED"
+	CodeInvalidPoints            = "INVALID_POINTS"
+	CodeRejectionNoteRequired    = "REJECTION_NOTE_REQUIRED"
+	CodeAttachmentRequired       = "ATTACHMENT_REQUIRED"
+	CodeAttachmentTooLarge       = "ATTACHMENT_TOO_LARGE"
+	CodeAttachmentTypeNotAllowed = "ATTACHMENT_TYPE_NOT_ALLOWED"
+	CodeInvalidDetails           = "INVALID_DETAILS"
+	CodeAchievementNotVerified   = "ACHIEVEMENT_NOT_VERIFIED"
+	CodeRevocationReasonRequired = "REVOCATION_REASON_REQUIRED"
+
+	// Tipe achievement
+	CodeAchievementTypeNotFound = "ACHIEVEMENT_TYPE_NOT_FOUND"
+	CodeAchievementTypeInactive = "ACHIEVEMENT_TYPE_INACTIVE"
+	CodeAchievementTypeExists   = "ACHIEVEMENT_TYPE_EXISTS"
+	CodeInvalidDetailsSchema    = "INVALID_DETAILS_SCHEMA"
+
+	// Tim
+	CodeTeamInvalid      = "TEAM_INVALID"
+	CodeTeamLeaderOnly   = "TEAM_LEADER_ONLY"
+	CodeTeamMemberOnly   = "TEAM_MEMBER_ONLY"
+	CodeTeamNotConfirmed = "TEAM_NOT_CONFIRMED"
+
+	// Notifikasi
+	CodeInvalidNotificationID = "INVALID_NOTIFICATION_ID"
+	CodeNotificationNotFound  = "NOTIFICATION_NOT_FOUND"
+
+	// Banding
+	CodeInvalidAppealID        = "INVALID_APPEAL_ID"
+	CodeAppealNotFound         = "APPEAL_NOT_FOUND"
+	CodeAchievementNotRejected = "ACHIEVEMENT_NOT_REJECTED"
+	CodeAppealNotAllowed       = "APPEAL_NOT_ALLOWED"
+	CodeAppealPending          = "APPEAL_PENDING"
+	CodeAppealDecided          = "APPEAL_DECIDED"
+	CodeAppealReviewForbidden  = "APPEAL_REVIEW_FORBIDDEN"
+
+	// Rantai verifikasi
+	CodeInvalidVerificationChainID = "INVALID_VERIFICATION_CHAIN_ID"
+	CodeVerificationChainNotFound  = "VERIFICATION_CHAIN_NOT_FOUND"
+	CodeVerificationChainExists    = "VERIFICATION_CHAIN_EXISTS"
+	CodeNotStageApprover           = "NOT_STAGE_APPROVER"
+	CodeStageDecided               = "STAGE_DECIDED"
+
+	// Delegasi dosen wali
+	CodeInvalidDelegationID = "INVALID_DELEGATION_ID"
+	CodeDelegationNotFound  = "DELEGATION_NOT_FOUND"
+	CodeDelegationOverlap   = "DELEGATION_OVERLAP"
+	CodeDelegationRevoked   = "DELEGATION_REVOKED"
+
+	// Unit akademik
+	CodeInvalidAcademicUnitID = "INVALID_ACADEMIC_UNIT_ID"
+	CodeAcademicUnitNotFound  = "ACADEMIC_UNIT_NOT_FOUND"
+	CodeAcademicUnitExists    = "ACADEMIC_UNIT_EXISTS"
+	CodeAcademicUnitInUse     = "ACADEMIC_UNIT_IN_USE"
+
+	// Kalender akademik
+	CodeInvalidAcademicPeriodID   = "INVALID_ACADEMIC_PERIOD_ID"
+	CodeAcademicPeriodNotFound    = "ACADEMIC_PERIOD_NOT_FOUND"
+	CodeAcademicPeriodExists      = "ACADEMIC_PERIOD_EXISTS"
+	CodeAcademicPeriodOverlap     = "ACADEMIC_PERIOD_OVERLAP"
+	CodeInvalidSubmissionWindowID = "INVALID_SUBMISSION_WINDOW_ID"
+	CodeSubmissionWindowNotFound  = "SUBMISSION_WINDOW_NOT_FOUND"
+	CodeSubmissionWindowClosed    = "SUBMISSION_WINDOW_CLOSED"
+	CodeInvalidExemptionID        = "INVALID_SUBMISSION_EXEMPTION_ID"
+	CodeExemptionNotFound         = "SUBMISSION_EXEMPTION_NOT_FOUND"
+)
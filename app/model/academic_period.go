@@ -0,0 +1,98 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Semester dalam satu tahun akademik.
+const (
+	SemesterOdd  = "odd"
+	SemesterEven = "even"
+)
+
+var semesterNames = map[string]string{
+	SemesterOdd:  "Ganjil",
+	SemesterEven: "Genap",
+}
+
+// AcademicPeriod adalah satu semester dengan rentang tanggal inklusif.
+// Achievement ditandai dengan periode yang memuat tanggal kegiatannya.
+type AcademicPeriod struct {
+	ID        uuid.UUID `json:"id"`
+	Year      string    `json:"year"`
+	Semester  string    `json:"semester"`
+	Label     string    `json:"label"`
+	StartsOn  string    `json:"starts_on"`
+	EndsOn    string    `json:"ends_on"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PeriodLabel menghasilkan label tampilan seperti "2025/2026 Ganjil".
+func PeriodLabel(year, semester string) string {
+	return year + " " + semesterNames[semester]
+}
+
+type AcademicPeriodRequest struct {
+	Year     string `json:"year" validate:"required,academic_year"`
+	Semester string `json:"semester" validate:"required,oneof=odd even"`
+	StartsOn string `json:"starts_on" validate:"required,datetime=2006-01-02"`
+	EndsOn   string `json:"ends_on" validate:"required,datetime=2006-01-02"`
+}
+
+// SubmissionWindow adalah rentang waktu achievement dalam suatu periode boleh
+// disubmit.
+type SubmissionWindow struct {
+	ID        uuid.UUID `json:"id"`
+	PeriodID  uuid.UUID `json:"period_id"`
+	OpensAt   time.Time `json:"opens_at"`
+	ClosesAt  time.Time `json:"closes_at"`
+	Note      string    `json:"note,omitempty"`
+	Open      bool      `json:"open"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SubmissionWindowRequest struct {
+	OpensAt  time.Time `json:"opens_at" validate:"required"`
+	ClosesAt time.Time `json:"closes_at" validate:"required"`
+	Note     string    `json:"note" validate:"max=500"`
+}
+
+// SubmissionExemption mengizinkan mahasiswa submit di luar jendela sampai
+// ExpiresAt; tanpa PeriodID berlaku untuk semua periode.
+type SubmissionExemption struct {
+	ID          uuid.UUID  `json:"id"`
+	StudentID   uuid.UUID  `json:"student_id"`
+	NIM         string     `json:"nim"`
+	StudentName string     `json:"student_name"`
+	PeriodID    *uuid.UUID `json:"period_id,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	Reason      string     `json:"reason"`
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type SubmissionExemptionRequest struct {
+	StudentID string    `json:"student_id" validate:"required,uuid"`
+	PeriodID  string    `json:"period_id" validate:"omitempty,uuid"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+	Reason    string    `json:"reason" validate:"required,max=500"`
+}
+
+// SubmissionGate merangkum aturan jendela yang berlaku bagi sebuah
+// achievement saat disubmit.
+type SubmissionGate struct {
+	PeriodLabel string
+	HasWindows  bool
+	Open        bool
+	Exempted    bool
+	NextOpensAt *time.Time
+}
+
+// Allowed bernilai true bila periode tidak punya jendela, ada jendela yang
+// sedang terbuka, atau mahasiswa punya pengecualian.
+func (g SubmissionGate) Allowed() bool {
+	return !g.HasWindows || g.Open || g.Exempted
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jenis unit akademik, dari yang paling atas.
+const (
+	UnitFaculty      = "faculty"
+	UnitDepartment   = "department"
+	UnitStudyProgram = "study_program"
+)
+
+// AcademicUnit mewakili fakultas, jurusan, atau program studi. ParentID adalah
+// fakultas untuk jurusan dan jurusan untuk program studi.
+type AcademicUnit struct {
+	ID         uuid.UUID  `json:"id"`
+	Code       string     `json:"code"`
+	Name       string     `json:"name"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	ParentName string     `json:"parent_name,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type AcademicUnitRequest struct {
+	Code     string `json:"code" validate:"required,max=20"`
+	Name     string `json:"name" validate:"required,max=100"`
+	ParentID string `json:"parent_id" validate:"omitempty,uuid"`
+}
+
+type MergeAcademicUnitRequest struct {
+	TargetID string `json:"target_id" validate:"required,uuid"`
+}
+
+// AcademicUnitFilter membatasi data pada satu cabang hierarki; bila beberapa
+// diisi, semuanya harus terpenuhi.
+type AcademicUnitFilter struct {
+	FacultyID      *uuid.UUID
+	DepartmentID   *uuid.UUID
+	StudyProgramID *uuid.UUID
+}
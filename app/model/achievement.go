@@ -4,16 +4,26 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	TypeAcademic      = "academic"
+	TypeCompetition   = "competition"
+	TypeOrganization  = "organization"
+	TypePublication   = "publication"
+	TypeCertification = "certification"
+	TypeOther         = "other"
+)
+
 type AchievementMongo struct {
 	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	StudentID       string             `bson:"studentId" json:"studentId"`
 	AchievementType string             `bson:"achievementType" json:"achievementType"`
 	Title           string             `bson:"title" json:"title"`
 	Description     string             `bson:"description" json:"description"`
-	Details         AchievementDetails `bson:"details" json:"details"`
+	Details         bson.M             `bson:"details" json:"details"`
 	Attachments     []Attachment       `bson:"attachments" json:"attachments"`
 	Tags            []string           `bson:"tags" json:"tags"`
 	Points          int                `bson:"points" json:"points"`
@@ -21,129 +31,122 @@ type AchievementMongo struct {
 	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
-type AchievementDetails struct {
-	// Competition
-	CompetitionName  string `bson:"competitionName,omitempty" json:"competitionName,omitempty"`
-	CompetitionLevel string `bson:"competitionLevel,omitempty" json:"competitionLevel,omitempty"`
-	Rank             int    `bson:"rank,omitempty" json:"rank,omitempty"`
-	MedalType        string `bson:"medalType,omitempty" json:"medalType,omitempty"`
-
-	// Publication
-	PublicationType  string   `bson:"publicationType,omitempty" json:"publicationType,omitempty"`
-	PublicationTitle string   `bson:"publicationTitle,omitempty" json:"publicationTitle,omitempty"`
-	Authors          []string `bson:"authors,omitempty" json:"authors,omitempty"`
-	Publisher        string   `bson:"publisher,omitempty" json:"publisher,omitempty"`
-	ISSN             string   `bson:"issn,omitempty" json:"issn,omitempty"`
-
-	// Organization
-	OrganizationName string              `bson:"organizationName,omitempty" json:"organizationName,omitempty"`
-	Position         string              `bson:"position,omitempty" json:"position,omitempty"`
-	Period           *OrganizationPeriod `bson:"period,omitempty" json:"period,omitempty"`
-
-	// Certification
-	CertificationName   string    `bson:"certificationName,omitempty" json:"certificationName,omitempty"`
-	IssuedBy            string    `bson:"issuedBy,omitempty" json:"issuedBy,omitempty"`
-	CertificationNumber string    `bson:"certificationNumber,omitempty" json:"certificationNumber,omitempty"`
-	ValidUntil          time.Time `bson:"validUntil,omitempty" json:"validUntil,omitempty"`
-
-	// General
-	EventDate time.Time `bson:"eventDate,omitempty" json:"eventDate,omitempty"`
-	Location  string    `bson:"location,omitempty" json:"location,omitempty"`
-	Organizer string    `bson:"organizer,omitempty" json:"organizer,omitempty"`
-	Score     float64   `bson:"score,omitempty" json:"score,omitempty"`
-}
-
-type OrganizationPeriod struct {
-	Start time.Time `bson:"start" json:"start"`
-	End   time.Time `bson:"end" json:"end"`
-}
-
 type Attachment struct {
 	FileName   string    `bson:"fileName" json:"fileName"`
 	FileURL    string    `bson:"fileUrl" json:"fileUrl"`
 	FileType   string    `bson:"fileType" json:"fileType"`
+	Checksum   string    `bson:"checksum,omitempty" json:"checksum,omitempty"`
 	UploadedAt time.Time `bson:"uploadedAt" json:"uploadedAt"`
 }
 
 type CreateAchievementRequest struct {
-	Title               string               `json:"title" binding:"required"`
-	AchievementType     string               `json:"achievement_type" binding:"required,oneof=academic competition organization publication certification other"`
-	Description         string               `json:"description"`
-	CompetitionDetails  *CompetitionRequest  `json:"competition_details,omitempty"`
-	PublicationDetails  *PublicationRequest  `json:"publication_details,omitempty"`
-	OrganizationDetails *OrganizationRequest `json:"organization_details,omitempty"`
-	EventDate           string               `json:"event_date"`
-	Tags                []string             `json:"tags"`
+	Title                string                 `json:"title" validate:"required,max=200"`
+	AchievementType      string                 `json:"achievement_type" validate:"required,slug,max=50"`
+	Description          string                 `json:"description" validate:"max=2000"`
+	Details              map[string]interface{} `json:"details,omitempty"`
+	CompetitionDetails   *CompetitionRequest    `json:"competition_details,omitempty"`
+	PublicationDetails   *PublicationRequest    `json:"publication_details,omitempty"`
+	OrganizationDetails  *OrganizationRequest   `json:"organization_details,omitempty"`
+	CertificationDetails *CertificationRequest  `json:"certification_details,omitempty"`
+	EventDate            string                 `json:"event_date" validate:"omitempty,datetime=2006-01-02"`
+	Tags                 []string               `json:"tags" validate:"max=10,dive,required,max=30"`
 }
 
 type UpdateAchievementRequest struct {
-	Title               *string              `json:"title,omitempty"`
-	AchievementType     *string              `json:"achievement_type,omitempty" binding:"omitempty,oneof=academic competition organization publication certification other"`
-	Description         *string              `json:"description,omitempty"`
-	CompetitionDetails  *CompetitionRequest  `json:"competition_details,omitempty"`
-	PublicationDetails  *PublicationRequest  `json:"publication_details,omitempty"`
-	OrganizationDetails *OrganizationRequest `json:"organization_details,omitempty"`
-	EventDate           *string              `json:"event_date,omitempty"`
-	Tags                *[]string            `json:"tags,omitempty"`
+	Title                *string                `json:"title,omitempty" validate:"omitempty,min=1,max=200"`
+	AchievementType      *string                `json:"achievement_type,omitempty" validate:"omitempty,slug,max=50"`
+	Description          *string                `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Details              map[string]interface{} `json:"details,omitempty"`
+	CompetitionDetails   *CompetitionRequest    `json:"competition_details,omitempty"`
+	PublicationDetails   *PublicationRequest    `json:"publication_details,omitempty"`
+	OrganizationDetails  *OrganizationRequest   `json:"organization_details,omitempty"`
+	CertificationDetails *CertificationRequest  `json:"certification_details,omitempty"`
+	EventDate            *string                `json:"event_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Tags                 *[]string              `json:"tags,omitempty" validate:"omitempty,max=10,dive,required,max=30"`
 }
 
 type AchievementResponse struct {
-	ID              uuid.UUID          `json:"id"`
-	MongoID         string             `json:"mongo_achievement_id"`
-	StudentID       uuid.UUID          `json:"student_id"`
-	StudentName     string             `json:"student_name,omitempty"`
-	Status          string             `json:"status"`
-	AchievementType string             `json:"achievement_type"`
-	Title           string             `json:"title"`
-	Description     string             `json:"description"`
-	Details         AchievementDetails `json:"details"`
-	Attachments     []Attachment       `json:"attachments"`
-	Tags            []string           `json:"tags"`
-	Points          int                `json:"points"`
-	RejectionNote   string             `json:"rejection_note,omitempty"`
-	CreatedAt       time.Time          `json:"created_at"`
-	UpdatedAt       time.Time          `json:"updated_at"`
+	ID              uuid.UUID            `json:"id"`
+	MongoID         string               `json:"mongo_achievement_id"`
+	StudentID       uuid.UUID            `json:"student_id"`
+	StudentName     string               `json:"student_name,omitempty"`
+	Status          string               `json:"status"`
+	AchievementType string               `json:"achievement_type"`
+	Title           string               `json:"title"`
+	Description     string               `json:"description"`
+	Details         bson.M               `json:"details"`
+	Attachments     []Attachment         `json:"attachments"`
+	Tags            []string             `json:"tags"`
+	Points          int                  `json:"points"`
+	RejectionNote   string               `json:"rejection_note,omitempty"`
+	TeamRole        string               `json:"team_role,omitempty"`
+	Period          string               `json:"period,omitempty"`
+	Team            []TeamMember         `json:"team,omitempty"`
+	Duplicates      []DuplicateSuspect   `json:"duplicate_suspects,omitempty"`
+	Comments        []AchievementComment `json:"comments,omitempty"`
+	ChangeRequests  []ChangeItem         `json:"change_requests,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
 }
 
 type CompetitionRequest struct {
-	CompetitionName  string `json:"competition_name"`
-	CompetitionLevel string `json:"competition_level"`
-	Rank             int    `json:"rank"`
-	MedalType        string `json:"medal_type"`
+	CompetitionName  string `json:"competition_name" validate:"required,max=200"`
+	CompetitionLevel string `json:"competition_level" validate:"required,max=50"`
+	Rank             int    `json:"rank" validate:"omitempty,gt=0"`
+	MedalType        string `json:"medal_type" validate:"max=50"`
 }
 
 type PublicationRequest struct {
-	PublicationTitle string   `json:"publication_title"`
-	Authors          []string `json:"authors"`
-	Publisher        string   `json:"publisher"`
-	ISSN             string   `json:"issn"`
+	PublicationTitle string   `json:"publication_title" validate:"required,max=300"`
+	Authors          []string `json:"authors" validate:"required,min=1,max=20,dive,required,max=100"`
+	Publisher        string   `json:"publisher" validate:"max=200"`
+	ISSN             string   `json:"issn" validate:"max=20"`
 }
 
 type OrganizationRequest struct {
-	OrganizationName string `json:"organization_name"`
-	Position         string `json:"position"`
-	StartDate        string `json:"start_date"`
-	EndDate          string `json:"end_date"`
+	OrganizationName string `json:"organization_name" validate:"required,max=200"`
+	Position         string `json:"position" validate:"required,max=100"`
+	StartDate        string `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate          string `json:"end_date" validate:"required,datetime=2006-01-02"`
+}
+
+type CertificationRequest struct {
+	CertificationName   string `json:"certification_name" validate:"required,max=200"`
+	IssuedBy            string `json:"issued_by" validate:"required,max=200"`
+	CertificationNumber string `json:"certification_number" validate:"max=100"`
+	ValidUntil          string `json:"valid_until" validate:"omitempty,datetime=2006-01-02"`
 }
 
 type VerifyRequest struct {
-	Points int `json:"points" form:"points" binding:"required,gt=0"`
+	Points int `json:"points" form:"points" validate:"required,gt=0"`
 }
 
 type RejectRequest struct {
-	RejectionNote string `json:"rejection_note" binding:"required"`
+	RejectionNote string `json:"rejection_note" validate:"required,max=1000"`
+}
+
+type RevokeRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
 }
 
 type AchievementHistoryResponse struct {
-	ID            uuid.UUID  `json:"id"`
-	Title         string     `json:"title"`
-	Status        string     `json:"status"`
-	CreatedAt     time.Time  `json:"created_at"`
-	SubmittedAt   *time.Time `json:"submitted_at,omitempty"`
-	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
-	VerifierName  string     `json:"verifier_name,omitempty"`
-	RejectionNote string     `json:"rejection_note,omitempty"`
-	Points        int        `json:"points,omitempty"`
+	ID               uuid.UUID            `json:"id"`
+	Title            string               `json:"title"`
+	Status           string               `json:"status"`
+	CreatedAt        time.Time            `json:"created_at"`
+	SubmittedAt      *time.Time           `json:"submitted_at,omitempty"`
+	VerifiedAt       *time.Time           `json:"verified_at,omitempty"`
+	VerifierName     string               `json:"verifier_name,omitempty"`
+	RejectionNote    string               `json:"rejection_note,omitempty"`
+	Points           int                  `json:"points,omitempty"`
+	RevokedAt        *time.Time           `json:"revoked_at,omitempty"`
+	RevokerName      string               `json:"revoker_name,omitempty"`
+	RevocationReason string               `json:"revocation_reason,omitempty"`
+	Stages           []AchievementStage   `json:"verification_stages,omitempty"`
+	PreviousStages   []AchievementStage   `json:"previous_verification_stages,omitempty"`
+	Comments         []AchievementComment `json:"comments,omitempty"`
+	ChangeRequests   []ChangeItem         `json:"change_requests,omitempty"`
+	Events           []AchievementEvent   `json:"events"`
 }
 
 type StatItem struct {
@@ -151,15 +154,22 @@ type StatItem struct {
 	Count int    `json:"count" bson:"count"`
 }
 type TopStudent struct {
-	StudentID          string `json:"student_id"`
-	StudentName        string `json:"student_name"`
-	Program            string `json:"program_study"`
-	TotalAchievements  int    `json:"total_achievements"`
-	TotalPoints        int    `json:"total_points"`
+	StudentID         string `json:"student_id"`
+	StudentName       string `json:"student_name"`
+	Program           string `json:"program_study"`
+	TotalAchievements int    `json:"total_achievements"`
+	TotalPoints       int    `json:"total_points"`
+}
+
+// ReportFilter membatasi achievement yang dihitung dalam laporan.
+type ReportFilter struct {
+	ExcludeExpired bool
+	Unit           AcademicUnitFilter
 }
 
 type StatsResponse struct {
 	TotalAchievements int64        `json:"total_achievements"`
+	TotalRevoked      int64        `json:"total_revoked"`
 	ByType            []StatItem   `json:"by_type"`
 	ByLevel           []StatItem   `json:"by_competition_level"`
 	ByPeriod          []StatItem   `json:"by_period"`
@@ -167,6 +177,6 @@ type StatsResponse struct {
 }
 
 type StudentStatsResponse struct {
-	StudentProfile TopStudent `json:"student_profile"`
+	StudentProfile TopStudent    `json:"student_profile"`
 	Stats          StatsResponse `json:"stats"`
-}
\ No newline at end of file
+}
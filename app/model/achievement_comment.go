@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ChangeOpen      = "open"
+	ChangeAddressed = "addressed"
+)
+
+const (
+	NotificationChangesRequested = "changes_requested"
+	NotificationNewComment       = "achievement_comment"
+)
+
+type AchievementComment struct {
+	ID            uuid.UUID            `json:"id"`
+	AchievementID uuid.UUID            `json:"achievement_id"`
+	ParentID      *uuid.UUID           `json:"parent_id,omitempty"`
+	AuthorID      uuid.UUID            `json:"-"`
+	AuthorName    string               `json:"author_name,omitempty"`
+	AuthorRole    string               `json:"author_role"`
+	Body          string               `json:"body"`
+	CreatedAt     time.Time            `json:"created_at"`
+	Replies       []AchievementComment `json:"replies,omitempty"`
+}
+
+type CreateCommentRequest struct {
+	Body     string `json:"body" validate:"required,max=2000"`
+	ParentID string `json:"parent_id" validate:"omitempty,uuid"`
+}
+
+// ChangeItem adalah satu butir perbaikan yang diminta peninjau.
+type ChangeItem struct {
+	ID            uuid.UUID  `json:"id"`
+	Description   string     `json:"description"`
+	Status        string     `json:"status"`
+	Response      string     `json:"response,omitempty"`
+	RequesterName string     `json:"requester_name,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	AddressedAt   *time.Time `json:"addressed_at,omitempty"`
+}
+
+type ChangeItemRequest struct {
+	Description string `json:"description" validate:"required,max=500"`
+}
+
+type RequestChangesRequest struct {
+	Note  string              `json:"note" validate:"max=1000"`
+	Items []ChangeItemRequest `json:"items" validate:"required,min=1,max=20,dive"`
+}
+
+type AddressedChangeRequest struct {
+	ItemID   string `json:"item_id" validate:"required,uuid"`
+	Response string `json:"response" validate:"max=1000"`
+}
+
+// SubmitRequest bersifat opsional; dipakai saat submit ulang untuk menandai
+// butir perbaikan yang sudah dikerjakan.
+type SubmitRequest struct {
+	Addressed []AddressedChangeRequest `json:"addressed" validate:"omitempty,max=20,dive"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Aksi yang dicatat di riwayat achievement.
+const (
+	ActionCreated   = "created"
+	ActionSubmitted = "submitted"
+	ActionVerified  = "verified"
+	ActionRejected  = "rejected"
+	ActionRevoked   = "revoked"
+
+	ActionStageApproved    = "stage_approved"
+	ActionChangesRequested = "changes_requested"
+	ActionChangesAddressed = "changes_addressed"
+
+	ActionAppealed         = "appealed"
+	ActionAppealUpheld     = "appeal_upheld"
+	ActionAppealOverturned = "appeal_overturned"
+)
+
+const NotificationAchievementRevoked = "achievement_revoked"
+
+type AchievementEvent struct {
+	ID            uuid.UUID  `json:"id"`
+	AchievementID uuid.UUID  `json:"achievement_id"`
+	Action        string     `json:"action"`
+	ActorID       *uuid.UUID `json:"-"`
+	ActorName     string     `json:"actor_name,omitempty"`
+	// OnBehalfOf diisi bila aktor bertindak sebagai penerima delegasi dosen wali.
+	OnBehalfOf     *uuid.UUID `json:"-"`
+	OnBehalfOfName string     `json:"on_behalf_of,omitempty"`
+	Note           string     `json:"note,omitempty"`
+	Points         *int       `json:"points,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
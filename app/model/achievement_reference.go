@@ -14,6 +14,7 @@ const (
 	StatusVerified  AchievementStatus = "verified"
 	StatusRejected  AchievementStatus = "rejected"
 	StatusDeleted   AchievementStatus = "deleted"
+	StatusRevoked   AchievementStatus = "revoked"
 )
 
 type AchievementReference struct {
@@ -25,6 +26,10 @@ type AchievementReference struct {
 	VerifiedAt         *time.Time        `json:"verified_at"`
 	VerifiedBy         *uuid.UUID        `json:"verified_by"`
 	RejectionNote      string            `json:"rejection_note"`
+	TeamRole           string            `json:"team_role,omitempty"`
+	PointsShare        int               `json:"points_share,omitempty"`
+	ConfirmedAt        *time.Time        `json:"confirmed_at,omitempty"`
+	PeriodLabel        string            `json:"period,omitempty"`
 	CreatedAt          time.Time         `json:"created_at"`
 	UpdatedAt          time.Time         `json:"updated_at"`
 
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TeamRoleLeader = "leader"
+	TeamRoleMember = "member"
+)
+
+const NotificationTeamInvitation = "team_invitation"
+
+type TeamMember struct {
+	AchievementID uuid.UUID  `json:"achievement_id"`
+	StudentID     uuid.UUID  `json:"student_id"`
+	NIM           string     `json:"nim"`
+	StudentName   string     `json:"student_name"`
+	Role          string     `json:"role"`
+	PointsShare   int        `json:"points_share"`
+	Points        int        `json:"points,omitempty"`
+	Confirmed     bool       `json:"confirmed"`
+	ConfirmedAt   *time.Time `json:"confirmed_at,omitempty"`
+}
+
+type TeamMemberRequest struct {
+	StudentID   string `json:"student_id" validate:"required,max=20"`
+	Role        string `json:"role" validate:"required,oneof=leader member"`
+	PointsShare int    `json:"points_share" validate:"required,gt=0,max=100"`
+}
+
+type SetTeamRequest struct {
+	Members []TeamMemberRequest `json:"members" validate:"required,min=2,max=20,dive"`
+}
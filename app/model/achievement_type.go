@@ -0,0 +1,36 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AchievementType struct {
+	Code          string          `json:"code"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	DetailsSchema json.RawMessage `json:"details_schema"`
+	IsActive      bool            `json:"is_active"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+type CreateAchievementTypeRequest struct {
+	Code          string          `json:"code" validate:"required,slug,max=50"`
+	Name          string          `json:"name" validate:"required,max=100"`
+	Description   string          `json:"description" validate:"max=1000"`
+	DetailsSchema json.RawMessage `json:"details_schema" validate:"required"`
+}
+
+type UpdateAchievementTypeRequest struct {
+	Name          *string         `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description   *string         `json:"description,omitempty" validate:"omitempty,max=1000"`
+	DetailsSchema json.RawMessage `json:"details_schema,omitempty"`
+	IsActive      *bool           `json:"is_active,omitempty"`
+}
+
+type AchievementTypeSchemaResponse struct {
+	Code   string          `json:"code"`
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
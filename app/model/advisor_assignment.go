@@ -0,0 +1,126 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdvisorAssignment struct {
+	ID             uuid.UUID  `json:"id"`
+	LecturerID     uuid.UUID  `json:"lecturer_id"`
+	NIP            string     `json:"nip"`
+	LecturerName   string     `json:"lecturer_name"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	AssignedByName string     `json:"assigned_by,omitempty"`
+	Reason         string     `json:"reason"`
+}
+
+type AssignAdvisorRequest struct {
+	LecturerID string `json:"lecturer_id"`
+	Reason     string `json:"reason" validate:"max=500"`
+}
+
+type ReassignAdviseesRequest struct {
+	ToLecturerID string `json:"to_lecturer_id" validate:"required,uuid"`
+	Reason       string `json:"reason" validate:"max=500"`
+}
+
+// AdvisorMappingItem memetakan NIM mahasiswa ke NIP dosen wali barunya.
+type AdvisorMappingItem struct {
+	NIM string `json:"nim" validate:"required"`
+	NIP string `json:"nip" validate:"required"`
+}
+
+type BulkAssignAdvisorRequest struct {
+	Items  []AdvisorMappingItem `json:"items" validate:"required,min=1,max=1000,dive"`
+	Reason string               `json:"reason" validate:"max=500"`
+}
+
+type AdvisorChange struct {
+	StudentID  uuid.UUID
+	LecturerID uuid.UUID
+}
+
+type AdvisorReassignResponse struct {
+	Reassigned int `json:"reassigned"`
+}
+
+// AdvisorAttribution merangkum keputusan verifikasi per dosen wali yang
+// membimbing mahasiswa pada saat achievement diputus.
+type AdvisorAttribution struct {
+	ID         uuid.UUID `json:"id"`
+	LecturerID string    `json:"lecturer_id"`
+	FullName   string    `json:"full_name"`
+	Department string    `json:"department"`
+	Students   int64     `json:"students"`
+	Verified   int64     `json:"verified"`
+	Rejected   int64     `json:"rejected"`
+}
+
+type AdvisorCapacityRequest struct {
+	MaxAdvisees *int `json:"max_advisees" validate:"omitempty,gt=0"`
+}
+
+// AutoAssignRequest membatasi mahasiswa tanpa dosen wali yang dibagikan;
+// filter kosong berarti semua.
+type AutoAssignRequest struct {
+	FacultyID      string `json:"faculty_id" validate:"omitempty,uuid"`
+	DepartmentID   string `json:"department_id" validate:"omitempty,uuid"`
+	StudyProgramID string `json:"study_program_id" validate:"omitempty,uuid"`
+	AcademicYear   string `json:"academic_year"`
+}
+
+// AutoAssignItem adalah satu usulan dari preview yang disetujui admin.
+type AutoAssignItem struct {
+	StudentID  string `json:"student_id" validate:"required,uuid"`
+	LecturerID string `json:"lecturer_id" validate:"required,uuid"`
+}
+
+// ApplyAutoAssignRequest memuat usulan hasil preview yang akan diterapkan.
+type ApplyAutoAssignRequest struct {
+	Proposals []AutoAssignItem `json:"proposals" validate:"required,min=1,max=1000,dive"`
+	Reason    string           `json:"reason" validate:"max=500"`
+}
+
+// AdvisorLoad adalah beban bimbingan dosen sebelum dan sesudah usulan.
+type AdvisorLoad struct {
+	ID           uuid.UUID  `json:"id"`
+	LecturerID   string     `json:"lecturer_id"`
+	FullName     string     `json:"full_name"`
+	Department   string     `json:"department"`
+	DepartmentID *uuid.UUID `json:"department_id"`
+	Advisees     int        `json:"advisees"`
+	MaxAdvisees  *int       `json:"max_advisees"`
+	Proposed     int        `json:"proposed"`
+}
+
+type AdvisorProposal struct {
+	StudentID    uuid.UUID `json:"student_id"`
+	NIM          string    `json:"nim"`
+	StudentName  string    `json:"student_name"`
+	ProgramStudy string    `json:"program_study"`
+	LecturerID   uuid.UUID `json:"lecturer_id"`
+	NIP          string    `json:"nip"`
+	LecturerName string    `json:"lecturer_name"`
+}
+
+const (
+	UnplacedNoLecturer   = "no_lecturer"
+	UnplacedCapacityFull = "capacity_full"
+)
+
+type UnplacedStudent struct {
+	StudentID    uuid.UUID `json:"student_id"`
+	NIM          string    `json:"nim"`
+	StudentName  string    `json:"student_name"`
+	ProgramStudy string    `json:"program_study"`
+	Reason       string    `json:"reason"`
+}
+
+type AutoAssignPlan struct {
+	Proposals []AdvisorProposal `json:"proposals"`
+	Unplaced  []UnplacedStudent `json:"unplaced"`
+	Loads     []AdvisorLoad     `json:"loads"`
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AppealPending    = "pending"
+	AppealUpheld     = "upheld"
+	AppealOverturned = "overturned"
+)
+
+const (
+	AppealDecisionUphold   = "uphold"
+	AppealDecisionOverturn = "overturn"
+)
+
+const (
+	NotificationAppealUpheld     = "appeal_upheld"
+	NotificationAppealOverturned = "appeal_overturned"
+)
+
+// Appeal adalah banding mahasiswa atas penolakan achievement.
+type Appeal struct {
+	ID            uuid.UUID  `json:"id"`
+	AchievementID uuid.UUID  `json:"achievement_id"`
+	Title         string     `json:"title"`
+	StudentName   string     `json:"student_name"`
+	StudentUserID uuid.UUID  `json:"-"`
+	Statement     string     `json:"statement"`
+	Status        string     `json:"status"`
+	RejectionNote string     `json:"rejection_note,omitempty"`
+	ReviewerName  string     `json:"reviewer_name,omitempty"`
+	DecisionNote  string     `json:"decision_note,omitempty"`
+	Points        *int       `json:"points,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+}
+
+type CreateAppealRequest struct {
+	Statement string `json:"statement" validate:"required,max=2000"`
+}
+
+type DecideAppealRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=uphold overturn"`
+	Note     string `json:"note" validate:"required,max=1000"`
+	Points   int    `json:"points" validate:"required_if=Decision overturn,omitempty,gt=0"`
+}
+
+type AppealReviewerRequest struct {
+	Enabled bool `json:"enabled"`
+}
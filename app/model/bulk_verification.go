@@ -0,0 +1,65 @@
+package model
+
+import "github.com/google/uuid"
+
+type BulkVerifyItem struct {
+	ID     string `json:"id" validate:"required,uuid"`
+	Points int    `json:"points" validate:"required,gt=0"`
+}
+
+type BulkVerifyRequest struct {
+	Items []BulkVerifyItem `json:"items" validate:"required,min=1,max=100,unique=ID,dive"`
+}
+
+type BulkRejectItem struct {
+	ID            string `json:"id" validate:"required,uuid"`
+	RejectionNote string `json:"rejection_note" validate:"required,max=1000"`
+}
+
+type BulkRejectRequest struct {
+	Items []BulkRejectItem `json:"items" validate:"required,min=1,max=100,unique=ID,dive"`
+}
+
+// VerificationTarget adalah keadaan achievement yang dibutuhkan untuk
+// memutus verifikasi, termasuk apakah user berwenang atas tahap saat ini.
+type VerificationTarget struct {
+	ID            uuid.UUID
+	Status        string
+	TeamRole      string
+	AdvisorUserID uuid.UUID
+	Stage         AchievementStage
+	Authorized    bool
+}
+
+// BulkDecision adalah keputusan atas satu achievement dalam permintaan massal.
+type BulkDecision struct {
+	ID            uuid.UUID
+	StagePosition int
+	StageName     string
+	Decision      string
+	Note          string
+	Points        int
+	OnBehalfOf    *uuid.UUID
+}
+
+// BulkDecisionResult adalah hasil penerapan satu BulkDecision. Status berisi
+// status achievement setelah keputusan, kosong bila Err terisi.
+type BulkDecisionResult struct {
+	ID     uuid.UUID
+	Status string
+	Err    error
+}
+
+type BulkItemResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Status  string `json:"status,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+type BulkResultResponse struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
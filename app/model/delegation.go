@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const NotificationAdvisorDelegation = "advisor_delegation"
+
+// AdvisorDelegation memberi dosen lain hak verifikasi atas mahasiswa
+// bimbingan dosen wali selama rentang tanggal tertentu (inklusif).
+type AdvisorDelegation struct {
+	ID           uuid.UUID  `json:"id"`
+	AdvisorID    uuid.UUID  `json:"advisor_id"`
+	AdvisorName  string     `json:"advisor_name"`
+	DelegateID   uuid.UUID  `json:"delegate_id"`
+	DelegateName string     `json:"delegate_name"`
+	StartsAt     string     `json:"starts_at"`
+	EndsAt       string     `json:"ends_at"`
+	Reason       string     `json:"reason,omitempty"`
+	Active       bool       `json:"active"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateDelegationRequest dipakai dosen untuk dirinya sendiri; advisor_id
+// hanya wajib diisi admin.
+type CreateDelegationRequest struct {
+	AdvisorID  string `json:"advisor_id" validate:"omitempty,uuid"`
+	DelegateID string `json:"delegate_id" validate:"required,uuid"`
+	StartsAt   string `json:"starts_at" validate:"required,datetime=2006-01-02"`
+	EndsAt     string `json:"ends_at" validate:"required,datetime=2006-01-02"`
+	Reason     string `json:"reason" validate:"max=500"`
+}
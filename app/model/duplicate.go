@@ -0,0 +1,24 @@
+package model
+
+import "github.com/google/uuid"
+
+const (
+	DuplicateReasonTitle               = "title"
+	DuplicateReasonCompetition         = "competition"
+	DuplicateReasonCertificationNumber = "certification_number"
+	DuplicateReasonISSNTitle           = "issn_title"
+	DuplicateReasonAttachment          = "attachment"
+)
+
+const NotificationDuplicateSuspected = "duplicate_suspected"
+
+// DuplicateSuspect adalah achievement lain yang diduga sama dengan achievement
+// yang disubmit, beserta alasan kecocokannya.
+type DuplicateSuspect struct {
+	AchievementID uuid.UUID `json:"achievement_id"`
+	Title         string    `json:"title"`
+	StudentName   string    `json:"student_name"`
+	Status        string    `json:"status"`
+	Reasons       []string  `json:"reasons"`
+	URL           string    `json:"url"`
+}
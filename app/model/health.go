@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// HealthResponse hanya memuat ok/fail per dependency; penyebab kegagalan
+// dicatat di log agar tidak bocor ke endpoint publik.
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type PostgresPoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+type MongoTopology struct {
+	SetName            string   `json:"set_name,omitempty" bson:"setName"`
+	Primary            string   `json:"primary,omitempty" bson:"primary"`
+	Me                 string   `json:"me,omitempty" bson:"me"`
+	Hosts              []string `json:"hosts,omitempty" bson:"hosts"`
+	IsWritablePrimary  bool     `json:"is_writable_primary" bson:"isWritablePrimary"`
+	MaxWireVersion     int      `json:"max_wire_version" bson:"maxWireVersion"`
+	SessionsInProgress int      `json:"sessions_in_progress" bson:"-"`
+	Error              string   `json:"error,omitempty" bson:"-"`
+}
+
+type StatusResponse struct {
+	Version   string            `json:"version"`
+	StartedAt time.Time         `json:"started_at"`
+	Uptime    string            `json:"uptime"`
+	Postgres  PostgresPoolStats `json:"postgres"`
+	Mongo     MongoTopology     `json:"mongo"`
+}
@@ -7,11 +7,12 @@ import (
 )
 
 type Lecturer struct {
-	ID         uuid.UUID `json:"id"`
-	UserID     uuid.UUID `json:"user_id"`
-	LecturerID string    `json:"lecturer_id"`
-	Department string    `json:"department"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	LecturerID   string     `json:"lecturer_id"`
+	Department   string     `json:"department"`
+	DepartmentID *uuid.UUID `json:"department_id"`
+	CreatedAt    time.Time  `json:"created_at"`
 
 	// Relasi
 	User     User      `json:"user,omitempty"`
@@ -19,8 +20,9 @@ type Lecturer struct {
 }
 
 type LecturerListResponse struct {
-	ID         uuid.UUID `json:"id"`
-	LecturerID string    `json:"lecturer_id"`
-	FullName   string    `json:"full_name"`
-	Department string    `json:"department"`
+	ID           uuid.UUID  `json:"id"`
+	LecturerID   string     `json:"lecturer_id"`
+	FullName     string     `json:"full_name"`
+	Department   string     `json:"department"`
+	DepartmentID *uuid.UUID `json:"department_id"`
 }
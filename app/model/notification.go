@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NotificationCertificationExpiring = "certification_expiring"
+	NotificationCertificationExpired  = "certification_expired"
+)
+
+// Notification menyimpan tipe dan parameter pesan; Message dirender sesuai
+// bahasa pembaca saat notifikasi diambil.
+type Notification struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        uuid.UUID  `json:"-"`
+	Type          string     `json:"type"`
+	AchievementID *uuid.UUID `json:"achievement_id,omitempty"`
+	Params        []string   `json:"-"`
+	Message       string     `json:"message"`
+	ReadAt        *time.Time `json:"read_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// CertificationAlert adalah sertifikasi terverifikasi yang mendekati atau
+// melewati masa berlakunya.
+type CertificationAlert struct {
+	AchievementID     uuid.UUID
+	MongoID           string
+	StudentUserID     uuid.UUID
+	Title             string
+	CertificationName string
+	ValidUntil        time.Time
+}
@@ -20,10 +20,19 @@ type SuccessMessageResponse struct {
 	Message string `json:"message"`
 }
 
-type ErrorResponse struct {
-	Success bool   `json:"success"`
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
 	Message string `json:"message"`
-	Error   string `json:"error,omitempty"`
+}
+
+type ErrorResponse struct {
+	Success bool         `json:"success"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+	TraceID string       `json:"trace_id,omitempty"`
 }
 
 type SuccessResponse[T any] struct {
@@ -7,13 +7,21 @@ import (
 )
 
 type Student struct {
-	ID           uuid.UUID  `json:"id"`
-	UserID       uuid.UUID  `json:"user_id"`
-	StudentID    string     `json:"student_id"`
-	ProgramStudy string     `json:"program_study"`
-	AcademicYear string     `json:"academic_year"`
-	AdvisorID    *uuid.UUID `json:"advisor_id"`
-	CreatedAt    time.Time  `json:"created_at"`
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	StudentID      string     `json:"student_id"`
+	ProgramStudy   string     `json:"program_study"`
+	StudyProgramID *uuid.UUID `json:"study_program_id"`
+	AcademicYear   string     `json:"academic_year"`
+	AdvisorID      *uuid.UUID `json:"advisor_id"`
+	Status         string     `json:"status"`
+	StatusSince    string     `json:"status_since"`
+	SKPFrozenAt    *time.Time `json:"skp_frozen_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// DepartmentID adalah jurusan dari program studi, hanya diisi oleh query
+	// yang membutuhkannya.
+	DepartmentID *uuid.UUID `json:"-"`
 
 	// Relasi
 	User         User                   `json:"user,omitempty"`
@@ -22,18 +30,24 @@ type Student struct {
 }
 
 type StudentListResponse struct {
-	ID           uuid.UUID `json:"id"`
-	StudentID    string    `json:"student_id"`
-	FullName     string    `json:"full_name"`
-	ProgramStudy string    `json:"program_study"`
-	AdvisorName  string    `json:"advisor_name"`
+	ID             uuid.UUID  `json:"id"`
+	StudentID      string     `json:"student_id"`
+	FullName       string     `json:"full_name"`
+	ProgramStudy   string     `json:"program_study"`
+	StudyProgramID *uuid.UUID `json:"study_program_id"`
+	Status         string     `json:"status"`
+	AdvisorName    string     `json:"advisor_name"`
 }
 
 type StudentDetailResponse struct {
-	ID           uuid.UUID `json:"id"`
-	StudentID    string    `json:"student_id"`
-	FullName     string    `json:"full_name"`
-	Email        string    `json:"email"`
-	ProgramStudy string    `json:"program_study"`
-	AdvisorName  string    `json:"advisor_name"`
+	ID             uuid.UUID  `json:"id"`
+	StudentID      string     `json:"student_id"`
+	FullName       string     `json:"full_name"`
+	Email          string     `json:"email"`
+	ProgramStudy   string     `json:"program_study"`
+	StudyProgramID *uuid.UUID `json:"study_program_id"`
+	Status         string     `json:"status"`
+	StatusSince    string     `json:"status_since"`
+	SKPFrozenAt    *time.Time `json:"skp_frozen_at,omitempty"`
+	AdvisorName    string     `json:"advisor_name"`
 }
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status studi mahasiswa. Hanya mahasiswa aktif yang boleh membuat dan
+// mengajukan achievement.
+const (
+	StudentActive     = "active"
+	StudentOnLeave    = "on_leave"
+	StudentSuspended  = "suspended"
+	StudentGraduated  = "graduated"
+	StudentDroppedOut = "dropped_out"
+)
+
+// StudentStatusTransitions adalah perubahan status yang diizinkan. Lulus
+// bersifat final karena rekam SKP dibekukan.
+var StudentStatusTransitions = map[string][]string{
+	StudentActive:     {StudentOnLeave, StudentSuspended, StudentGraduated, StudentDroppedOut},
+	StudentOnLeave:    {StudentActive, StudentDroppedOut},
+	StudentSuspended:  {StudentActive, StudentDroppedOut},
+	StudentDroppedOut: {StudentActive},
+	StudentGraduated:  {},
+}
+
+// StudentStatusChange adalah satu baris riwayat status mahasiswa.
+type StudentStatusChange struct {
+	ID            uuid.UUID  `json:"id"`
+	StudentID     uuid.UUID  `json:"student_id"`
+	Status        string     `json:"status"`
+	EffectiveDate string     `json:"effective_date"`
+	Reason        string     `json:"reason,omitempty"`
+	ChangedBy     *uuid.UUID `json:"changed_by,omitempty"`
+	ChangedByName string     `json:"changed_by_name,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ChangeStudentStatusRequest mengubah status; effective_date kosong berarti
+// hari ini.
+type ChangeStudentStatusRequest struct {
+	Status        string `json:"status" validate:"required,oneof=active on_leave suspended graduated dropped_out"`
+	EffectiveDate string `json:"effective_date" validate:"omitempty,datetime=2006-01-02"`
+	Reason        string `json:"reason" validate:"required,max=500"`
+}
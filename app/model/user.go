@@ -26,8 +26,8 @@ type User struct {
 }
 
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
 type CreateUserRequest struct {
@@ -40,19 +40,25 @@ type CreateUserRequest struct {
 	Lecturer *CreateLecturerData `json:"lecturer,omitempty"`
 }
 
+// CreateStudentData merujuk program studi lewat study_program_id;
+// program_study berisi nama atau kode program studi untuk klien lama.
 type CreateStudentData struct {
-	ProgramStudy string `json:"program_study" validate:"required"`
-	AcademicYear string `json:"academic_year" validate:"required"`
+	StudyProgramID string `json:"study_program_id" validate:"omitempty,uuid"`
+	ProgramStudy   string `json:"program_study"`
+	AcademicYear   string `json:"academic_year" validate:"required"`
 }
 
+// CreateLecturerData merujuk jurusan lewat department_id; department berisi
+// nama atau kode jurusan untuk klien lama.
 type CreateLecturerData struct {
-	Department string `json:"department" validate:"required"`
+	DepartmentID string `json:"department_id" validate:"omitempty,uuid"`
+	Department   string `json:"department"`
 }
 
 type UpdateUserRequest struct {
-	Username string `json:"username"`
+	Username string `json:"username" validate:"omitempty,max=100"`
 	Email    string `json:"email" validate:"omitempty,email"`
-	FullName string `json:"full_name"`
+	FullName string `json:"full_name" validate:"omitempty,max=100"`
 	Password string `json:"password" validate:"omitempty,min=6"`
 }
 
@@ -95,11 +101,11 @@ type BlacklistedToken struct {
 }
 
 type RefreshTokenRequest struct {
-	RefreshToken string `json:"refreshToken" binding:"required"`
+	RefreshToken string `json:"refreshToken" validate:"required"`
 }
 
 type RefreshTokenResponse struct {
-	Token string `json:"token" binding:"required"`
+	Token string `json:"token"`
 }
 
 type ChangeRoleRequest struct {
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pihak yang menyetujui satu tahap verifikasi.
+const (
+	ApproverAdvisor        = "advisor"
+	ApproverDepartmentHead = "department_head"
+	ApproverAdmin          = "admin"
+)
+
+const (
+	StageApproved         = "approved"
+	StageRejected         = "rejected"
+	StageChangesRequested = "changes_requested"
+)
+
+// DefaultStageName adalah nama tahap dosen wali yang dipakai bila tidak ada
+// rantai verifikasi yang cocok; SLA-nya diatur lewat VERIFICATION_SLA.
+const DefaultStageName = "Dosen Wali"
+
+type VerificationChain struct {
+	ID              uuid.UUID           `json:"id"`
+	Name            string              `json:"name"`
+	AchievementType string              `json:"achievement_type"`
+	LevelField      string              `json:"level_field,omitempty"`
+	LevelValue      string              `json:"level_value,omitempty"`
+	IsActive        bool                `json:"is_active"`
+	Stages          []VerificationStage `json:"stages"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
+
+type VerificationStage struct {
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Approver string `json:"approver"`
+	SLAHours int    `json:"sla_hours"`
+}
+
+type VerificationStageRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Approver string `json:"approver" validate:"required,oneof=advisor department_head admin"`
+	SLAHours int    `json:"sla_hours" validate:"required,gt=0,lte=8760"`
+}
+
+type CreateVerificationChainRequest struct {
+	Name            string                     `json:"name" validate:"required,max=100"`
+	AchievementType string                     `json:"achievement_type" validate:"required,max=50"`
+	LevelField      string                     `json:"level_field" validate:"required_with=LevelValue,max=50"`
+	LevelValue      string                     `json:"level_value" validate:"required_with=LevelField,max=100"`
+	Stages          []VerificationStageRequest `json:"stages" validate:"required,min=1,max=5,dive"`
+}
+
+type UpdateVerificationChainRequest struct {
+	Name     *string                    `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Stages   []VerificationStageRequest `json:"stages,omitempty" validate:"omitempty,min=1,max=5,dive"`
+	IsActive *bool                      `json:"is_active,omitempty"`
+}
+
+// AchievementStage adalah tahap verifikasi yang sedang atau sudah dijalani
+// sebuah achievement. Round hanya diisi untuk tahap dari pengajuan sebelumnya.
+type AchievementStage struct {
+	Round       int        `json:"round,omitempty"`
+	Position    int        `json:"position"`
+	ChainName   string     `json:"chain_name,omitempty"`
+	Name        string     `json:"name"`
+	Approver    string     `json:"approver"`
+	SLAHours    int        `json:"sla_hours"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Overdue     bool       `json:"overdue"`
+	Decision    string     `json:"decision,omitempty"`
+	DeciderName string     `json:"decider_name,omitempty"`
+	Note        string     `json:"note,omitempty"`
+	Points      *int       `json:"points,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+}
+
+type DepartmentHeadRequest struct {
+	Enabled bool `json:"enabled"`
+}
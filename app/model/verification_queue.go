@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const NotificationVerificationOverdue = "verification_overdue"
+
+// QueueItem adalah achievement yang menunggu keputusan user pada tahap
+// verifikasi saat ini.
+type QueueItem struct {
+	AchievementID  uuid.UUID `json:"achievement_id"`
+	Title          string    `json:"title"`
+	NIM            string    `json:"nim"`
+	StudentName    string    `json:"student_name"`
+	StageName      string    `json:"stage_name"`
+	SubmittedAt    time.Time `json:"submitted_at"`
+	StageStartedAt time.Time `json:"stage_started_at"`
+	DueAt          time.Time `json:"due_at"`
+	DaysPending    int       `json:"days_pending"`
+	Overdue        bool      `json:"overdue"`
+}
+
+// OverdueStage adalah tahap verifikasi yang melewati SLA dan belum
+// dieskalasi ke admin.
+type OverdueStage struct {
+	AchievementID uuid.UUID
+	MongoID       string
+	Position      int
+	Title         string
+	StageName     string
+	StudentName   string
+	AdvisorName   string
+	DueAt         time.Time
+}
+
+// LecturerSLAStats merangkum ketepatan waktu dosen dalam memutus tahap
+// verifikasi. Tahap yang sudah diputus dihitung untuk dosen yang memutusnya,
+// tahap dosen wali yang masih menunggu untuk dosen wali mahasiswa.
+type LecturerSLAStats struct {
+	ID               uuid.UUID `json:"id"`
+	LecturerID       string    `json:"lecturer_id"`
+	FullName         string    `json:"full_name"`
+	Department       string    `json:"department"`
+	Decided          int       `json:"decided"`
+	DecidedWithinSLA int       `json:"decided_within_sla"`
+	ComplianceRate   float64   `json:"compliance_rate"`
+	AvgHoursToDecide float64   `json:"avg_hours_to_decide"`
+	Pending          int       `json:"pending"`
+	Overdue          int       `json:"overdue"`
+}
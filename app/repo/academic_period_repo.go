@@ -0,0 +1,382 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"fiber/skp/helper"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// periodForDate memilih periode yang memuat tanggal %s; periode tidak boleh
+// beririsan sehingga paling banyak satu yang cocok.
+const periodForDate = `(SELECT p.id FROM academic_periods p WHERE %s BETWEEN p.starts_on AND p.ends_on LIMIT 1)`
+
+type AcademicPeriodRepository interface {
+	FindAll(ctx context.Context) ([]model.AcademicPeriod, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.AcademicPeriod, error)
+	HasOverlap(ctx context.Context, startsOn, endsOn string, excludeID *uuid.UUID) (bool, error)
+	Create(ctx context.Context, period *model.AcademicPeriod) error
+	Update(ctx context.Context, period *model.AcademicPeriod) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindWindows(ctx context.Context, periodID uuid.UUID) ([]model.SubmissionWindow, error)
+	CreateWindow(ctx context.Context, window *model.SubmissionWindow, createdBy uuid.UUID) error
+	DeleteWindow(ctx context.Context, periodID, windowID uuid.UUID) error
+	FindExemptions(ctx context.Context, activeOnly bool) ([]model.SubmissionExemption, error)
+	CreateExemption(ctx context.Context, exemption *model.SubmissionExemption, grantedBy uuid.UUID) error
+	DeleteExemption(ctx context.Context, id uuid.UUID) error
+	FindSubmissionGate(ctx context.Context, achievementID uuid.UUID, now time.Time) (*model.SubmissionGate, error)
+}
+
+type AcademicPeriodRepo struct {
+	DB *sql.DB
+}
+
+func NewAcademicPeriodRepo(db *sql.DB) *AcademicPeriodRepo {
+	return &AcademicPeriodRepo{DB: db}
+}
+
+const academicPeriodSelect = `SELECT id, year, semester, starts_on, ends_on, created_at, updated_at FROM academic_periods`
+
+func (r *AcademicPeriodRepo) FindAll(ctx context.Context) ([]model.AcademicPeriod, error) {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.FindAll")
+	defer finish()
+
+	rows, err := r.DB.QueryContext(ctx, academicPeriodSelect+` ORDER BY starts_on DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	periods := []model.AcademicPeriod{}
+	for rows.Next() {
+		p, err := scanAcademicPeriod(rows)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, *p)
+	}
+	return periods, rows.Err()
+}
+
+func (r *AcademicPeriodRepo) FindByID(ctx context.Context, id uuid.UUID) (*model.AcademicPeriod, error) {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.FindByID")
+	defer finish()
+
+	return scanAcademicPeriod(r.DB.QueryRowContext(ctx, academicPeriodSelect+` WHERE id = $1`, id))
+}
+
+// HasOverlap memeriksa apakah rentang tanggal beririsan dengan periode lain
+// selain excludeID.
+func (r *AcademicPeriodRepo) HasOverlap(ctx context.Context, startsOn, endsOn string, excludeID *uuid.UUID) (bool, error) {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.HasOverlap")
+	defer finish()
+
+	query := `SELECT COUNT(*) FROM academic_periods WHERE starts_on <= $2 AND ends_on >= $1`
+	args := []interface{}{startsOn, endsOn}
+	if excludeID != nil {
+		args = append(args, *excludeID)
+		query += fmt.Sprintf(` AND id != $%d`, len(args))
+	}
+	var count int64
+	if err := r.DB.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Create menyimpan periode lalu menandai ulang achievement yang tanggal
+// kegiatannya masuk rentang periode tersebut.
+func (r *AcademicPeriodRepo) Create(ctx context.Context, period *model.AcademicPeriod) error {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.Create")
+	defer finish()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO academic_periods (year, semester, starts_on, ends_on, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING id`,
+		period.Year, period.Semester, period.StartsOn, period.EndsOn, time.Now()).Scan(&id)
+	if err != nil {
+		return err
+	}
+	if err := retagPeriods(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	created, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	*period = *created
+	return nil
+}
+
+// Update menyimpan perubahan periode; rentang yang bergeser ikut mengubah
+// penandaan achievement.
+func (r *AcademicPeriodRepo) Update(ctx context.Context, period *model.AcademicPeriod) error {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.Update")
+	defer finish()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE academic_periods SET year = $1, semester = $2, starts_on = $3, ends_on = $4, updated_at = $5
+		WHERE id = $6`,
+		period.Year, period.Semester, period.StartsOn, period.EndsOn, time.Now(), period.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	if err := retagPeriods(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	updated, err := r.FindByID(ctx, period.ID)
+	if err != nil {
+		return err
+	}
+	*period = *updated
+	return nil
+}
+
+// Delete menghapus periode beserta jendela dan pengecualiannya; achievement
+// yang ditandai periode ini menjadi tanpa periode.
+func (r *AcademicPeriodRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.Delete")
+	defer finish()
+
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM academic_periods WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *AcademicPeriodRepo) FindWindows(ctx context.Context, periodID uuid.UUID) ([]model.SubmissionWindow, error) {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.FindWindows")
+	defer finish()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, period_id, opens_at, closes_at, note, $2 BETWEEN opens_at AND closes_at, created_at
+		FROM submission_windows
+		WHERE period_id = $1
+		ORDER BY opens_at`, periodID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	windows := []model.SubmissionWindow{}
+	for rows.Next() {
+		var w model.SubmissionWindow
+		if err := rows.Scan(&w.ID, &w.PeriodID, &w.OpensAt, &w.ClosesAt, &w.Note, &w.Open, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, rows.Err()
+}
+
+func (r *AcademicPeriodRepo) CreateWindow(ctx context.Context, window *model.SubmissionWindow, createdBy uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.CreateWindow")
+	defer finish()
+
+	now := time.Now()
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO submission_windows (period_id, opens_at, closes_at, note, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		window.PeriodID, window.OpensAt, window.ClosesAt, window.Note, createdBy, now).Scan(&window.ID)
+	if err != nil {
+		return err
+	}
+	window.Open = !now.Before(window.OpensAt) && !now.After(window.ClosesAt)
+	window.CreatedAt = now
+	return nil
+}
+
+// DeleteWindow menghapus jendela milik periode; sql.ErrNoRows bila tidak
+// ditemukan.
+func (r *AcademicPeriodRepo) DeleteWindow(ctx context.Context, periodID, windowID uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.DeleteWindow")
+	defer finish()
+
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM submission_windows WHERE id = $1 AND period_id = $2`, windowID, periodID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+const submissionExemptionSelect = `
+	SELECT e.id, e.student_id, s.student_id, u.full_name, e.period_id, e.expires_at, e.reason,
+		e.expires_at > $1, e.created_at
+	FROM submission_exemptions e
+	JOIN students s ON s.id = e.student_id
+	JOIN users u ON u.id = s.user_id`
+
+// FindExemptions memuat pengecualian, terbaru lebih dulu.
+func (r *AcademicPeriodRepo) FindExemptions(ctx context.Context, activeOnly bool) ([]model.SubmissionExemption, error) {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.FindExemptions")
+	defer finish()
+
+	query := submissionExemptionSelect
+	if activeOnly {
+		query += ` WHERE e.expires_at > $1`
+	}
+	rows, err := r.DB.QueryContext(ctx, query+` ORDER BY e.created_at DESC`, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exemptions := []model.SubmissionExemption{}
+	for rows.Next() {
+		e, err := scanSubmissionExemption(rows)
+		if err != nil {
+			return nil, err
+		}
+		exemptions = append(exemptions, *e)
+	}
+	return exemptions, rows.Err()
+}
+
+func (r *AcademicPeriodRepo) CreateExemption(ctx context.Context, exemption *model.SubmissionExemption, grantedBy uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.CreateExemption")
+	defer finish()
+
+	var id uuid.UUID
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO submission_exemptions (student_id, period_id, expires_at, reason, granted_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		exemption.StudentID, exemption.PeriodID, exemption.ExpiresAt, exemption.Reason, grantedBy, time.Now()).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	created, err := scanSubmissionExemption(r.DB.QueryRowContext(ctx, submissionExemptionSelect+` WHERE e.id = $2`, time.Now(), id))
+	if err != nil {
+		return err
+	}
+	*exemption = *created
+	return nil
+}
+
+// DeleteExemption mencabut pengecualian; sql.ErrNoRows bila tidak ditemukan.
+func (r *AcademicPeriodRepo) DeleteExemption(ctx context.Context, id uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.DeleteExemption")
+	defer finish()
+
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM submission_exemptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// FindSubmissionGate memuat aturan jendela untuk periode achievement dan
+// pengecualian milik pengajunya pada waktu now. Achievement tanpa periode
+// tidak punya jendela.
+func (r *AcademicPeriodRepo) FindSubmissionGate(ctx context.Context, achievementID uuid.UUID, now time.Time) (*model.SubmissionGate, error) {
+	ctx, finish := startOp(ctx, "AcademicPeriodRepo.FindSubmissionGate")
+	defer finish()
+
+	query := `
+		SELECT p.year, p.semester,
+			EXISTS (SELECT 1 FROM submission_windows w WHERE w.period_id = p.id),
+			EXISTS (SELECT 1 FROM submission_windows w WHERE w.period_id = p.id AND $2 BETWEEN w.opens_at AND w.closes_at),
+			EXISTS (
+				SELECT 1 FROM submission_exemptions e
+				WHERE e.student_id = ar.student_id AND (e.period_id IS NULL OR e.period_id = p.id) AND e.expires_at > $2
+			),
+			(SELECT MIN(w.opens_at) FROM submission_windows w WHERE w.period_id = p.id AND w.opens_at > $2)
+		FROM achievement_references ar
+		LEFT JOIN academic_periods p ON p.id = ar.period_id
+		WHERE ar.id = $1`
+
+	var gate model.SubmissionGate
+	var year, semester sql.NullString
+	var nextOpensAt sql.NullTime
+	err := r.DB.QueryRowContext(ctx, query, achievementID, now).Scan(&year, &semester,
+		&gate.HasWindows, &gate.Open, &gate.Exempted, &nextOpensAt)
+	if err != nil {
+		return nil, err
+	}
+	if year.Valid {
+		gate.PeriodLabel = model.PeriodLabel(year.String, semester.String)
+	}
+	if nextOpensAt.Valid {
+		gate.NextOpensAt = &nextOpensAt.Time
+	}
+	return &gate, nil
+}
+
+// retagPeriods menyelaraskan period_id semua achievement dengan rentang
+// periode saat ini.
+func retagPeriods(ctx context.Context, db execer) error {
+	_, err := db.ExecContext(ctx, `
+		UPDATE achievement_references ar SET period_id = t.period_id
+		FROM (
+			SELECT r.id, `+fmt.Sprintf(periodForDate, "r.event_date")+` AS period_id
+			FROM achievement_references r
+		) t
+		WHERE t.id = ar.id AND ar.period_id IS DISTINCT FROM t.period_id`)
+	return err
+}
+
+func scanAcademicPeriod(row rowScanner) (*model.AcademicPeriod, error) {
+	var p model.AcademicPeriod
+	var startsOn, endsOn time.Time
+	if err := row.Scan(&p.ID, &p.Year, &p.Semester, &startsOn, &endsOn, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	p.StartsOn = startsOn.Format(helper.DateLayout)
+	p.EndsOn = endsOn.Format(helper.DateLayout)
+	p.Label = model.PeriodLabel(p.Year, p.Semester)
+	return &p, nil
+}
+
+func scanSubmissionExemption(row rowScanner) (*model.SubmissionExemption, error) {
+	var e model.SubmissionExemption
+	var periodID uuid.NullUUID
+	err := row.Scan(&e.ID, &e.StudentID, &e.NIM, &e.StudentName, &periodID, &e.ExpiresAt, &e.Reason, &e.Active, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if periodID.Valid {
+		e.PeriodID = &periodID.UUID
+	}
+	return &e, nil
+}
@@ -0,0 +1,302 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AcademicUnitRepository interface {
+	FindAll(ctx context.Context, kind string, parentID *uuid.UUID) ([]model.AcademicUnit, error)
+	FindByID(ctx context.Context, kind string, id uuid.UUID) (*model.AcademicUnit, error)
+	FindByName(ctx context.Context, kind string, name string) (*model.AcademicUnit, error)
+	Create(ctx context.Context, kind string, unit *model.AcademicUnit) error
+	Update(ctx context.Context, kind string, unit *model.AcademicUnit) error
+	Delete(ctx context.Context, kind string, id uuid.UUID) error
+	Merge(ctx context.Context, kind string, sourceID, targetID uuid.UUID) error
+}
+
+type AcademicUnitRepo struct {
+	DB *sql.DB
+}
+
+func NewAcademicUnitRepo(db *sql.DB) *AcademicUnitRepo {
+	return &AcademicUnitRepo{DB: db}
+}
+
+// unitRef adalah kolom yang merujuk sebuah unit. nameColumn, bila ada, adalah
+// salinan nama unit yang ikut diperbarui.
+type unitRef struct {
+	table, column, nameColumn string
+}
+
+type unitTable struct {
+	table, parentTable, parentColumn string
+	refs                             []unitRef
+}
+
+var academicUnitTables = map[string]unitTable{
+	model.UnitFaculty: {
+		table: "faculties",
+		refs:  []unitRef{{"departments", "faculty_id", ""}},
+	},
+	model.UnitDepartment: {
+		table: "departments", parentTable: "faculties", parentColumn: "faculty_id",
+		refs: []unitRef{{"study_programs", "department_id", ""}, {"lecturers", "department_id", "department"}},
+	},
+	model.UnitStudyProgram: {
+		table: "study_programs", parentTable: "departments", parentColumn: "department_id",
+		refs: []unitRef{{"students", "study_program_id", "program_study"}},
+	},
+}
+
+func (t unitTable) selectQuery() string {
+	if t.parentTable == "" {
+		return `SELECT u.id, u.code, u.name, NULL::uuid, '', u.created_at, u.updated_at FROM ` + t.table + ` u`
+	}
+	return `SELECT u.id, u.code, u.name, u.` + t.parentColumn + `, p.name, u.created_at, u.updated_at
+		FROM ` + t.table + ` u JOIN ` + t.parentTable + ` p ON p.id = u.` + t.parentColumn
+}
+
+func (r *AcademicUnitRepo) FindAll(ctx context.Context, kind string, parentID *uuid.UUID) ([]model.AcademicUnit, error) {
+	ctx, finish := startOp(ctx, "AcademicUnitRepo.FindAll")
+	defer finish()
+
+	t := academicUnitTables[kind]
+	query := t.selectQuery()
+	var args []interface{}
+	if parentID != nil && t.parentTable != "" {
+		query += ` WHERE u.` + t.parentColumn + ` = $1`
+		args = append(args, *parentID)
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query+` ORDER BY u.name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	units := []model.AcademicUnit{}
+	for rows.Next() {
+		u, err := scanAcademicUnit(rows)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, *u)
+	}
+	return units, rows.Err()
+}
+
+func (r *AcademicUnitRepo) FindByID(ctx context.Context, kind string, id uuid.UUID) (*model.AcademicUnit, error) {
+	ctx, finish := startOp(ctx, "AcademicUnitRepo.FindByID")
+	defer finish()
+
+	return scanAcademicUnit(r.DB.QueryRowContext(ctx, academicUnitTables[kind].selectQuery()+` WHERE u.id = $1`, id))
+}
+
+// FindByName mencocokkan kode atau nama unit tanpa membedakan huruf besar/kecil
+// dan spasi berlebih, untuk klien yang masih mengirim teks bebas.
+func (r *AcademicUnitRepo) FindByName(ctx context.Context, kind string, name string) (*model.AcademicUnit, error) {
+	ctx, finish := startOp(ctx, "AcademicUnitRepo.FindByName")
+	defer finish()
+
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	query := academicUnitTables[kind].selectQuery() + ` WHERE LOWER(u.name) = $1 OR LOWER(u.code) = $1`
+	return scanAcademicUnit(r.DB.QueryRowContext(ctx, query, name))
+}
+
+func (r *AcademicUnitRepo) Create(ctx context.Context, kind string, unit *model.AcademicUnit) error {
+	ctx, finish := startOp(ctx, "AcademicUnitRepo.Create")
+	defer finish()
+
+	t := academicUnitTables[kind]
+	now := time.Now()
+	var id uuid.UUID
+	var err error
+	if t.parentTable == "" {
+		err = r.DB.QueryRowContext(ctx,
+			`INSERT INTO `+t.table+` (code, name, created_at, updated_at) VALUES ($1, $2, $3, $3) RETURNING id`,
+			unit.Code, unit.Name, now).Scan(&id)
+	} else {
+		err = r.DB.QueryRowContext(ctx,
+			`INSERT INTO `+t.table+` (`+t.parentColumn+`, code, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $4) RETURNING id`,
+			unit.ParentID, unit.Code, unit.Name, now).Scan(&id)
+	}
+	if err != nil {
+		return err
+	}
+
+	created, err := r.FindByID(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	*unit = *created
+	return nil
+}
+
+// Update menyimpan kode, nama, dan induk unit, lalu menyelaraskan salinan nama
+// di tabel yang merujuknya.
+func (r *AcademicUnitRepo) Update(ctx context.Context, kind string, unit *model.AcademicUnit) error {
+	ctx, finish := startOp(ctx, "AcademicUnitRepo.Update")
+	defer finish()
+
+	t := academicUnitTables[kind]
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if t.parentTable == "" {
+		result, err = tx.ExecContext(ctx,
+			`UPDATE `+t.table+` SET code = $1, name = $2, updated_at = $3 WHERE id = $4`,
+			unit.Code, unit.Name, time.Now(), unit.ID)
+	} else {
+		result, err = tx.ExecContext(ctx,
+			`UPDATE `+t.table+` SET code = $1, name = $2, `+t.parentColumn+` = $3, updated_at = $4 WHERE id = $5`,
+			unit.Code, unit.Name, unit.ParentID, time.Now(), unit.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	for _, ref := range t.refs {
+		if ref.nameColumn == "" {
+			continue
+		}
+		query := fmt.Sprintf(`UPDATE %s SET %s = $1 WHERE %s = $2`, ref.table, ref.nameColumn, ref.column)
+		if _, err := tx.ExecContext(ctx, query, unit.Name, unit.ID); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	updated, err := r.FindByID(ctx, kind, unit.ID)
+	if err != nil {
+		return err
+	}
+	*unit = *updated
+	return nil
+}
+
+// Delete menghapus unit; gagal dengan foreign key violation bila masih dirujuk.
+func (r *AcademicUnitRepo) Delete(ctx context.Context, kind string, id uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AcademicUnitRepo.Delete")
+	defer finish()
+
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM `+academicUnitTables[kind].table+` WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Merge memindahkan semua rujukan sourceID ke targetID lalu menghapus
+// sourceID, misalnya untuk menyatukan "TI" dengan "Teknik Informatika".
+// sql.ErrNoRows bila salah satu unit tidak ditemukan.
+func (r *AcademicUnitRepo) Merge(ctx context.Context, kind string, sourceID, targetID uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AcademicUnitRepo.Merge")
+	defer finish()
+
+	t := academicUnitTables[kind]
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var targetName string
+	err = tx.QueryRowContext(ctx, `SELECT name FROM `+t.table+` WHERE id = $1 FOR UPDATE`, targetID).Scan(&targetName)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range t.refs {
+		query := fmt.Sprintf(`UPDATE %s SET %s = $1 WHERE %s = $2`, ref.table, ref.column, ref.column)
+		args := []interface{}{targetID, sourceID}
+		if ref.nameColumn != "" {
+			query = fmt.Sprintf(`UPDATE %s SET %s = $1, %s = $3 WHERE %s = $2`, ref.table, ref.column, ref.nameColumn, ref.column)
+			args = append(args, targetName)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM `+t.table+` WHERE id = $1`, sourceID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func scanAcademicUnit(row rowScanner) (*model.AcademicUnit, error) {
+	var u model.AcademicUnit
+	var parentID uuid.NullUUID
+	if err := row.Scan(&u.ID, &u.Code, &u.Name, &parentID, &u.ParentName, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		u.ParentID = &parentID.UUID
+	}
+	return &u, nil
+}
+
+// studentUnitScope menghasilkan kondisi filter unit atas students alias s
+// dengan placeholder mulai dari $next.
+func studentUnitScope(f model.AcademicUnitFilter, next int) (string, []interface{}) {
+	var scope string
+	var args []interface{}
+	if f.StudyProgramID != nil {
+		scope += fmt.Sprintf(" AND s.study_program_id = $%d", next+len(args))
+		args = append(args, *f.StudyProgramID)
+	}
+	if f.DepartmentID != nil {
+		scope += fmt.Sprintf(" AND s.study_program_id IN (SELECT id FROM study_programs WHERE department_id = $%d)", next+len(args))
+		args = append(args, *f.DepartmentID)
+	}
+	if f.FacultyID != nil {
+		scope += fmt.Sprintf(` AND s.study_program_id IN (
+			SELECT sp.id FROM study_programs sp JOIN departments d ON d.id = sp.department_id
+			WHERE d.faculty_id = $%d)`, next+len(args))
+		args = append(args, *f.FacultyID)
+	}
+	return scope, args
+}
+
+// lecturerUnitScope seperti studentUnitScope untuk lecturers alias l; filter
+// program studi dipetakan ke jurusan program studi tersebut.
+func lecturerUnitScope(f model.AcademicUnitFilter, next int) (string, []interface{}) {
+	var scope string
+	var args []interface{}
+	if f.StudyProgramID != nil {
+		scope += fmt.Sprintf(" AND l.department_id = (SELECT department_id FROM study_programs WHERE id = $%d)", next+len(args))
+		args = append(args, *f.StudyProgramID)
+	}
+	if f.DepartmentID != nil {
+		scope += fmt.Sprintf(" AND l.department_id = $%d", next+len(args))
+		args = append(args, *f.DepartmentID)
+	}
+	if f.FacultyID != nil {
+		scope += fmt.Sprintf(" AND l.department_id IN (SELECT id FROM departments WHERE faculty_id = $%d)", next+len(args))
+		args = append(args, *f.FacultyID)
+	}
+	return scope, args
+}
@@ -0,0 +1,393 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fiber/skp/app/model"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// appealReviewerScope membatasi banding untuk dosen peninjau: dosen sejurusan
+// dengan dosen wali mahasiswa, bukan dosen wali itu sendiri, dan bukan dosen
+// yang menolak. %[1]s adalah placeholder user_id peninjau.
+const appealReviewerScope = `
+	EXISTS (
+		SELECT 1 FROM students s
+		JOIN lecturers adv ON adv.id = s.advisor_id
+		JOIN lecturers rv ON rv.department_id = adv.department_id AND rv.id != adv.id
+		WHERE s.id = ar.student_id AND rv.user_id = %[1]s AND rv.is_appeal_reviewer
+	) AND ap.original_verifier_id IS DISTINCT FROM %[1]s`
+
+const appealSelect = `
+	SELECT ap.id, ap.achievement_id, ar.mongo_achievement_id, su.full_name, ap.student_user_id,
+		ap.statement, ap.status, ap.rejection_note, ru.full_name, ap.decision_note, ap.points,
+		ap.created_at, ap.decided_at
+	FROM achievement_appeals ap
+	JOIN achievement_references ar ON ar.id = ap.achievement_id
+	JOIN users su ON su.id = ap.student_user_id
+	LEFT JOIN users ru ON ru.id = ap.reviewer_id`
+
+// CreateAppeal mengajukan banding atas achievement yang ditolak. Satu penolakan
+// hanya dapat dibanding sekali; sql.ErrNoRows bila banding tidak diizinkan.
+func (r *AchievementRepo) CreateAppeal(ctx context.Context, id uuid.UUID, studentUserID uuid.UUID, statement string) (*model.Appeal, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.CreateAppeal")
+	defer finish()
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var verifierID uuid.NullUUID
+	var rejectedAt sql.NullTime
+	var rejectionNote sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT verified_by, verified_at, rejection_note
+		FROM achievement_references
+		WHERE id = $1 AND status = $2
+		FOR UPDATE`, id, model.StatusRejected).Scan(&verifierID, &rejectedAt, &rejectionNote)
+	if err != nil {
+		return nil, err
+	}
+
+	var appealed bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM achievement_appeals
+			WHERE achievement_id = $1 AND (status = $2 OR created_at >= $3)
+		)`, id, model.AppealPending, rejectedAt.Time).Scan(&appealed)
+	if err != nil {
+		return nil, err
+	}
+	if appealed {
+		return nil, sql.ErrNoRows
+	}
+
+	appeal := &model.Appeal{
+		AchievementID: id,
+		StudentUserID: studentUserID,
+		Statement:     statement,
+		Status:        model.AppealPending,
+		RejectionNote: rejectionNote.String,
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO achievement_appeals (achievement_id, student_user_id, statement, rejection_note, original_verifier_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`,
+		id, studentUserID, statement, appeal.RejectionNote, verifierID, time.Now()).Scan(&appeal.ID, &appeal.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertEvent(ctx, tx, model.AchievementEvent{
+		AchievementID: id,
+		Action:        model.ActionAppealed,
+		ActorID:       &studentUserID,
+		Note:          statement,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return appeal, nil
+}
+
+func (r *AchievementRepo) HasPendingAppeal(ctx context.Context, id uuid.UUID) (bool, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.HasPendingAppeal")
+	defer finish()
+
+	var pending bool
+	query := `SELECT EXISTS (SELECT 1 FROM achievement_appeals WHERE achievement_id = $1 AND status = $2)`
+	err := r.pgDB.QueryRowContext(ctx, query, id, model.AppealPending).Scan(&pending)
+	return pending, err
+}
+
+// FindAppeals mengembalikan antrean banding. Admin melihat semua banding,
+// dosen hanya banding yang boleh ia tinjau. Status kosong berarti semua status.
+func (r *AchievementRepo) FindAppeals(ctx context.Context, role string, userID uuid.UUID, status string, page, limit int) ([]model.Appeal, int64, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindAppeals")
+	defer finish()
+
+	where := ` WHERE ar.status != $1`
+	args := []interface{}{model.StatusDeleted}
+	if status != "" {
+		args = append(args, status)
+		where += fmt.Sprintf(` AND ap.status = $%d`, len(args))
+	}
+	if role != model.RoleAdmin {
+		args = append(args, userID)
+		where += ` AND` + fmt.Sprintf(appealReviewerScope, fmt.Sprintf("$%d", len(args)))
+	}
+
+	var total int64
+	countQuery := `
+		SELECT COUNT(*)
+		FROM achievement_appeals ap
+		JOIN achievement_references ar ON ar.id = ap.achievement_id` + where
+	if err := r.pgDB.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	query := appealSelect + where + fmt.Sprintf(` ORDER BY ap.created_at ASC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := r.pgDB.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	appeals := []model.Appeal{}
+	mongoIDs := []string{}
+	for rows.Next() {
+		appeal, mongoID, err := scanAppeal(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		appeals = append(appeals, *appeal)
+		mongoIDs = append(mongoIDs, mongoID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	titles, err := r.findTitles(ctx, mongoIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range appeals {
+		appeals[i].Title = titles[mongoIDs[i]]
+	}
+	return appeals, total, nil
+}
+
+func (r *AchievementRepo) GetAppeal(ctx context.Context, appealID uuid.UUID) (*model.Appeal, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.GetAppeal")
+	defer finish()
+
+	appeal, mongoID, err := scanAppeal(r.pgDB.QueryRowContext(ctx, appealSelect+` WHERE ap.id = $1`, appealID))
+	if err != nil {
+		return nil, err
+	}
+
+	titles, err := r.findTitles(ctx, []string{mongoID})
+	if err != nil {
+		return nil, err
+	}
+	appeal.Title = titles[mongoID]
+	return appeal, nil
+}
+
+// CanReviewAppeal memeriksa apakah dosen boleh memutus banding.
+func (r *AchievementRepo) CanReviewAppeal(ctx context.Context, appealID uuid.UUID, userID uuid.UUID) (bool, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.CanReviewAppeal")
+	defer finish()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM achievement_appeals ap
+			JOIN achievement_references ar ON ar.id = ap.achievement_id
+			WHERE ap.id = $1 AND` + fmt.Sprintf(appealReviewerScope, "$2") + `
+		)`
+	var allowed bool
+	err := r.pgDB.QueryRowContext(ctx, query, appealID, userID).Scan(&allowed)
+	return allowed, err
+}
+
+// DecideAppeal memutus banding yang masih pending. Bila penolakan dibatalkan,
+// tahap yang menolak dianggap disetujui dengan poin dari peninjau; achievement
+// kembali submitted di tahap berikutnya, atau menjadi verified bila tidak ada
+// tahap tersisa. Mengembalikan status achievement setelah keputusan, dan
+// sql.ErrNoRows bila banding sudah diputus.
+func (r *AchievementRepo) DecideAppeal(ctx context.Context, appealID uuid.UUID, reviewerID uuid.UUID, decision, note string, points int) (string, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.DecideAppeal")
+	defer finish()
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	status := model.AppealUpheld
+	action := model.ActionAppealUpheld
+	var awarded *int
+	if decision == model.AppealDecisionOverturn {
+		status = model.AppealOverturned
+		action = model.ActionAppealOverturned
+		awarded = &points
+	}
+
+	now := time.Now()
+	var achievementID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		UPDATE achievement_appeals
+		SET status = $1, reviewer_id = $2, decision_note = $3, points = $4, decided_at = $5
+		WHERE id = $6 AND status = $7
+		RETURNING achievement_id`,
+		status, reviewerID, note, awarded, now, appealID, model.AppealPending).Scan(&achievementID)
+	if err != nil {
+		return "", err
+	}
+
+	var mongoID string
+	achievementStatus := string(model.StatusRejected)
+	if decision == model.AppealDecisionOverturn {
+		err = tx.QueryRowContext(ctx, `
+			SELECT mongo_achievement_id FROM achievement_references
+			WHERE id = $1 AND status = $2
+			FOR UPDATE`, achievementID, model.StatusRejected).Scan(&mongoID)
+		if err != nil {
+			return "", err
+		}
+
+		last, err := overturnStageTx(ctx, tx, mongoID, reviewerID, note, points, now)
+		if err != nil {
+			return "", err
+		}
+
+		// Tahap berikutnya tetap harus menyetujui sebelum achievement terverifikasi.
+		if last {
+			achievementStatus = string(model.StatusVerified)
+			_, err = tx.ExecContext(ctx, `
+				UPDATE achievement_references
+				SET status = $1, verified_at = $2, verified_by = $3, rejection_note = '', updated_at = $2
+				WHERE mongo_achievement_id = $4 AND status = $5`,
+				model.StatusVerified, now, reviewerID, mongoID, model.StatusRejected)
+		} else {
+			achievementStatus = string(model.StatusSubmitted)
+			_, err = tx.ExecContext(ctx, `
+				UPDATE achievement_references
+				SET status = $1, verified_at = NULL, verified_by = NULL, rejection_note = '', updated_at = $2
+				WHERE mongo_achievement_id = $3 AND status = $4`,
+				model.StatusSubmitted, now, mongoID, model.StatusRejected)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = insertEvent(ctx, tx, model.AchievementEvent{
+		AchievementID: achievementID,
+		Action:        action,
+		ActorID:       &reviewerID,
+		Note:          note,
+		Points:        awarded,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	if achievementStatus == string(model.StatusVerified) {
+		return achievementStatus, r.setVerifiedPoints(ctx, mongoID, points)
+	}
+	return achievementStatus, nil
+}
+
+// overturnStageTx mengubah tahap yang menolak menjadi disetujui peninjau
+// banding dan memulai tahap berikutnya. Mengembalikan true bila tidak ada
+// tahap tersisa, termasuk achievement yang disubmit sebelum ada rantai
+// verifikasi.
+func overturnStageTx(ctx context.Context, tx *sql.Tx, mongoID string, reviewerID uuid.UUID, note string, points int, now time.Time) (bool, error) {
+	var stageAchievementID uuid.UUID
+	var position int
+	err := tx.QueryRowContext(ctx, `
+		UPDATE achievement_stages st
+		SET decision = $1, decided_by = $2, note = $3, points = $4, decided_at = $5
+		FROM achievement_references ar
+		WHERE ar.id = st.achievement_id AND ar.mongo_achievement_id = $6 AND st.decision = $7
+		RETURNING st.achievement_id, st.position`,
+		model.StageApproved, reviewerID, note, points, now, mongoID, model.StageRejected).Scan(&stageAchievementID, &position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE achievement_stages SET started_at = $1
+		WHERE achievement_id = $2 AND position = $3 AND decided_at IS NULL`,
+		now, stageAchievementID, position+1)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows == 0, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAppeal(row rowScanner) (*model.Appeal, string, error) {
+	var appeal model.Appeal
+	var mongoID string
+	var reviewerName sql.NullString
+	var points sql.NullInt64
+	var decidedAt sql.NullTime
+	err := row.Scan(&appeal.ID, &appeal.AchievementID, &mongoID, &appeal.StudentName, &appeal.StudentUserID,
+		&appeal.Statement, &appeal.Status, &appeal.RejectionNote, &reviewerName, &appeal.DecisionNote, &points,
+		&appeal.CreatedAt, &decidedAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	appeal.ReviewerName = reviewerName.String
+	if points.Valid {
+		p := int(points.Int64)
+		appeal.Points = &p
+	}
+	if decidedAt.Valid {
+		appeal.DecidedAt = &decidedAt.Time
+	}
+	return &appeal, mongoID, nil
+}
+
+// findTitles memuat judul achievement dari MongoDB, dikunci dengan hex ID.
+func (r *AchievementRepo) findTitles(ctx context.Context, mongoIDs []string) (map[string]string, error) {
+	titles := make(map[string]string)
+	var oids []primitive.ObjectID
+	for _, hexID := range mongoIDs {
+		if oid, err := primitive.ObjectIDFromHex(hexID); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return titles, nil
+	}
+
+	coll := r.mongoDB.Collection("achievements")
+	cursor, err := coll.Find(ctx, bson.M{"_id": bson.M{"$in": oids}}, options.Find().SetProjection(bson.M{"title": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Title string             `bson:"title"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		titles[doc.ID.Hex()] = doc.Title
+	}
+	return titles, nil
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"context"
+	"fiber/skp/helper"
+	"time"
+
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// batchSize membatasi jumlah id per query $in atau UPDATE massal.
+const batchSize = 500
+
+// BackfillEventDates menyalin details.eventDate dari MongoDB ke
+// achievement_references.event_date lalu menandai ulang periode akademik
+// semua achievement. Achievement tanpa tanggal kegiatan memakai tanggal
+// pembuatan, sama seperti saat dibuat. Mengembalikan jumlah dokumen yang
+// tanggalnya disalin.
+func (r *AchievementRepo) BackfillEventDates(ctx context.Context) (int, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.BackfillEventDates")
+	defer finish()
+
+	cursor, err := r.mongoDB.Collection("achievements").Find(ctx,
+		bson.M{"details.eventDate": bson.M{"$type": "date"}},
+		options.Find().SetProjection(bson.M{"details.eventDate": 1}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var mongoIDs, dates []string
+	flush := func() error {
+		if len(mongoIDs) == 0 {
+			return nil
+		}
+		_, err := tx.ExecContext(ctx, `
+			UPDATE achievement_references ar SET event_date = d.event_date
+			FROM unnest($1::text[], $2::date[]) AS d(mongo_id, event_date)
+			WHERE ar.mongo_achievement_id = d.mongo_id`,
+			pq.Array(mongoIDs), pq.Array(dates))
+		mongoIDs, dates = mongoIDs[:0], dates[:0]
+		return err
+	}
+
+	count := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID      primitive.ObjectID `bson:"_id"`
+			Details struct {
+				EventDate time.Time `bson:"eventDate"`
+			} `bson:"details"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return 0, err
+		}
+		mongoIDs = append(mongoIDs, doc.ID.Hex())
+		dates = append(dates, doc.Details.EventDate.Format(helper.DateLayout))
+		count++
+		if len(mongoIDs) == batchSize {
+			if err := flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+	if err := flush(); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE achievement_references SET event_date = created_at::date WHERE event_date IS NULL`); err != nil {
+		return 0, err
+	}
+	if err := retagPeriods(ctx, tx); err != nil {
+		return 0, err
+	}
+	return count, tx.Commit()
+}
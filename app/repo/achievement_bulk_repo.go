@@ -0,0 +1,185 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// FindVerificationTargets memuat status, tahap saat ini, dan kewenangan user
+// atas sekumpulan achievement dalam satu query. Achievement yang tidak
+// ditemukan tidak ada di hasil.
+func (r *AchievementRepo) FindVerificationTargets(ctx context.Context, userID uuid.UUID, role string, ids []uuid.UUID) (map[uuid.UUID]model.VerificationTarget, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindVerificationTargets")
+	defer finish()
+
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = id.String()
+	}
+
+	// Achievement tanpa tahap (disubmit sebelum ada rantai verifikasi)
+	// diperlakukan sebagai satu tahap dosen wali dengan position 0.
+	query := `
+		SELECT ar.id, ar.status, COALESCE(ar.team_role, ''), adv.user_id,
+			COALESCE(st.position, 0), COALESCE(st.name, $4), COALESCE(st.approver, $5),
+			COALESCE(CASE COALESCE(st.approver, $5)
+				WHEN 'advisor' THEN` + fmt.Sprintf(advisorOrDelegateScope, "adv", "$2") + `
+				WHEN 'department_head' THEN $3 = 'dosen_wali' AND EXISTS (
+					SELECT 1 FROM lecturers h
+					WHERE h.department_id = adv.department_id AND h.user_id = $2 AND h.is_department_head
+				)
+				WHEN 'admin' THEN $3 = 'admin'
+			END, FALSE)
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		LEFT JOIN lecturers adv ON adv.id = s.advisor_id
+		LEFT JOIN LATERAL (
+			SELECT position, name, approver
+			FROM achievement_stages
+			WHERE achievement_id = ar.id AND decided_at IS NULL
+			ORDER BY position
+			LIMIT 1
+		) st ON TRUE
+		WHERE ar.id = ANY($1::uuid[]) AND ar.status != $6`
+	rows, err := r.pgDB.QueryContext(ctx, query, pq.Array(idStrings), userID, role,
+		model.DefaultStageName, model.ApproverAdvisor, model.StatusDeleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := make(map[uuid.UUID]model.VerificationTarget, len(ids))
+	for rows.Next() {
+		var t model.VerificationTarget
+		var advisorUserID uuid.NullUUID
+		err := rows.Scan(&t.ID, &t.Status, &t.TeamRole, &advisorUserID,
+			&t.Stage.Position, &t.Stage.Name, &t.Stage.Approver, &t.Authorized)
+		if err != nil {
+			return nil, err
+		}
+		t.AdvisorUserID = advisorUserID.UUID
+		targets[t.ID] = t
+	}
+	return targets, rows.Err()
+}
+
+// BulkDecide menerapkan keputusan verifikasi dalam satu transaksi. Setiap
+// achievement diproses dalam savepoint sendiri sehingga kegagalan satu butir
+// tidak membatalkan yang lain; Err berisi sql.ErrNoRows bila achievement
+// tidak lagi menunggu keputusan tahap tersebut.
+func (r *AchievementRepo) BulkDecide(ctx context.Context, actorID uuid.UUID, decisions []model.BulkDecision) ([]model.BulkDecisionResult, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.BulkDecide")
+	defer finish()
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	results := make([]model.BulkDecisionResult, len(decisions))
+	verified := make(map[int]string)
+	for i, d := range decisions {
+		results[i].ID = d.ID
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT bulk_item`); err != nil {
+			return nil, err
+		}
+
+		status, mongoID, err := decideVerificationTx(ctx, tx, actorID, d, now)
+		if err != nil {
+			if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT bulk_item`); rbErr != nil {
+				return nil, rbErr
+			}
+			results[i].Err = err
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT bulk_item`); err != nil {
+			return nil, err
+		}
+
+		results[i].Status = status
+		if status == string(model.StatusVerified) {
+			verified[i] = mongoID
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Poin di MongoDB ditulis setelah commit.
+	for i, mongoID := range verified {
+		if err := r.setVerifiedPoints(ctx, mongoID, decisions[i].Points); err != nil {
+			results[i].Err = err
+		}
+	}
+	return results, nil
+}
+
+// decideVerificationTx menerapkan satu keputusan di dalam tx dan mengembalikan
+// status baru beserta id dokumen MongoDB-nya.
+func decideVerificationTx(ctx context.Context, tx *sql.Tx, actorID uuid.UUID, d model.BulkDecision, now time.Time) (string, string, error) {
+	var mongoID string
+	err := tx.QueryRowContext(ctx,
+		`SELECT mongo_achievement_id FROM achievement_references WHERE id = $1 AND status = $2 FOR UPDATE`,
+		d.ID, model.StatusSubmitted).Scan(&mongoID)
+	if err != nil {
+		return "", "", err
+	}
+
+	var points *int
+	if d.Decision == model.StageApproved {
+		points = &d.Points
+	}
+
+	last := true
+	if d.StagePosition > 0 {
+		last, err = decideStageTx(ctx, tx, d.ID, d.StagePosition, actorID, d.Decision, d.Note, points, now)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	if !last {
+		err := insertEvent(ctx, tx, model.AchievementEvent{
+			AchievementID: d.ID,
+			Action:        model.ActionStageApproved,
+			ActorID:       &actorID,
+			OnBehalfOf:    d.OnBehalfOf,
+			Note:          d.StageName,
+			Points:        points,
+		})
+		return string(model.StatusSubmitted), mongoID, err
+	}
+
+	status, action := model.StatusVerified, model.ActionVerified
+	if d.Decision == model.StageRejected {
+		status, action = model.StatusRejected, model.ActionRejected
+	}
+
+	// Status berlaku untuk semua baris yang merujuk dokumen yang sama (achievement tim).
+	_, err = tx.ExecContext(ctx, `
+		UPDATE achievement_references
+		SET status = $1, verified_at = $2, verified_by = $3, rejection_note = $4, updated_at = $2
+		WHERE mongo_achievement_id = $5 AND status != $6`,
+		status, now, actorID, d.Note, mongoID, model.StatusDeleted)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = insertEvent(ctx, tx, model.AchievementEvent{
+		AchievementID: d.ID,
+		Action:        action,
+		ActorID:       &actorID,
+		OnBehalfOf:    d.OnBehalfOf,
+		Note:          d.Note,
+		Points:        points,
+	})
+	return string(status), mongoID, err
+}
@@ -0,0 +1,252 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sameDocument mencocokkan semua baris yang merujuk dokumen achievement yang
+// sama dengan baris $1, agar anggota tim berbagi diskusi dengan ketuanya.
+const sameDocument = `(SELECT mongo_achievement_id FROM achievement_references WHERE id = $1)`
+
+// AddComment menyimpan komentar. Balasan selalu ditautkan ke komentar akar
+// agar thread hanya satu tingkat; sql.ErrNoRows bila induk tidak ditemukan
+// pada achievement yang sama.
+func (r *AchievementRepo) AddComment(ctx context.Context, comment *model.AchievementComment) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.AddComment")
+	defer finish()
+
+	if comment.ParentID != nil {
+		var rootID uuid.UUID
+		err := r.pgDB.QueryRowContext(ctx, `
+			SELECT COALESCE(c.parent_id, c.id)
+			FROM achievement_comments c
+			JOIN achievement_references ar ON ar.id = c.achievement_id
+			WHERE c.id = $2 AND ar.mongo_achievement_id = `+sameDocument,
+			comment.AchievementID, *comment.ParentID).Scan(&rootID)
+		if err != nil {
+			return err
+		}
+		comment.ParentID = &rootID
+	}
+
+	query := `
+		INSERT INTO achievement_comments (achievement_id, parent_id, author_id, body, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	return r.pgDB.QueryRowContext(ctx, query, comment.AchievementID, comment.ParentID, comment.AuthorID, comment.Body, time.Now()).
+		Scan(&comment.ID, &comment.CreatedAt)
+}
+
+// FindComments memuat thread komentar achievement, komentar akar beserta
+// balasannya, berurutan dari yang terlama.
+func (r *AchievementRepo) FindComments(ctx context.Context, id uuid.UUID) ([]model.AchievementComment, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindComments")
+	defer finish()
+
+	query := `
+		SELECT c.id, c.achievement_id, c.parent_id, c.author_id, u.full_name, ro.name, c.body, c.created_at
+		FROM achievement_comments c
+		JOIN achievement_references ar ON ar.id = c.achievement_id
+		LEFT JOIN users u ON u.id = c.author_id
+		LEFT JOIN roles ro ON ro.id = u.role_id
+		WHERE ar.mongo_achievement_id = ` + sameDocument + `
+		ORDER BY c.created_at`
+	rows, err := r.pgDB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.AchievementComment{}
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var c model.AchievementComment
+		var parentID, authorID uuid.NullUUID
+		var authorName, authorRole sql.NullString
+		if err := rows.Scan(&c.ID, &c.AchievementID, &parentID, &authorID, &authorName, &authorRole, &c.Body, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.AuthorID = authorID.UUID
+		c.AuthorName = authorName.String
+		c.AuthorRole = authorRole.String
+
+		if parentID.Valid {
+			c.ParentID = &parentID.UUID
+			if i, ok := index[parentID.UUID]; ok {
+				comments[i].Replies = append(comments[i].Replies, c)
+			}
+			continue
+		}
+		index[c.ID] = len(comments)
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// RequestChanges mengembalikan achievement yang sedang diverifikasi ke draft
+// beserta butir perbaikan yang diminta. Mengembalikan sql.ErrNoRows bila
+// achievement tidak lagi berstatus submitted.
+func (r *AchievementRepo) RequestChanges(ctx context.Context, id uuid.UUID, actorID uuid.UUID, onBehalfOf *uuid.UUID, note string, items []string) ([]model.ChangeItem, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.RequestChanges")
+	defer finish()
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var mongoID string
+	err = tx.QueryRowContext(ctx,
+		`SELECT mongo_achievement_id FROM achievement_references WHERE id = $1 AND status = $2 FOR UPDATE`,
+		id, model.StatusSubmitted).Scan(&mongoID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE achievement_references SET status = $1, updated_at = $2
+		WHERE mongo_achievement_id = $3 AND status = $4`,
+		model.StatusDraft, now, mongoID, model.StatusSubmitted)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE achievement_stages
+		SET decision = $1, decided_by = $2, note = $3, decided_at = $4
+		WHERE achievement_id = $5 AND started_at IS NOT NULL AND decided_at IS NULL`,
+		model.StageChangesRequested, actorID, note, now, id)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]model.ChangeItem, 0, len(items))
+	for _, description := range items {
+		item := model.ChangeItem{Description: description, Status: model.ChangeOpen}
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO achievement_change_items (achievement_id, requested_by, description, created_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at`,
+			id, actorID, description, now).Scan(&item.ID, &item.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, item)
+	}
+
+	err = insertEvent(ctx, tx, model.AchievementEvent{
+		AchievementID: id,
+		Action:        model.ActionChangesRequested,
+		ActorID:       &actorID,
+		OnBehalfOf:    onBehalfOf,
+		Note:          note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// FindChangeItems memuat semua butir perbaikan achievement, yang masih
+// terbuka lebih dulu.
+func (r *AchievementRepo) FindChangeItems(ctx context.Context, id uuid.UUID) ([]model.ChangeItem, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindChangeItems")
+	defer finish()
+
+	query := `
+		SELECT ci.id, ci.description, ci.status, ci.response, u.full_name, ci.created_at, ci.addressed_at
+		FROM achievement_change_items ci
+		JOIN achievement_references ar ON ar.id = ci.achievement_id
+		LEFT JOIN users u ON u.id = ci.requested_by
+		WHERE ar.mongo_achievement_id = ` + sameDocument + `
+		ORDER BY ci.status = 'addressed', ci.created_at`
+	rows, err := r.pgDB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.ChangeItem{}
+	for rows.Next() {
+		var item model.ChangeItem
+		var requester sql.NullString
+		var addressedAt sql.NullTime
+		if err := rows.Scan(&item.ID, &item.Description, &item.Status, &item.Response, &requester, &item.CreatedAt, &addressedAt); err != nil {
+			return nil, err
+		}
+		item.RequesterName = requester.String
+		if addressedAt.Valid {
+			item.AddressedAt = &addressedAt.Time
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// addressChangesTx menandai butir perbaikan yang sudah dikerjakan mahasiswa
+// dan mengembalikan jumlah butir yang masih terbuka. Mengembalikan error
+// validasi bila ada butir yang tidak terbuka pada achievement ini.
+func addressChangesTx(ctx context.Context, tx *sql.Tx, id uuid.UUID, actorID uuid.UUID, responses map[uuid.UUID]string, now time.Time) (int, error) {
+	var addressed []string
+	for itemID, response := range responses {
+		var description string
+		err := tx.QueryRowContext(ctx, `
+			UPDATE achievement_change_items ci
+			SET status = $3, response = $4, addressed_at = $5
+			FROM achievement_references ar
+			WHERE ar.id = ci.achievement_id AND ci.id = $2 AND ci.status = $6
+			  AND ar.mongo_achievement_id = `+sameDocument+`
+			RETURNING ci.description`,
+			id, itemID, model.ChangeAddressed, response, now, model.ChangeOpen).Scan(&description)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+				{Field: "addressed", Rule: "exists"},
+			})
+		}
+		if err != nil {
+			return 0, err
+		}
+		addressed = append(addressed, description)
+	}
+
+	var open int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM achievement_change_items ci
+		JOIN achievement_references ar ON ar.id = ci.achievement_id
+		WHERE ci.status = $2 AND ar.mongo_achievement_id = `+sameDocument,
+		id, model.ChangeOpen).Scan(&open)
+	if err != nil {
+		return 0, err
+	}
+
+	// Riwayat mencatat butir yang dikerjakan pada pengajuan ulang ini.
+	if len(addressed) > 0 {
+		sort.Strings(addressed)
+		err = insertEvent(ctx, tx, model.AchievementEvent{
+			AchievementID: id,
+			Action:        model.ActionChangesAddressed,
+			ActorID:       &actorID,
+			Note:          strings.Join(addressed, "; "),
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return open, nil
+}
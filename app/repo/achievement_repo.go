@@ -4,29 +4,76 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fiber/skp/app/apperror"
 	"fiber/skp/app/model"
+	"fiber/skp/helper"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AchievementRepository interface {
-	Create(studentID uuid.UUID, req model.CreateAchievementRequest) (*model.AchievementResponse, error)
-	FindByAchievementID(id uuid.UUID) (*model.AchievementResponse, error)
-	FindAll(role string, userID uuid.UUID, page, limit int, search, sortBy, order string) ([]model.AchievementResponse, int64, error)
-	Update(id uuid.UUID, req model.UpdateAchievementRequest) (*model.AchievementResponse, error)
-	UpdateStatus(id uuid.UUID, status string, verifierID *uuid.UUID, note string, points int) error
-	Delete(id uuid.UUID) error
-	AddAttachment(id uuid.UUID, attachment model.Attachment) error
+	Create(ctx context.Context, studentID uuid.UUID, req model.CreateAchievementRequest) (*model.AchievementResponse, error)
+	FindByAchievementID(ctx context.Context, id uuid.UUID) (*model.AchievementResponse, error)
+	FindAll(ctx context.Context, role string, userID uuid.UUID, page, limit int, search, sortBy, order string) ([]model.AchievementResponse, int64, error)
+	Update(ctx context.Context, id uuid.UUID, req model.UpdateAchievementRequest) (*model.AchievementResponse, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string, verifierID *uuid.UUID, note string, points int) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddAttachment(ctx context.Context, id uuid.UUID, attachment model.Attachment) error
 	mapToResponse(ref model.AchievementReference, mongoDoc model.AchievementMongo) *model.AchievementResponse
-	GetOwnerID(id uuid.UUID) (uuid.UUID, error)
-	IsAdvisor(advisorID uuid.UUID, achievementID uuid.UUID) (bool, error)
-	GetStatus(id uuid.UUID) (string, error)
-	GetHistory(id uuid.UUID) (*model.AchievementHistoryResponse, error)
+	GetOwnerID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	IsAdvisor(ctx context.Context, advisorID uuid.UUID, achievementID uuid.UUID) (bool, error)
+	GetStatus(ctx context.Context, id uuid.UUID) (string, error)
+	HasFrozenStudent(ctx context.Context, id uuid.UUID) (bool, error)
+	GetAchievementType(ctx context.Context, id uuid.UUID) (string, error)
+	GetHistory(ctx context.Context, id uuid.UUID) (*model.AchievementHistoryResponse, error)
+	FindCertificationAlerts(ctx context.Context, alert string, before time.Time) ([]model.CertificationAlert, error)
+	MarkCertificationAlerted(ctx context.Context, mongoID string, alerts ...string) error
+	GetTeamRole(ctx context.Context, id uuid.UUID) (string, error)
+	FindTeam(ctx context.Context, id uuid.UUID) ([]model.TeamMember, error)
+	SetTeam(ctx context.Context, id uuid.UUID, members []model.TeamMember) ([]model.TeamMember, error)
+	ConfirmParticipation(ctx context.Context, id uuid.UUID) error
+	DeclineParticipation(ctx context.Context, id uuid.UUID) error
+	SaveFingerprints(ctx context.Context, id uuid.UUID, fingerprints []string) error
+	FindFingerprintTargets(ctx context.Context) ([]uuid.UUID, error)
+	FindDuplicates(ctx context.Context, id uuid.UUID, fingerprints []string) ([]model.DuplicateSuspect, error)
+	SaveDuplicateFlags(ctx context.Context, id uuid.UUID, suspects []model.DuplicateSuspect) error
+	FindDuplicateFlags(ctx context.Context, id uuid.UUID) ([]model.DuplicateSuspect, error)
+	GetAdvisorUserID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	AddEvent(ctx context.Context, event model.AchievementEvent) error
+	GetVerifierID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	FindStudentUserIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	Revoke(ctx context.Context, id uuid.UUID, actorID uuid.UUID, reason string) (int, error)
+	CreateAppeal(ctx context.Context, id uuid.UUID, studentUserID uuid.UUID, statement string) (*model.Appeal, error)
+	HasPendingAppeal(ctx context.Context, id uuid.UUID) (bool, error)
+	FindAppeals(ctx context.Context, role string, userID uuid.UUID, status string, page, limit int) ([]model.Appeal, int64, error)
+	GetAppeal(ctx context.Context, appealID uuid.UUID) (*model.Appeal, error)
+	CanReviewAppeal(ctx context.Context, appealID uuid.UUID, userID uuid.UUID) (bool, error)
+	DecideAppeal(ctx context.Context, appealID uuid.UUID, reviewerID uuid.UUID, decision, note string, points int) (string, error)
+	Submit(ctx context.Context, id uuid.UUID, actorID uuid.UUID, responses map[uuid.UUID]string, chainName string, stages []model.VerificationStage) (int, error)
+	FindStages(ctx context.Context, id uuid.UUID) ([]model.AchievementStage, error)
+	FindPreviousStages(ctx context.Context, id uuid.UUID) ([]model.AchievementStage, error)
+	GetCurrentStage(ctx context.Context, id uuid.UUID) (*model.AchievementStage, error)
+	DecideVerification(ctx context.Context, id uuid.UUID, stage *model.AchievementStage, actorID uuid.UUID, onBehalfOf *uuid.UUID, decision, note string, points int) (string, error)
+	IsDepartmentHead(ctx context.Context, lecturerUserID uuid.UUID, achievementID uuid.UUID) (bool, error)
+	IsStageApprover(ctx context.Context, lecturerUserID uuid.UUID, achievementID uuid.UUID) (bool, error)
+	AddComment(ctx context.Context, comment *model.AchievementComment) error
+	FindComments(ctx context.Context, id uuid.UUID) ([]model.AchievementComment, error)
+	RequestChanges(ctx context.Context, id uuid.UUID, actorID uuid.UUID, onBehalfOf *uuid.UUID, note string, items []string) ([]model.ChangeItem, error)
+	FindChangeItems(ctx context.Context, id uuid.UUID) ([]model.ChangeItem, error)
+	FindVerificationTargets(ctx context.Context, userID uuid.UUID, role string, ids []uuid.UUID) (map[uuid.UUID]model.VerificationTarget, error)
+	BulkDecide(ctx context.Context, actorID uuid.UUID, decisions []model.BulkDecision) ([]model.BulkDecisionResult, error)
+	FindVerificationQueue(ctx context.Context, userID uuid.UUID, role string, page, limit int) ([]model.QueueItem, int64, error)
+	FindOverdueStages(ctx context.Context, now time.Time) ([]model.OverdueStage, error)
+	MarkStageEscalated(ctx context.Context, id uuid.UUID, position int) error
+	FindSLAStats(ctx context.Context) ([]model.LecturerSLAStats, error)
 }
 
 type AchievementRepo struct {
@@ -45,39 +92,29 @@ var achievementSortWhitelist = map[string]string{
 	"date":       "ar.created_at",
 }
 
-func (r *AchievementRepo) Create(studentID uuid.UUID, req model.CreateAchievementRequest) (*model.AchievementResponse, error) {
-	var details model.AchievementDetails
-	if req.CompetitionDetails != nil {
-		details.CompetitionName = req.CompetitionDetails.CompetitionName
-		details.CompetitionLevel = req.CompetitionDetails.CompetitionLevel
-		details.Rank = req.CompetitionDetails.Rank
-		details.MedalType = req.CompetitionDetails.MedalType
-	} else if req.PublicationDetails != nil {
-		details.PublicationTitle = req.PublicationDetails.PublicationTitle
-		details.Authors = req.PublicationDetails.Authors
-		details.Publisher = req.PublicationDetails.Publisher
-		details.ISSN = req.PublicationDetails.ISSN
-	} else if req.OrganizationDetails != nil {
-		details.OrganizationName = req.OrganizationDetails.OrganizationName
-		details.Position = req.OrganizationDetails.Position
-
-		layout := "2006-01-02"
-		start, err := time.Parse(layout, req.OrganizationDetails.StartDate)
-		if err != nil {
-			return nil, fmt.Errorf("invalid start_date format, expected YYYY-MM-DD: %w", err)
-		}
-		end, err := time.Parse(layout, req.OrganizationDetails.EndDate)
-		if err != nil {
-			return nil, fmt.Errorf("invalid end_date format, expected YYYY-MM-DD: %w", err)
-		}
-		details.Period = &model.OrganizationPeriod{
-			Start: start,
-			End:   end,
-		}
+func (r *AchievementRepo) Create(ctx context.Context, studentID uuid.UUID, req model.CreateAchievementRequest) (*model.AchievementResponse, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.Create")
+	defer finish()
+
+	// req.Details sudah divalidasi terhadap schema tipe oleh service.
+	details := bson.M{}
+	for key, value := range req.Details {
+		details[key] = value
 	}
 
 	now := time.Now()
 
+	// Tanpa tanggal kegiatan, periode ditentukan dari tanggal pembuatan.
+	periodDate := now
+	if req.EventDate != "" {
+		eventDate, err := time.Parse("2006-01-02", req.EventDate)
+		if err != nil {
+			return nil, invalidDate("event_date", err)
+		}
+		details["eventDate"] = eventDate
+		periodDate = eventDate
+	}
+
 	mongoData := bson.M{
 		"studentId":       studentID.String(),
 		"achievementType": req.AchievementType,
@@ -92,21 +129,21 @@ func (r *AchievementRepo) Create(studentID uuid.UUID, req model.CreateAchievemen
 	}
 
 	coll := r.mongoDB.Collection("achievements")
-	res, err := coll.InsertOne(context.TODO(), mongoData)
+	res, err := coll.InsertOne(ctx, mongoData)
 	if err != nil {
 		return nil, err
 	}
 	oid := res.InsertedID.(primitive.ObjectID)
 
 	query := `
-		INSERT INTO achievement_references (student_id, mongo_achievement_id, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO achievement_references (student_id, mongo_achievement_id, status, event_date, period_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4::date, ` + fmt.Sprintf(periodForDate, "$4::date") + `, $5, $6)
 		RETURNING id`
 
 	var pgID uuid.UUID
-	err = r.pgDB.QueryRow(query, studentID, oid.Hex(), "draft", now, now).Scan(&pgID)
+	err = r.pgDB.QueryRowContext(ctx, query, studentID, oid.Hex(), "draft", periodDate.Format("2006-01-02"), now, now).Scan(&pgID)
 	if err != nil {
-		coll.DeleteOne(context.TODO(), bson.M{"_id": oid})
+		coll.DeleteOne(ctx, bson.M{"_id": oid})
 		return nil, err
 	}
 
@@ -127,31 +164,40 @@ func (r *AchievementRepo) Create(studentID uuid.UUID, req model.CreateAchievemen
 	}, nil
 }
 
-func (r *AchievementRepo) FindByAchievementID(id uuid.UUID) (*model.AchievementResponse, error) {
+func (r *AchievementRepo) FindByAchievementID(ctx context.Context, id uuid.UUID) (*model.AchievementResponse, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindByAchievementID")
+	defer finish()
+
 	query := `
-		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.rejection_note, ar.created_at, ar.updated_at,
-		       s.id, u.full_name
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.rejection_note, ar.team_role, ar.created_at, ar.updated_at,
+		       s.id, u.full_name, p.year, p.semester
 		FROM achievement_references ar
 		JOIN students s ON s.id = ar.student_id
 		JOIN users u ON u.id = s.user_id
+		LEFT JOIN academic_periods p ON p.id = ar.period_id
 		WHERE ar.id = $1 AND ar.status != $2`
 
 	var ref model.AchievementReference
 	var studentID uuid.UUID
 	var studentFullName sql.NullString
-	var rejectionNote sql.NullString
+	var rejectionNote, teamRole sql.NullString
+	var periodYear, periodSemester sql.NullString
 
-	err := r.pgDB.QueryRow(query, id, model.StatusDeleted).Scan(
-		&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &rejectionNote, &ref.CreatedAt, &ref.UpdatedAt,
-		&studentID, &studentFullName,
+	err := r.pgDB.QueryRowContext(ctx, query, id, model.StatusDeleted).Scan(
+		&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &rejectionNote, &teamRole, &ref.CreatedAt, &ref.UpdatedAt,
+		&studentID, &studentFullName, &periodYear, &periodSemester,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if periodYear.Valid {
+		ref.PeriodLabel = model.PeriodLabel(periodYear.String, periodSemester.String)
+	}
 
 	if rejectionNote.Valid {
 		ref.RejectionNote = rejectionNote.String
 	}
+	ref.TeamRole = teamRole.String
 
 	ref.Student.ID = studentID
 	if studentFullName.Valid {
@@ -162,7 +208,7 @@ func (r *AchievementRepo) FindByAchievementID(id uuid.UUID) (*model.AchievementR
 	objID, _ := primitive.ObjectIDFromHex(ref.MongoAchievementID)
 
 	coll := r.mongoDB.Collection("achievements")
-	err = coll.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&mongoDetail)
+	err = coll.FindOne(ctx, bson.M{"_id": objID}).Decode(&mongoDetail)
 
 	if err != nil {
 		return nil, errors.New("detail data missing in NoSQL")
@@ -171,19 +217,22 @@ func (r *AchievementRepo) FindByAchievementID(id uuid.UUID) (*model.AchievementR
 	return r.mapToResponse(ref, mongoDetail), nil
 }
 
-func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int, search, sortBy, order string) ([]model.AchievementResponse, int64, error) {
+func (r *AchievementRepo) FindAll(ctx context.Context, role string, userID uuid.UUID, page, limit int, search, sortBy, order string) ([]model.AchievementResponse, int64, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindAll")
+	defer finish()
+
 	var total int64
 	var mongoIDs []string
 	if search != "" {
 		coll := r.mongoDB.Collection("achievements")
 		filter := bson.M{"title": bson.M{"$regex": search, "$options": "i"}}
-		cursor, err := coll.Find(context.TODO(), filter)
+		cursor, err := coll.Find(ctx, filter)
 		if err != nil {
 			return nil, 0, err
 		}
-		defer cursor.Close(context.TODO())
+		defer cursor.Close(ctx)
 
-		for cursor.Next(context.TODO()) {
+		for cursor.Next(ctx) {
 			var doc struct {
 				ID primitive.ObjectID `bson:"_id"`
 			}
@@ -206,7 +255,7 @@ func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int
 
 	if search != "" && len(mongoIDs) > 0 {
 		countQuery += fmt.Sprintf(" AND ar.mongo_achievement_id = ANY($%d)", argIndex)
-		args = append(args, mongoIDs)
+		args = append(args, pq.Array(mongoIDs))
 		argIndex++
 	}
 
@@ -215,7 +264,13 @@ func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int
 		args = append(args, userID)
 		argIndex++
 	} else if role == model.RoleDosenWali {
-		countQuery += fmt.Sprintf(" AND ar.student_id IN (SELECT s.id FROM students s JOIN lecturers l ON s.advisor_id = l.id WHERE l.user_id = $%d) AND ar.status != $%d", argIndex, argIndex+1)
+		// Dosen melihat achievement mahasiswa bimbingannya (termasuk lewat
+		// delegasi) dan achievement yang menunggu tahap verifikasinya sebagai
+		// kepala jurusan.
+		countQuery += " AND (ar.student_id IN (SELECT s.id FROM students s JOIN lecturers l ON s.advisor_id = l.id WHERE" +
+			fmt.Sprintf(advisorOrDelegateScope, "l", fmt.Sprintf("$%d", argIndex)) + ") OR" +
+			fmt.Sprintf(departmentHeadStageScope, fmt.Sprintf("$%d", argIndex)) +
+			fmt.Sprintf(") AND ar.status != $%d", argIndex+1)
 		args = append(args, userID, model.StatusDraft)
 		argIndex += 2
 	} else if role == model.RoleAdmin {
@@ -224,13 +279,13 @@ func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int
 		argIndex++
 	}
 
-	err := r.pgDB.QueryRow(countQuery, args...).Scan(&total)
+	err := r.pgDB.QueryRowContext(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	mainQuery := `
-		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.rejection_note, ar.created_at, ar.updated_at,
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.rejection_note, ar.team_role, ar.created_at, ar.updated_at,
 		       s.id, u.full_name
 		FROM achievement_references ar
 		JOIN students s ON s.id = ar.student_id
@@ -242,7 +297,7 @@ func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int
 
 	if search != "" && len(mongoIDs) > 0 {
 		mainQuery += fmt.Sprintf(" AND ar.mongo_achievement_id = ANY($%d)", selectArgIndex)
-		selectArgs = append(selectArgs, mongoIDs)
+		selectArgs = append(selectArgs, pq.Array(mongoIDs))
 		selectArgIndex++
 	}
 
@@ -251,7 +306,10 @@ func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int
 		selectArgs = append(selectArgs, userID)
 		selectArgIndex++
 	} else if role == model.RoleDosenWali {
-		mainQuery += fmt.Sprintf(" AND ar.student_id IN (SELECT s.id FROM students s JOIN lecturers l ON s.advisor_id = l.id WHERE l.user_id = $%d) AND ar.status != $%d", selectArgIndex, selectArgIndex+1)
+		mainQuery += " AND (ar.student_id IN (SELECT s.id FROM students s JOIN lecturers l ON s.advisor_id = l.id WHERE" +
+			fmt.Sprintf(advisorOrDelegateScope, "l", fmt.Sprintf("$%d", selectArgIndex)) + ") OR" +
+			fmt.Sprintf(departmentHeadStageScope, fmt.Sprintf("$%d", selectArgIndex)) +
+			fmt.Sprintf(") AND ar.status != $%d", selectArgIndex+1)
 		selectArgs = append(selectArgs, userID, model.StatusDraft)
 		selectArgIndex += 2
 	} else if role == model.RoleAdmin {
@@ -273,22 +331,21 @@ func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int
 	mainQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", selectArgIndex, selectArgIndex+1)
 	selectArgs = append(selectArgs, limit, offset)
 
-	rows, err := r.pgDB.Query(mainQuery, selectArgs...)
+	rows, err := r.pgDB.QueryContext(ctx, mainQuery, selectArgs...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
 	var refs []model.AchievementReference
-	refMap := make(map[string]model.AchievementReference)
 
 	for rows.Next() {
 		var ref model.AchievementReference
 		var studentID uuid.UUID
-		var studentFullName, rejectionNote sql.NullString
+		var studentFullName, rejectionNote, teamRole sql.NullString
 
 		if err := rows.Scan(
-			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &rejectionNote, &ref.CreatedAt, &ref.UpdatedAt,
+			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &rejectionNote, &teamRole, &ref.CreatedAt, &ref.UpdatedAt,
 			&studentID, &studentFullName,
 		); err != nil {
 			return nil, 0, err
@@ -297,6 +354,7 @@ func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int
 		if rejectionNote.Valid {
 			ref.RejectionNote = rejectionNote.String
 		}
+		ref.TeamRole = teamRole.String
 
 		ref.Student.ID = studentID
 		if studentFullName.Valid {
@@ -304,7 +362,6 @@ func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int
 		}
 
 		refs = append(refs, ref)
-		refMap[ref.MongoAchievementID] = ref
 	}
 
 	if len(refs) == 0 {
@@ -318,30 +375,39 @@ func (r *AchievementRepo) FindAll(role string, userID uuid.UUID, page, limit int
 	}
 
 	coll := r.mongoDB.Collection("achievements")
-	cursor, err := coll.Find(context.TODO(), bson.M{"_id": bson.M{"$in": mongoOIDs}})
+	cursor, err := coll.Find(ctx, bson.M{"_id": bson.M{"$in": mongoOIDs}})
 	if err != nil {
 		return nil, 0, err
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
-	var results []model.AchievementResponse
-	for cursor.Next(context.TODO()) {
+	// Satu dokumen bisa dirujuk beberapa anggota tim, jadi hasil disusun
+	// mengikuti urutan baris PostgreSQL.
+	docs := make(map[string]model.AchievementMongo)
+	for cursor.Next(ctx) {
 		var doc model.AchievementMongo
 		if err := cursor.Decode(&doc); err == nil {
-			hexID := doc.ID.Hex()
-			if sqlRef, ok := refMap[hexID]; ok {
-				results = append(results, *r.mapToResponse(sqlRef, doc))
-			}
+			docs[doc.ID.Hex()] = doc
+		}
+	}
+
+	var results []model.AchievementResponse
+	for _, ref := range refs {
+		if doc, ok := docs[ref.MongoAchievementID]; ok {
+			results = append(results, *r.mapToResponse(ref, doc))
 		}
 	}
 
 	return results, total, nil
 }
 
-func (r *AchievementRepo) Update(id uuid.UUID, req model.UpdateAchievementRequest) (*model.AchievementResponse, error) {
+func (r *AchievementRepo) Update(ctx context.Context, id uuid.UUID, req model.UpdateAchievementRequest) (*model.AchievementResponse, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.Update")
+	defer finish()
+
 	query := `SELECT mongo_achievement_id FROM achievement_references WHERE id = $1 AND status != $2`
 	var mongoID string
-	err := r.pgDB.QueryRow(query, id, model.StatusDeleted).Scan(&mongoID)
+	err := r.pgDB.QueryRowContext(ctx, query, id, model.StatusDeleted).Scan(&mongoID)
 	if err != nil {
 		return nil, err
 	}
@@ -359,113 +425,147 @@ func (r *AchievementRepo) Update(id uuid.UUID, req model.UpdateAchievementReques
 	if req.Description != nil {
 		updateFields["description"] = *req.Description
 	}
-	if req.EventDate != nil {
-		parsedDate, _ := time.Parse("2006-01-02", *req.EventDate)
-		updateFields["details.eventDate"] = parsedDate
-	}
 	if req.Tags != nil {
 		updateFields["tags"] = *req.Tags
 	}
 
-	if req.CompetitionDetails != nil {
-		updateFields["details.competitionName"] = req.CompetitionDetails.CompetitionName
-		updateFields["details.competitionLevel"] = req.CompetitionDetails.CompetitionLevel
-		updateFields["details.rank"] = req.CompetitionDetails.Rank
-		updateFields["details.medalType"] = req.CompetitionDetails.MedalType
-	} else if req.PublicationDetails != nil {
-		updateFields["details.publicationTitle"] = req.PublicationDetails.PublicationTitle
-		updateFields["details.authors"] = req.PublicationDetails.Authors
-		updateFields["details.publisher"] = req.PublicationDetails.Publisher
-		updateFields["details.issn"] = req.PublicationDetails.ISSN
-	} else if req.OrganizationDetails != nil {
-		updateFields["details.organizationName"] = req.OrganizationDetails.OrganizationName
-		updateFields["details.position"] = req.OrganizationDetails.Position
-		if req.OrganizationDetails.StartDate != "" && req.OrganizationDetails.EndDate != "" {
-			layout := "2006-01-02"
-			start, err := time.Parse(layout, req.OrganizationDetails.StartDate)
-			if err != nil {
-				return nil, fmt.Errorf("invalid start_date format, expected YYYY-MM-DD: %w", err)
-			}
-			end, err := time.Parse(layout, req.OrganizationDetails.EndDate)
-			if err != nil {
-				return nil, fmt.Errorf("invalid end_date format, expected YYYY-MM-DD: %w", err)
-			}
-			updateFields["details.period"] = &model.OrganizationPeriod{
-				Start: start,
-				End:   end,
-			}
+	var eventDate interface{}
+	if req.EventDate != nil {
+		parsedDate, err := time.Parse("2006-01-02", *req.EventDate)
+		if err != nil {
+			return nil, invalidDate("event_date", err)
 		}
+		eventDate = parsedDate
 	}
 
 	objID, _ := primitive.ObjectIDFromHex(mongoID)
 	coll := r.mongoDB.Collection("achievements")
 
-	_, err = coll.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": updateFields})
+	if req.Details != nil {
+		// details diganti utuh; eventDate lama dipertahankan bila tidak dikirim.
+		details := bson.M{}
+		for key, value := range req.Details {
+			details[key] = value
+		}
+		if eventDate == nil {
+			var current struct {
+				Details bson.M `bson:"details"`
+			}
+			err := coll.FindOne(ctx, bson.M{"_id": objID}, options.FindOne().SetProjection(bson.M{"details.eventDate": 1})).Decode(&current)
+			if err != nil {
+				return nil, err
+			}
+			eventDate = current.Details["eventDate"]
+		}
+		if eventDate != nil {
+			details["eventDate"] = eventDate
+		}
+		updateFields["details"] = details
+	} else if eventDate != nil {
+		updateFields["details.eventDate"] = eventDate
+	}
+
+	_, err = coll.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": updateFields})
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = r.pgDB.Exec("UPDATE achievement_references SET updated_at = $1 WHERE id = $2", time.Now(), id)
+	_, err = r.pgDB.ExecContext(ctx, "UPDATE achievement_references SET updated_at = $1 WHERE id = $2", time.Now(), id)
 	if err != nil {
 		return nil, err
 	}
 
-	return r.FindByAchievementID(id)
+	// Tanggal kegiatan baru memindahkan semua baris achievement ke periodenya.
+	if req.EventDate != nil {
+		query := `UPDATE achievement_references SET event_date = $1::date, period_id = ` + fmt.Sprintf(periodForDate, "$1::date") + `
+			WHERE mongo_achievement_id = $2`
+		if _, err := r.pgDB.ExecContext(ctx, query, *req.EventDate, mongoID); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.FindByAchievementID(ctx, id)
 }
 
-func (r *AchievementRepo) UpdateStatus(id uuid.UUID, status string, verifierID *uuid.UUID, note string, points int) error {
+func (r *AchievementRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status string, verifierID *uuid.UUID, note string, points int) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.UpdateStatus")
+	defer finish()
+
 	now := time.Now()
 
+	var mongoID string
+	err := r.pgDB.QueryRowContext(ctx, "SELECT mongo_achievement_id FROM achievement_references WHERE id = $1", id).Scan(&mongoID)
+	if err != nil {
+		return err
+	}
+
+	// Status berlaku untuk semua baris yang merujuk dokumen yang sama (achievement tim).
 	var query string
 	var args []interface{}
 
 	if status == "submitted" {
-		query = `UPDATE achievement_references SET status = $1, submitted_at = $2, updated_at = $3 WHERE id = $4 AND status != $5`
-		args = []interface{}{status, now, now, id, model.StatusDeleted}
+		query = `UPDATE achievement_references SET status = $1, submitted_at = $2, updated_at = $3 WHERE mongo_achievement_id = $4 AND status != $5`
+		args = []interface{}{status, now, now, mongoID, model.StatusDeleted}
 	} else if status == "verified" || status == "rejected" {
-		query = `UPDATE achievement_references SET status = $1, verified_at = $2, verified_by = $3, rejection_note = $4, updated_at = $5 WHERE id = $6 AND status != $7`
-		args = []interface{}{status, now, verifierID, note, now, id, model.StatusDeleted}
+		query = `UPDATE achievement_references SET status = $1, verified_at = $2, verified_by = $3, rejection_note = $4, updated_at = $5 WHERE mongo_achievement_id = $6 AND status != $7`
+		args = []interface{}{status, now, verifierID, note, now, mongoID, model.StatusDeleted}
 	} else {
-		query = `UPDATE achievement_references SET status = $1, updated_at = $2 WHERE id = $3 AND status != $4`
-		args = []interface{}{status, now, id, model.StatusDeleted}
+		query = `UPDATE achievement_references SET status = $1, updated_at = $2 WHERE mongo_achievement_id = $3 AND status != $4`
+		args = []interface{}{status, now, mongoID, model.StatusDeleted}
 	}
 
-	_, err := r.pgDB.Exec(query, args...)
+	_, err = r.pgDB.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
 
 	if status == "verified" {
-		var mongoID string
-		err := r.pgDB.QueryRow("SELECT mongo_achievement_id FROM achievement_references WHERE id = $1", id).Scan(&mongoID)
-		if err != nil {
-			return err
-		}
+		return r.setVerifiedPoints(ctx, mongoID, points)
+	}
 
-		objID, _ := primitive.ObjectIDFromHex(mongoID)
-		coll := r.mongoDB.Collection("achievements")
+	return nil
+}
 
-		update := bson.M{
-			"$set": bson.M{"points": points},
-		}
+// setVerifiedPoints menyimpan poin achievement terverifikasi beserta
+// pembagiannya per anggota tim.
+func (r *AchievementRepo) setVerifiedPoints(ctx context.Context, mongoID string, points int) error {
+	memberPoints, err := r.memberPoints(ctx, mongoID, points)
+	if err != nil {
+		return err
+	}
 
-		if _, err := coll.UpdateOne(context.TODO(), bson.M{"_id": objID}, update); err != nil {
-			return err
-		}
+	objID, _ := primitive.ObjectIDFromHex(mongoID)
+	coll := r.mongoDB.Collection("achievements")
+
+	set := bson.M{"points": points}
+	if memberPoints != nil {
+		set["memberPoints"] = memberPoints
+	}
+	update := bson.M{
+		"$set": set,
 	}
 
-	return nil
+	_, err = coll.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	return err
 }
 
-func (r *AchievementRepo) Delete(id uuid.UUID) error {
-	query := `UPDATE achievement_references SET status = $1 WHERE id = $2`
-	_, err := r.pgDB.Exec(query, model.StatusDeleted, id)
+func (r *AchievementRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.Delete")
+	defer finish()
+
+	query := `
+		UPDATE achievement_references SET status = $1
+		WHERE mongo_achievement_id = (SELECT mongo_achievement_id FROM achievement_references WHERE id = $2)`
+	_, err := r.pgDB.ExecContext(ctx, query, model.StatusDeleted, id)
 	return err
 }
 
-func (r *AchievementRepo) AddAttachment(id uuid.UUID, attachment model.Attachment) error {
+func (r *AchievementRepo) AddAttachment(ctx context.Context, id uuid.UUID, attachment model.Attachment) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.AddAttachment")
+	defer finish()
+
 	var mongoID string
-	err := r.pgDB.QueryRow("SELECT mongo_achievement_id FROM achievement_references WHERE id = $1 AND status != $2", id, model.StatusDeleted).Scan(&mongoID)
+	err := r.pgDB.QueryRowContext(ctx, "SELECT mongo_achievement_id FROM achievement_references WHERE id = $1 AND status != $2", id, model.StatusDeleted).Scan(&mongoID)
 	if err != nil {
 		return err
 	}
@@ -476,7 +576,7 @@ func (r *AchievementRepo) AddAttachment(id uuid.UUID, attachment model.Attachmen
 	update := bson.M{
 		"$push": bson.M{"attachments": attachment},
 	}
-	_, err = coll.UpdateOne(context.TODO(), bson.M{"_id": objID}, update)
+	_, err = coll.UpdateOne(ctx, bson.M{"_id": objID}, update)
 	return err
 }
 
@@ -506,12 +606,17 @@ func (r *AchievementRepo) mapToResponse(ref model.AchievementReference, mongoDoc
 		Tags:            tags,
 		Points:          mongoDoc.Points,
 		RejectionNote:   ref.RejectionNote,
+		TeamRole:        ref.TeamRole,
+		Period:          ref.PeriodLabel,
 		CreatedAt:       ref.CreatedAt,
 		UpdatedAt:       ref.UpdatedAt,
 	}
 }
 
-func (r *AchievementRepo) GetOwnerID(achievementID uuid.UUID) (uuid.UUID, error) {
+func (r *AchievementRepo) GetOwnerID(ctx context.Context, achievementID uuid.UUID) (uuid.UUID, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.GetOwnerID")
+	defer finish()
+
 	query := `
 		SELECT s.user_id
 		FROM achievement_references ar
@@ -519,56 +624,109 @@ func (r *AchievementRepo) GetOwnerID(achievementID uuid.UUID) (uuid.UUID, error)
 		WHERE ar.id = $1 AND ar.status != $2`
 
 	var ownerID uuid.UUID
-	err := r.pgDB.QueryRow(query, achievementID, model.StatusDeleted).Scan(&ownerID)
+	err := r.pgDB.QueryRowContext(ctx, query, achievementID, model.StatusDeleted).Scan(&ownerID)
 	if err != nil {
 		return uuid.Nil, err
 	}
 	return ownerID, nil
 }
 
-func (r *AchievementRepo) IsAdvisor(lecturerUserID uuid.UUID, achievementID uuid.UUID) (bool, error) {
+// IsAdvisor memeriksa apakah dosen adalah dosen wali pemilik achievement atau
+// penerima delegasinya yang sedang berlaku.
+func (r *AchievementRepo) IsAdvisor(ctx context.Context, lecturerUserID uuid.UUID, achievementID uuid.UUID) (bool, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.IsAdvisor")
+	defer finish()
+
 	query := `
 		SELECT COUNT(*)
 		FROM achievement_references ar
 		JOIN students s ON s.id = ar.student_id
 		JOIN lecturers l ON l.id = s.advisor_id
-		WHERE ar.id = $1 AND l.user_id = $2 AND ar.status != $3`
+		WHERE ar.id = $1 AND ar.status != $3 AND` + fmt.Sprintf(advisorOrDelegateScope, "l", "$2")
 
 	var count int64
-	err := r.pgDB.QueryRow(query, achievementID, lecturerUserID, model.StatusDeleted).Scan(&count)
+	err := r.pgDB.QueryRowContext(ctx, query, achievementID, lecturerUserID, model.StatusDeleted).Scan(&count)
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *AchievementRepo) GetStatus(id uuid.UUID) (string, error) {
+func (r *AchievementRepo) GetStatus(ctx context.Context, id uuid.UUID) (string, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.GetStatus")
+	defer finish()
+
 	var status string
 	query := `SELECT status FROM achievement_references WHERE id = $1 AND status != $2`
-	err := r.pgDB.QueryRow(query, id, model.StatusDeleted).Scan(&status)
+	err := r.pgDB.QueryRowContext(ctx, query, id, model.StatusDeleted).Scan(&status)
 	if err != nil {
 		return "", err
 	}
 	return status, nil
 }
 
-func (r *AchievementRepo) GetHistory(id uuid.UUID) (*model.AchievementHistoryResponse, error) {
+// HasFrozenStudent memeriksa apakah achievement, termasuk baris anggota
+// timnya, dimiliki mahasiswa yang rekam SKP-nya sudah dibekukan.
+func (r *AchievementRepo) HasFrozenStudent(ctx context.Context, id uuid.UUID) (bool, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.HasFrozenStudent")
+	defer finish()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM achievement_references ar
+			JOIN achievement_references t ON t.mongo_achievement_id = ar.mongo_achievement_id AND t.status != $2
+			JOIN students s ON s.id = t.student_id
+			WHERE ar.id = $1 AND s.skp_frozen_at IS NOT NULL
+		)`
+	var frozen bool
+	err := r.pgDB.QueryRowContext(ctx, query, id, model.StatusDeleted).Scan(&frozen)
+	return frozen, err
+}
+
+func (r *AchievementRepo) GetAchievementType(ctx context.Context, id uuid.UUID) (string, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.GetAchievementType")
+	defer finish()
+
+	var mongoID string
+	err := r.pgDB.QueryRowContext(ctx, "SELECT mongo_achievement_id FROM achievement_references WHERE id = $1", id).Scan(&mongoID)
+	if err != nil {
+		return "", err
+	}
+
+	var doc struct {
+		AchievementType string `bson:"achievementType"`
+	}
+	objID, _ := primitive.ObjectIDFromHex(mongoID)
+	coll := r.mongoDB.Collection("achievements")
+	err = coll.FindOne(ctx, bson.M{"_id": objID}).Decode(&doc)
+	if err != nil {
+		return "", err
+	}
+	return doc.AchievementType, nil
+}
+
+func (r *AchievementRepo) GetHistory(ctx context.Context, id uuid.UUID) (*model.AchievementHistoryResponse, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.GetHistory")
+	defer finish()
+
 	query := `
 		SELECT ar.id, ar.mongo_achievement_id, ar.status, ar.created_at, ar.submitted_at, ar.verified_at, ar.rejection_note,
-		       u.full_name
+		       u.full_name, ar.revoked_at, ar.revocation_reason, ru.full_name
 		FROM achievement_references ar
 		LEFT JOIN users u ON u.id = ar.verified_by
+		LEFT JOIN users ru ON ru.id = ar.revoked_by
 		WHERE ar.id = $1 AND ar.status != $2`
 
 	var mongoID string
 	var status string
 	var createdAt time.Time
-	var submittedAt, verifiedAt sql.NullTime
-	var rejectionNote, verifierName sql.NullString
+	var submittedAt, verifiedAt, revokedAt sql.NullTime
+	var rejectionNote, verifierName, revocationReason, revokerName sql.NullString
 	var pgID uuid.UUID
 
-	err := r.pgDB.QueryRow(query, id, model.StatusDeleted).Scan(
+	err := r.pgDB.QueryRowContext(ctx, query, id, model.StatusDeleted).Scan(
 		&pgID, &mongoID, &status, &createdAt, &submittedAt, &verifiedAt, &rejectionNote, &verifierName,
+		&revokedAt, &revocationReason, &revokerName,
 	)
 	if err != nil {
 		return nil, err
@@ -577,7 +735,7 @@ func (r *AchievementRepo) GetHistory(id uuid.UUID) (*model.AchievementHistoryRes
 	var mongoDetail model.AchievementMongo
 	objID, _ := primitive.ObjectIDFromHex(mongoID)
 	coll := r.mongoDB.Collection("achievements")
-	err = coll.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&mongoDetail)
+	err = coll.FindOne(ctx, bson.M{"_id": objID}).Decode(&mongoDetail)
 	if err != nil {
 		return nil, errors.New("detail data missing in NoSQL")
 	}
@@ -608,6 +766,782 @@ func (r *AchievementRepo) GetHistory(id uuid.UUID) (*model.AchievementHistoryRes
 	if verifiedAt.Valid {
 		resp.VerifiedAt = &verifiedAt.Time
 	}
+	if revokedAt.Valid {
+		resp.RevokedAt = &revokedAt.Time
+		resp.RevokerName = revokerName.String
+		resp.RevocationReason = revocationReason.String
+	}
+
+	events, err := r.findEvents(ctx, mongoID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Events = events
 
 	return resp, nil
 }
+
+// findEvents memuat riwayat semua baris yang merujuk dokumen yang sama agar
+// anggota tim melihat riwayat yang sama dengan ketuanya.
+func (r *AchievementRepo) findEvents(ctx context.Context, mongoID string) ([]model.AchievementEvent, error) {
+	query := `
+		SELECT e.id, e.achievement_id, e.action, e.actor_id, u.full_name, e.on_behalf_of, ob.full_name,
+			e.note, e.points, e.created_at
+		FROM achievement_events e
+		JOIN achievement_references ar ON ar.id = e.achievement_id
+		LEFT JOIN users u ON u.id = e.actor_id
+		LEFT JOIN users ob ON ob.id = e.on_behalf_of
+		WHERE ar.mongo_achievement_id = $1
+		ORDER BY e.created_at`
+	rows, err := r.pgDB.QueryContext(ctx, query, mongoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.AchievementEvent{}
+	for rows.Next() {
+		var e model.AchievementEvent
+		var actorID, onBehalfOf uuid.NullUUID
+		var actorName, onBehalfOfName sql.NullString
+		var points sql.NullInt64
+		err := rows.Scan(&e.ID, &e.AchievementID, &e.Action, &actorID, &actorName, &onBehalfOf, &onBehalfOfName,
+			&e.Note, &points, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if actorID.Valid {
+			e.ActorID = &actorID.UUID
+		}
+		e.ActorName = actorName.String
+		if onBehalfOf.Valid {
+			e.OnBehalfOf = &onBehalfOf.UUID
+		}
+		e.OnBehalfOfName = onBehalfOfName.String
+		if points.Valid {
+			p := int(points.Int64)
+			e.Points = &p
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func invalidDate(field string, err error) error {
+	return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+		{Field: field, Rule: "datetime", Param: "2006-01-02"},
+	}).Wrap(err)
+}
+
+// FindCertificationAlerts mengembalikan sertifikasi terverifikasi dengan
+// validUntil <= before yang belum pernah diberi notifikasi alert. Achievement
+// terverifikasi diambil dari PostgreSQL lebih dulu agar dokumen berstatus lain
+// tidak ikut dipindai setiap kali.
+func (r *AchievementRepo) FindCertificationAlerts(ctx context.Context, alert string, before time.Time) ([]model.CertificationAlert, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindCertificationAlerts")
+	defer finish()
+
+	query := `
+		SELECT ar.id, ar.mongo_achievement_id, s.user_id
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		WHERE ar.status = $1`
+	rows, err := r.pgDB.QueryContext(ctx, query, model.StatusVerified)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var verified []model.CertificationAlert
+	var oids []primitive.ObjectID
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var a model.CertificationAlert
+		if err := rows.Scan(&a.AchievementID, &a.MongoID, &a.StudentUserID); err != nil {
+			return nil, err
+		}
+		verified = append(verified, a)
+		if seen[a.MongoID] {
+			continue
+		}
+		seen[a.MongoID] = true
+		if oid, err := primitive.ObjectIDFromHex(a.MongoID); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	type certDoc struct {
+		ID      primitive.ObjectID `bson:"_id"`
+		Title   string             `bson:"title"`
+		Details struct {
+			CertificationName string    `bson:"certificationName"`
+			ValidUntil        time.Time `bson:"validUntil"`
+		} `bson:"details"`
+	}
+	docs := make(map[string]certDoc)
+	projection := bson.M{"title": 1, "details.certificationName": 1, "details.validUntil": 1}
+	coll := r.mongoDB.Collection("achievements")
+	for start := 0; start < len(oids); start += batchSize {
+		end := min(start+batchSize, len(oids))
+		filter := bson.M{
+			"_id":                bson.M{"$in": oids[start:end]},
+			"achievementType":    model.TypeCertification,
+			"details.validUntil": bson.M{"$lte": before},
+			"alerts." + alert:    bson.M{"$exists": false},
+		}
+		cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(projection))
+		if err != nil {
+			return nil, err
+		}
+		var batch []certDoc
+		err = cursor.All(ctx, &batch)
+		cursor.Close(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range batch {
+			docs[doc.ID.Hex()] = doc
+		}
+	}
+
+	var alerts []model.CertificationAlert
+	for _, a := range verified {
+		doc, ok := docs[a.MongoID]
+		if !ok {
+			continue
+		}
+		a.Title = doc.Title
+		a.CertificationName = doc.Details.CertificationName
+		a.ValidUntil = doc.Details.ValidUntil
+		alerts = append(alerts, a)
+	}
+	return alerts, nil
+}
+
+// MarkCertificationAlerted menandai alert yang sudah dikirim agar tidak dikirim ulang.
+func (r *AchievementRepo) MarkCertificationAlerted(ctx context.Context, mongoID string, alerts ...string) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.MarkCertificationAlerted")
+	defer finish()
+
+	objID, err := primitive.ObjectIDFromHex(mongoID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	set := bson.M{}
+	for _, alert := range alerts {
+		set["alerts."+alert] = now
+	}
+
+	_, err = r.mongoDB.Collection("achievements").UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": set})
+	return err
+}
+
+// memberPoints membagi poin achievement tim sesuai points_share tiap anggota.
+// Anggota diurutkan menurut student_id agar sisa pembulatan sama dengan yang
+// ditampilkan teamWithPoints.
+// Mengembalikan nil untuk achievement individu.
+func (r *AchievementRepo) memberPoints(ctx context.Context, mongoID string, points int) (bson.A, error) {
+	rows, err := r.pgDB.QueryContext(ctx, `
+		SELECT student_id, points_share
+		FROM achievement_references
+		WHERE mongo_achievement_id = $1 AND status != $2 AND team_role IS NOT NULL
+		ORDER BY student_id`, mongoID, model.StatusDeleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var studentIDs []uuid.UUID
+	var shares []int
+	for rows.Next() {
+		var studentID uuid.UUID
+		var share sql.NullInt64
+		if err := rows.Scan(&studentID, &share); err != nil {
+			return nil, err
+		}
+		studentIDs = append(studentIDs, studentID)
+		shares = append(shares, int(share.Int64))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(studentIDs) == 0 {
+		return nil, nil
+	}
+
+	split := helper.SplitPoints(points, shares)
+	result := make(bson.A, len(studentIDs))
+	for i, studentID := range studentIDs {
+		result[i] = bson.M{"studentId": studentID.String(), "points": split[i]}
+	}
+	return result, nil
+}
+
+// GetTeamRole mengembalikan peran baris achievement dalam tim, atau string
+// kosong untuk achievement individu.
+func (r *AchievementRepo) GetTeamRole(ctx context.Context, id uuid.UUID) (string, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.GetTeamRole")
+	defer finish()
+
+	var role sql.NullString
+	query := `SELECT team_role FROM achievement_references WHERE id = $1 AND status != $2`
+	if err := r.pgDB.QueryRowContext(ctx, query, id, model.StatusDeleted).Scan(&role); err != nil {
+		return "", err
+	}
+	return role.String, nil
+}
+
+func (r *AchievementRepo) FindTeam(ctx context.Context, id uuid.UUID) ([]model.TeamMember, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindTeam")
+	defer finish()
+
+	query := `
+		SELECT ar.id, s.id, s.student_id, u.full_name, ar.team_role, ar.points_share, ar.confirmed_at
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		JOIN users u ON u.id = s.user_id
+		WHERE ar.mongo_achievement_id = (SELECT mongo_achievement_id FROM achievement_references WHERE id = $1)
+		  AND ar.status != $2 AND ar.team_role IS NOT NULL
+		ORDER BY ar.team_role = $3 DESC, u.full_name`
+
+	rows, err := r.pgDB.QueryContext(ctx, query, id, model.StatusDeleted, model.TeamRoleLeader)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []model.TeamMember
+	for rows.Next() {
+		var m model.TeamMember
+		var share sql.NullInt64
+		var confirmedAt sql.NullTime
+		if err := rows.Scan(&m.AchievementID, &m.StudentID, &m.NIM, &m.StudentName, &m.Role, &share, &confirmedAt); err != nil {
+			return nil, err
+		}
+		m.PointsShare = int(share.Int64)
+		if confirmedAt.Valid {
+			m.Confirmed = true
+			m.ConfirmedAt = &confirmedAt.Time
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// SetTeam mengganti susunan tim achievement id (baris milik ketua) dalam satu
+// transaksi. Anggota yang dikeluarkan dihapus, dan konfirmasi direset bila
+// peran atau bagian poinnya berubah. Mengembalikan anggota yang baru ditambahkan.
+func (r *AchievementRepo) SetTeam(ctx context.Context, id uuid.UUID, members []model.TeamMember) ([]model.TeamMember, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.SetTeam")
+	defer finish()
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var mongoID string
+	err = tx.QueryRowContext(ctx,
+		`SELECT mongo_achievement_id FROM achievement_references WHERE id = $1 AND status != $2 FOR UPDATE`,
+		id, model.StatusDeleted).Scan(&mongoID)
+	if err != nil {
+		return nil, err
+	}
+
+	type existingRow struct {
+		id    uuid.UUID
+		role  string
+		share int
+	}
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, student_id, COALESCE(team_role, ''), COALESCE(points_share, 0)
+		FROM achievement_references
+		WHERE mongo_achievement_id = $1 AND status != $2`, mongoID, model.StatusDeleted)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[uuid.UUID]existingRow)
+	for rows.Next() {
+		var row existingRow
+		var studentID uuid.UUID
+		if err := rows.Scan(&row.id, &studentID, &row.role, &row.share); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		existing[studentID] = row
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	keep := make(map[uuid.UUID]bool, len(members))
+	var added []model.TeamMember
+
+	for _, m := range members {
+		keep[m.StudentID] = true
+
+		var confirmedAt interface{}
+		if m.Role == model.TeamRoleLeader {
+			confirmedAt = now
+		}
+
+		if row, ok := existing[m.StudentID]; ok {
+			query := `
+				UPDATE achievement_references
+				SET team_role = $1, points_share = $2, updated_at = $3,
+				    confirmed_at = CASE WHEN $4::timestamp IS NOT NULL THEN $4::timestamp
+				                        WHEN team_role = $1 AND points_share = $2 THEN confirmed_at END
+				WHERE id = $5`
+			if _, err := tx.ExecContext(ctx, query, m.Role, m.PointsShare, now, confirmedAt, row.id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		query := `
+			INSERT INTO achievement_references (student_id, mongo_achievement_id, status, team_role, points_share, confirmed_at, event_date, period_id, created_at, updated_at)
+			SELECT $1, $2, $3, $4, $5, $6, event_date, period_id, $7, $7
+			FROM achievement_references WHERE id = $8
+			RETURNING id`
+		if err := tx.QueryRowContext(ctx, query, m.StudentID, mongoID, model.StatusDraft, m.Role, m.PointsShare, confirmedAt, now, id).Scan(&m.AchievementID); err != nil {
+			return nil, err
+		}
+		added = append(added, m)
+	}
+
+	for studentID, row := range existing {
+		if keep[studentID] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE achievement_references SET status = $1, updated_at = $2 WHERE id = $3`,
+			model.StatusDeleted, now, row.id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+func (r *AchievementRepo) ConfirmParticipation(ctx context.Context, id uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.ConfirmParticipation")
+	defer finish()
+
+	result, err := r.pgDB.ExecContext(ctx, `
+		UPDATE achievement_references SET confirmed_at = $1, updated_at = $1
+		WHERE id = $2 AND team_role = $3 AND status != $4`,
+		time.Now(), id, model.TeamRoleMember, model.StatusDeleted)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeclineParticipation mengeluarkan anggota dari tim; ketua perlu menyusun
+// ulang pembagian poin sebelum submit.
+func (r *AchievementRepo) DeclineParticipation(ctx context.Context, id uuid.UUID) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.DeclineParticipation")
+	defer finish()
+
+	result, err := r.pgDB.ExecContext(ctx, `
+		UPDATE achievement_references SET status = $1, updated_at = $2
+		WHERE id = $3 AND team_role = $4 AND status != $1`,
+		model.StatusDeleted, time.Now(), id, model.TeamRoleMember)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SaveFingerprints menyimpan sidik achievement agar bisa dicocokkan dengan
+// achievement yang disubmit berikutnya.
+func (r *AchievementRepo) SaveFingerprints(ctx context.Context, id uuid.UUID, fingerprints []string) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.SaveFingerprints")
+	defer finish()
+
+	var mongoID string
+	err := r.pgDB.QueryRowContext(ctx, "SELECT mongo_achievement_id FROM achievement_references WHERE id = $1", id).Scan(&mongoID)
+	if err != nil {
+		return err
+	}
+
+	objID, _ := primitive.ObjectIDFromHex(mongoID)
+	_, err = r.mongoDB.Collection("achievements").UpdateOne(ctx,
+		bson.M{"_id": objID},
+		bson.M{"$set": bson.M{"fingerprints": fingerprints}})
+	return err
+}
+
+// FindFingerprintTargets memuat satu baris per achievement yang belum
+// dihapus; untuk achievement tim dipilih baris ketua.
+func (r *AchievementRepo) FindFingerprintTargets(ctx context.Context) ([]uuid.UUID, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindFingerprintTargets")
+	defer finish()
+
+	rows, err := r.pgDB.QueryContext(ctx, `
+		SELECT DISTINCT ON (mongo_achievement_id) id
+		FROM achievement_references
+		WHERE status != $1
+		ORDER BY mongo_achievement_id, team_role IS NOT NULL AND team_role != $2, created_at`,
+		model.StatusDeleted, model.TeamRoleLeader)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// FindDuplicates mencari achievement lain yang memiliki sidik yang sama.
+// Alasan kecocokan diambil dari prefix sidik, mis. "certification_number".
+func (r *AchievementRepo) FindDuplicates(ctx context.Context, id uuid.UUID, fingerprints []string) ([]model.DuplicateSuspect, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindDuplicates")
+	defer finish()
+
+	if len(fingerprints) == 0 {
+		return nil, nil
+	}
+
+	var mongoID string
+	err := r.pgDB.QueryRowContext(ctx, "SELECT mongo_achievement_id FROM achievement_references WHERE id = $1", id).Scan(&mongoID)
+	if err != nil {
+		return nil, err
+	}
+	objID, _ := primitive.ObjectIDFromHex(mongoID)
+
+	coll := r.mongoDB.Collection("achievements")
+	cursor, err := coll.Find(ctx,
+		bson.M{"_id": bson.M{"$ne": objID}, "fingerprints": bson.M{"$in": fingerprints}},
+		options.Find().SetProjection(bson.M{"title": 1, "fingerprints": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID           primitive.ObjectID `bson:"_id"`
+		Title        string             `bson:"title"`
+		Fingerprints []string           `bson:"fingerprints"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	own := make(map[string]bool, len(fingerprints))
+	for _, fp := range fingerprints {
+		own[fp] = true
+	}
+
+	type match struct {
+		title   string
+		reasons []string
+	}
+	matches := make(map[string]match, len(docs))
+	mongoIDs := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		m := match{title: doc.Title}
+		seen := make(map[string]bool)
+		for _, fp := range doc.Fingerprints {
+			reason, _, _ := strings.Cut(fp, ":")
+			if own[fp] && !seen[reason] {
+				seen[reason] = true
+				m.reasons = append(m.reasons, reason)
+			}
+		}
+		hexID := doc.ID.Hex()
+		matches[hexID] = m
+		mongoIDs = append(mongoIDs, hexID)
+	}
+
+	query := `
+		SELECT ar.id, ar.mongo_achievement_id, ar.status, u.full_name
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		JOIN users u ON u.id = s.user_id
+		WHERE ar.mongo_achievement_id = ANY($1) AND ar.status != $2
+		ORDER BY ar.created_at`
+	rows, err := r.pgDB.QueryContext(ctx, query, pq.Array(mongoIDs), model.StatusDeleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suspects []model.DuplicateSuspect
+	for rows.Next() {
+		var d model.DuplicateSuspect
+		var hexID string
+		if err := rows.Scan(&d.AchievementID, &hexID, &d.Status, &d.StudentName); err != nil {
+			return nil, err
+		}
+		m := matches[hexID]
+		d.Title = m.title
+		d.Reasons = m.reasons
+		suspects = append(suspects, d)
+	}
+	return suspects, rows.Err()
+}
+
+// SaveDuplicateFlags mengganti daftar dugaan duplikat milik achievement id.
+func (r *AchievementRepo) SaveDuplicateFlags(ctx context.Context, id uuid.UUID, suspects []model.DuplicateSuspect) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.SaveDuplicateFlags")
+	defer finish()
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM achievement_duplicate_flags WHERE achievement_id = $1`, id); err != nil {
+		return err
+	}
+	for _, d := range suspects {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO achievement_duplicate_flags (achievement_id, matched_achievement_id, reasons)
+			VALUES ($1, $2, $3)`, id, d.AchievementID, pq.Array(d.Reasons))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *AchievementRepo) FindDuplicateFlags(ctx context.Context, id uuid.UUID) ([]model.DuplicateSuspect, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindDuplicateFlags")
+	defer finish()
+
+	query := `
+		SELECT ar.id, ar.mongo_achievement_id, ar.status, u.full_name, f.reasons
+		FROM achievement_duplicate_flags f
+		JOIN achievement_references ar ON ar.id = f.matched_achievement_id
+		JOIN students s ON s.id = ar.student_id
+		JOIN users u ON u.id = s.user_id
+		WHERE f.achievement_id = $1 AND ar.status != $2
+		ORDER BY f.created_at`
+	rows, err := r.pgDB.QueryContext(ctx, query, id, model.StatusDeleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suspects []model.DuplicateSuspect
+	var mongoOIDs []primitive.ObjectID
+	var mongoIDs []string
+	for rows.Next() {
+		var d model.DuplicateSuspect
+		var hexID string
+		if err := rows.Scan(&d.AchievementID, &hexID, &d.Status, &d.StudentName, pq.Array(&d.Reasons)); err != nil {
+			return nil, err
+		}
+		suspects = append(suspects, d)
+		mongoIDs = append(mongoIDs, hexID)
+		if oid, err := primitive.ObjectIDFromHex(hexID); err == nil {
+			mongoOIDs = append(mongoOIDs, oid)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(suspects) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.mongoDB.Collection("achievements").Find(ctx,
+		bson.M{"_id": bson.M{"$in": mongoOIDs}},
+		options.Find().SetProjection(bson.M{"title": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	titles := make(map[string]string)
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Title string             `bson:"title"`
+		}
+		if err := cursor.Decode(&doc); err == nil {
+			titles[doc.ID.Hex()] = doc.Title
+		}
+	}
+	for i := range suspects {
+		suspects[i].Title = titles[mongoIDs[i]]
+	}
+	return suspects, nil
+}
+
+// GetAdvisorUserID mengembalikan user_id dosen wali pemilik achievement, atau
+// uuid.Nil bila mahasiswa belum memiliki dosen wali.
+func (r *AchievementRepo) GetAdvisorUserID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.GetAdvisorUserID")
+	defer finish()
+
+	query := `
+		SELECT l.user_id
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		LEFT JOIN lecturers l ON l.id = s.advisor_id
+		WHERE ar.id = $1`
+	var userID uuid.NullUUID
+	if err := r.pgDB.QueryRowContext(ctx, query, id).Scan(&userID); err != nil {
+		return uuid.Nil, err
+	}
+	return userID.UUID, nil
+}
+
+func (r *AchievementRepo) AddEvent(ctx context.Context, event model.AchievementEvent) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.AddEvent")
+	defer finish()
+
+	return insertEvent(ctx, r.pgDB, event)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertEvent(ctx context.Context, db execer, event model.AchievementEvent) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO achievement_events (achievement_id, action, actor_id, on_behalf_of, note, points, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		event.AchievementID, event.Action, event.ActorID, event.OnBehalfOf, event.Note, event.Points, time.Now())
+	return err
+}
+
+// GetVerifierID mengembalikan user yang memverifikasi achievement, atau
+// uuid.Nil bila belum diverifikasi.
+func (r *AchievementRepo) GetVerifierID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.GetVerifierID")
+	defer finish()
+
+	var verifierID uuid.NullUUID
+	query := `SELECT verified_by FROM achievement_references WHERE id = $1 AND status != $2`
+	if err := r.pgDB.QueryRowContext(ctx, query, id, model.StatusDeleted).Scan(&verifierID); err != nil {
+		return uuid.Nil, err
+	}
+	return verifierID.UUID, nil
+}
+
+// FindStudentUserIDs mengembalikan user_id semua mahasiswa yang merujuk
+// dokumen achievement yang sama (satu untuk achievement individu).
+func (r *AchievementRepo) FindStudentUserIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindStudentUserIDs")
+	defer finish()
+
+	query := `
+		SELECT s.user_id
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		WHERE ar.mongo_achievement_id = (SELECT mongo_achievement_id FROM achievement_references WHERE id = $1)
+		  AND ar.status != $2`
+	rows, err := r.pgDB.QueryContext(ctx, query, id, model.StatusDeleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+// Revoke mencabut achievement terverifikasi beserta seluruh baris timnya,
+// mencatat riwayat, lalu menarik kembali poinnya. Mengembalikan poin yang ditarik.
+func (r *AchievementRepo) Revoke(ctx context.Context, id uuid.UUID, actorID uuid.UUID, reason string) (int, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.Revoke")
+	defer finish()
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var mongoID string
+	err = tx.QueryRowContext(ctx,
+		`SELECT mongo_achievement_id FROM achievement_references WHERE id = $1 AND status = $2 FOR UPDATE`,
+		id, model.StatusVerified).Scan(&mongoID)
+	if err != nil {
+		return 0, err
+	}
+
+	objID, _ := primitive.ObjectIDFromHex(mongoID)
+	coll := r.mongoDB.Collection("achievements")
+
+	var doc struct {
+		Points int `bson:"points"`
+	}
+	if err := coll.FindOne(ctx, bson.M{"_id": objID}, options.FindOne().SetProjection(bson.M{"points": 1})).Decode(&doc); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE achievement_references
+		SET status = $1, revoked_at = $2, revoked_by = $3, revocation_reason = $4, updated_at = $2
+		WHERE mongo_achievement_id = $5 AND status = $6`,
+		model.StatusRevoked, now, actorID, reason, mongoID, model.StatusVerified)
+	if err != nil {
+		return 0, err
+	}
+
+	reversed := -doc.Points
+	err = insertEvent(ctx, tx, model.AchievementEvent{
+		AchievementID: id,
+		Action:        model.ActionRevoked,
+		ActorID:       &actorID,
+		Note:          reason,
+		Points:        &reversed,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	// Poin ditarik setelah commit; revokedPoints disimpan sebagai jejak.
+	_, err = coll.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{
+		"$set":   bson.M{"points": 0, "revokedPoints": doc.Points, "updatedAt": now},
+		"$unset": bson.M{"memberPoints": ""},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return doc.Points, nil
+}
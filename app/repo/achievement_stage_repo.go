@@ -0,0 +1,321 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// departmentHeadStageScope mencakup achievement yang punya tahap verifikasi
+// kepala jurusan, dilihat dari jurusan dosen wali mahasiswa. %[1]s adalah
+// placeholder user_id dosen.
+const departmentHeadStageScope = `
+	EXISTS (
+		SELECT 1 FROM achievement_stages st
+		JOIN students s ON s.id = ar.student_id
+		JOIN lecturers adv ON adv.id = s.advisor_id
+		JOIN lecturers h ON h.department_id = adv.department_id
+		WHERE st.achievement_id = ar.id AND st.approver = 'department_head'
+		  AND h.user_id = %[1]s AND h.is_department_head
+	)`
+
+// Submit mengajukan achievement draft: butir perbaikan yang dikerjakan, tahap
+// verifikasi baru, status submitted, dan riwayatnya dicatat dalam satu
+// transaksi. Mengembalikan jumlah butir perbaikan yang masih terbuka, dan
+// sql.ErrNoRows bila achievement sudah bukan draft.
+func (r *AchievementRepo) Submit(ctx context.Context, id uuid.UUID, actorID uuid.UUID, responses map[uuid.UUID]string, chainName string, stages []model.VerificationStage) (int, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.Submit")
+	defer finish()
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Submit bersamaan menunggu kunci baris ini lalu tidak lagi menemukan draft.
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE achievement_references SET status = $2, submitted_at = $3, updated_at = $3
+		WHERE mongo_achievement_id = `+sameDocument+` AND status = $4`,
+		id, model.StatusSubmitted, now, model.StatusDraft)
+	if err != nil {
+		return 0, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	open, err := addressChangesTx(ctx, tx, id, actorID, responses, now)
+	if err != nil {
+		return 0, err
+	}
+	if err := startVerificationTx(ctx, tx, id, chainName, stages, now); err != nil {
+		return 0, err
+	}
+	err = insertEvent(ctx, tx, model.AchievementEvent{
+		AchievementID: id,
+		Action:        model.ActionSubmitted,
+		ActorID:       &actorID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return open, tx.Commit()
+}
+
+// startVerificationTx menyalin tahap rantai verifikasi untuk achievement yang
+// disubmit. Tahap dari pengajuan sebelumnya dipindahkan ke
+// achievement_stage_rounds sebagai putaran berikutnya.
+func startVerificationTx(ctx context.Context, tx *sql.Tx, id uuid.UUID, chainName string, stages []model.VerificationStage, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO achievement_stage_rounds (achievement_id, round, position, chain_name, name, approver,
+			sla_hours, started_at, decision, decided_by, note, points, decided_at, escalated_at, archived_at)
+		SELECT st.achievement_id,
+			(SELECT COALESCE(MAX(round), 0) + 1 FROM achievement_stage_rounds WHERE achievement_id = $1),
+			st.position, st.chain_name, st.name, st.approver, st.sla_hours, st.started_at, st.decision,
+			st.decided_by, st.note, st.points, st.decided_at, st.escalated_at, $2
+		FROM achievement_stages st
+		WHERE st.achievement_id = $1`, id, now)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM achievement_stages WHERE achievement_id = $1`, id); err != nil {
+		return err
+	}
+
+	for i, st := range stages {
+		var startedAt *time.Time
+		if i == 0 {
+			startedAt = &now
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO achievement_stages (achievement_id, position, chain_name, name, approver, sla_hours, started_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			id, i+1, chainName, st.Name, st.Approver, st.SLAHours, startedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindStages memuat tahap verifikasi dokumen achievement, termasuk bila
+// diminta lewat baris anggota tim.
+func (r *AchievementRepo) FindStages(ctx context.Context, id uuid.UUID) ([]model.AchievementStage, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindStages")
+	defer finish()
+
+	query := achievementStageSelect + `
+		WHERE ar.mongo_achievement_id = (SELECT mongo_achievement_id FROM achievement_references WHERE id = $1)
+		ORDER BY st.position`
+	rows, err := r.pgDB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stages := []model.AchievementStage{}
+	for rows.Next() {
+		st, err := scanAchievementStage(rows)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, *st)
+	}
+	return stages, rows.Err()
+}
+
+// FindPreviousStages memuat tahap verifikasi dari pengajuan sebelumnya,
+// diurutkan per putaran.
+func (r *AchievementRepo) FindPreviousStages(ctx context.Context, id uuid.UUID) ([]model.AchievementStage, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindPreviousStages")
+	defer finish()
+
+	rows, err := r.pgDB.QueryContext(ctx, `
+		SELECT st.position, st.chain_name, st.name, st.approver, st.sla_hours, st.started_at,
+			st.decision, u.full_name, st.note, st.points, st.decided_at, st.round
+		FROM achievement_stage_rounds st
+		JOIN achievement_references ar ON ar.id = st.achievement_id
+		LEFT JOIN users u ON u.id = st.decided_by
+		WHERE ar.mongo_achievement_id = `+sameDocument+`
+		ORDER BY st.round, st.position`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stages := []model.AchievementStage{}
+	for rows.Next() {
+		var round int
+		st, err := scanAchievementStage(rows, &round)
+		if err != nil {
+			return nil, err
+		}
+		st.Round = round
+		stages = append(stages, *st)
+	}
+	return stages, rows.Err()
+}
+
+// GetCurrentStage mengembalikan tahap pertama yang belum diputus, atau
+// sql.ErrNoRows bila achievement tidak punya tahap tersisa.
+func (r *AchievementRepo) GetCurrentStage(ctx context.Context, id uuid.UUID) (*model.AchievementStage, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.GetCurrentStage")
+	defer finish()
+
+	query := achievementStageSelect + `
+		WHERE st.achievement_id = $1 AND st.decided_at IS NULL
+		ORDER BY st.position
+		LIMIT 1`
+	return scanAchievementStage(r.pgDB.QueryRowContext(ctx, query, id))
+}
+
+// DecideVerification mencatat keputusan atas tahap yang sedang berjalan,
+// status achievement bila tahap tersebut yang terakhir, dan riwayatnya dalam
+// satu transaksi. Mengembalikan status achievement setelah keputusan, dan
+// sql.ErrNoRows bila achievement tidak lagi menunggu keputusan tahap tersebut.
+func (r *AchievementRepo) DecideVerification(ctx context.Context, id uuid.UUID, stage *model.AchievementStage, actorID uuid.UUID, onBehalfOf *uuid.UUID, decision, note string, points int) (string, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.DecideVerification")
+	defer finish()
+
+	tx, err := r.pgDB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	d := model.BulkDecision{
+		ID:            id,
+		StagePosition: stage.Position,
+		StageName:     stage.Name,
+		Decision:      decision,
+		Note:          note,
+		Points:        points,
+		OnBehalfOf:    onBehalfOf,
+	}
+	status, mongoID, err := decideVerificationTx(ctx, tx, actorID, d, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	// Poin di MongoDB ditulis setelah commit.
+	if status == string(model.StatusVerified) {
+		return status, r.setVerifiedPoints(ctx, mongoID, points)
+	}
+	return status, nil
+}
+
+func decideStageTx(ctx context.Context, db execer, id uuid.UUID, position int, actorID uuid.UUID, decision, note string, points *int, now time.Time) (bool, error) {
+	result, err := db.ExecContext(ctx, `
+		UPDATE achievement_stages
+		SET decision = $1, decided_by = $2, note = $3, points = $4, decided_at = $5
+		WHERE achievement_id = $6 AND position = $7 AND decided_at IS NULL`,
+		decision, actorID, note, points, now, id, position)
+	if err != nil {
+		return false, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return false, sql.ErrNoRows
+	}
+
+	if decision != model.StageApproved {
+		return true, nil
+	}
+	result, err = db.ExecContext(ctx, `
+		UPDATE achievement_stages SET started_at = $1
+		WHERE achievement_id = $2 AND position = $3`,
+		now, id, position+1)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows == 0, nil
+}
+
+// IsDepartmentHead memeriksa apakah dosen adalah kepala jurusan dari dosen
+// wali pemilik achievement.
+func (r *AchievementRepo) IsDepartmentHead(ctx context.Context, lecturerUserID uuid.UUID, achievementID uuid.UUID) (bool, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.IsDepartmentHead")
+	defer finish()
+
+	query := `
+		SELECT COUNT(*)
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		JOIN lecturers adv ON adv.id = s.advisor_id
+		JOIN lecturers h ON h.department_id = adv.department_id
+		WHERE ar.id = $1 AND h.user_id = $2 AND h.is_department_head AND ar.status != $3`
+
+	var count int64
+	err := r.pgDB.QueryRowContext(ctx, query, achievementID, lecturerUserID, model.StatusDeleted).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsStageApprover memeriksa apakah dosen terlibat sebagai kepala jurusan
+// dalam rantai verifikasi achievement.
+func (r *AchievementRepo) IsStageApprover(ctx context.Context, lecturerUserID uuid.UUID, achievementID uuid.UUID) (bool, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.IsStageApprover")
+	defer finish()
+
+	query := `
+		SELECT COUNT(*)
+		FROM achievement_references ar
+		WHERE ar.id = $1 AND ar.status != $3 AND` + fmt.Sprintf(departmentHeadStageScope, "$2")
+
+	var count int64
+	err := r.pgDB.QueryRowContext(ctx, query, achievementID, lecturerUserID, model.StatusDeleted).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+const achievementStageSelect = `
+	SELECT st.position, st.chain_name, st.name, st.approver, st.sla_hours, st.started_at,
+		st.decision, u.full_name, st.note, st.points, st.decided_at
+	FROM achievement_stages st
+	JOIN achievement_references ar ON ar.id = st.achievement_id
+	LEFT JOIN users u ON u.id = st.decided_by`
+
+// scanAchievementStage membaca kolom achievementStageSelect; extra menampung
+// kolom tambahan setelahnya.
+func scanAchievementStage(row rowScanner, extra ...interface{}) (*model.AchievementStage, error) {
+	var st model.AchievementStage
+	var startedAt, decidedAt sql.NullTime
+	var decision, deciderName sql.NullString
+	var points sql.NullInt64
+	dest := []interface{}{&st.Position, &st.ChainName, &st.Name, &st.Approver, &st.SLAHours, &startedAt,
+		&decision, &deciderName, &st.Note, &points, &decidedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	st.Decision = decision.String
+	st.DeciderName = deciderName.String
+	if points.Valid {
+		p := int(points.Int64)
+		st.Points = &p
+	}
+	if decidedAt.Valid {
+		st.DecidedAt = &decidedAt.Time
+	}
+	if startedAt.Valid {
+		st.StartedAt = &startedAt.Time
+		due := startedAt.Time.Add(time.Duration(st.SLAHours) * time.Hour)
+		st.DueAt = &due
+		st.Overdue = !decidedAt.Valid && time.Now().After(due)
+	}
+	return &st, nil
+}
@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"time"
+)
+
+type AchievementTypeRepository interface {
+	FindAll(ctx context.Context, includeInactive bool) ([]model.AchievementType, error)
+	FindByCode(ctx context.Context, code string) (*model.AchievementType, error)
+	Create(ctx context.Context, t *model.AchievementType) error
+	Update(ctx context.Context, t *model.AchievementType) error
+	Deactivate(ctx context.Context, code string) error
+}
+
+type AchievementTypeRepo struct {
+	DB *sql.DB
+}
+
+func NewAchievementTypeRepo(db *sql.DB) *AchievementTypeRepo {
+	return &AchievementTypeRepo{DB: db}
+}
+
+const achievementTypeColumns = `code, name, description, details_schema, is_active, created_at, updated_at`
+
+func scanAchievementType(row interface{ Scan(dest ...any) error }) (*model.AchievementType, error) {
+	var t model.AchievementType
+	var schema []byte
+	if err := row.Scan(&t.Code, &t.Name, &t.Description, &schema, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	t.DetailsSchema = schema
+	return &t, nil
+}
+
+func (r *AchievementTypeRepo) FindAll(ctx context.Context, includeInactive bool) ([]model.AchievementType, error) {
+	ctx, finish := startOp(ctx, "AchievementTypeRepo.FindAll")
+	defer finish()
+
+	query := `SELECT ` + achievementTypeColumns + ` FROM achievement_types`
+	if !includeInactive {
+		query += ` WHERE is_active = true`
+	}
+	query += ` ORDER BY name`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var types []model.AchievementType
+	for rows.Next() {
+		t, err := scanAchievementType(rows)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, *t)
+	}
+	return types, rows.Err()
+}
+
+func (r *AchievementTypeRepo) FindByCode(ctx context.Context, code string) (*model.AchievementType, error) {
+	ctx, finish := startOp(ctx, "AchievementTypeRepo.FindByCode")
+	defer finish()
+
+	query := `SELECT ` + achievementTypeColumns + ` FROM achievement_types WHERE code = $1`
+	return scanAchievementType(r.DB.QueryRowContext(ctx, query, code))
+}
+
+func (r *AchievementTypeRepo) Create(ctx context.Context, t *model.AchievementType) error {
+	ctx, finish := startOp(ctx, "AchievementTypeRepo.Create")
+	defer finish()
+
+	now := time.Now()
+	query := `
+		INSERT INTO achievement_types (code, name, description, details_schema, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, true, $5, $5)`
+	if _, err := r.DB.ExecContext(ctx, query, t.Code, t.Name, t.Description, []byte(t.DetailsSchema), now); err != nil {
+		return err
+	}
+
+	t.IsActive = true
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	return nil
+}
+
+func (r *AchievementTypeRepo) Update(ctx context.Context, t *model.AchievementType) error {
+	ctx, finish := startOp(ctx, "AchievementTypeRepo.Update")
+	defer finish()
+
+	t.UpdatedAt = time.Now()
+	query := `
+		UPDATE achievement_types
+		SET name = $1, description = $2, details_schema = $3, is_active = $4, updated_at = $5
+		WHERE code = $6`
+	result, err := r.DB.ExecContext(ctx, query, t.Name, t.Description, []byte(t.DetailsSchema), t.IsActive, t.UpdatedAt, t.Code)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Deactivate menonaktifkan tipe tanpa menghapusnya karena achievement lama
+// tetap merujuk ke kode tipe tersebut.
+func (r *AchievementTypeRepo) Deactivate(ctx context.Context, code string) error {
+	ctx, finish := startOp(ctx, "AchievementTypeRepo.Deactivate")
+	defer finish()
+
+	result, err := r.DB.ExecContext(ctx,
+		`UPDATE achievement_types SET is_active = false, updated_at = $1 WHERE code = $2`,
+		time.Now(), code)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
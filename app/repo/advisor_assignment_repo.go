@@ -0,0 +1,255 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// UpdateAdvisor mengganti dosen wali mahasiswa dan mencatatnya di riwayat
+// penugasan; sql.ErrNoRows bila mahasiswa tidak ditemukan.
+func (r *StudentRepo) UpdateAdvisor(ctx context.Context, studentID, advisorID, assignedBy uuid.UUID, reason string) error {
+	ctx, finish := startOp(ctx, "StudentRepo.UpdateAdvisor")
+	defer finish()
+
+	_, err := r.AssignAdvisors(ctx, []model.AdvisorChange{{StudentID: studentID, LecturerID: advisorID}}, assignedBy, reason)
+	return err
+}
+
+// AssignAdvisors menerapkan semua penggantian dosen wali dalam satu transaksi
+// dan mengembalikan jumlah mahasiswa yang dosen walinya benar-benar berubah.
+func (r *StudentRepo) AssignAdvisors(ctx context.Context, changes []model.AdvisorChange, assignedBy uuid.UUID, reason string) (int, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.AssignAdvisors")
+	defer finish()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	changed := 0
+	for _, c := range changes {
+		ok, err := assignAdvisorTx(ctx, tx, c.StudentID, c.LecturerID, assignedBy, reason, now)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			changed++
+		}
+	}
+	return changed, tx.Commit()
+}
+
+// ReassignAdvisees memindahkan semua mahasiswa bimbingan fromID ke toID dalam
+// satu transaksi.
+func (r *StudentRepo) ReassignAdvisees(ctx context.Context, fromID, toID, assignedBy uuid.UUID, reason string) (int, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.ReassignAdvisees")
+	defer finish()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM students WHERE advisor_id = $1 ORDER BY id FOR UPDATE`, fromID)
+	if err != nil {
+		return 0, err
+	}
+	var studentIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		studentIDs = append(studentIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, id := range studentIDs {
+		if _, err := assignAdvisorTx(ctx, tx, id, toID, assignedBy, reason, now); err != nil {
+			return 0, err
+		}
+	}
+	return len(studentIDs), tx.Commit()
+}
+
+// FindAdvisorHistory memuat riwayat penugasan dosen wali mahasiswa, terbaru
+// lebih dulu.
+func (r *StudentRepo) FindAdvisorHistory(ctx context.Context, studentID uuid.UUID) ([]model.AdvisorAssignment, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.FindAdvisorHistory")
+	defer finish()
+
+	query := `
+		SELECT a.id, a.lecturer_id, l.lecturer_id, lu.full_name, a.starts_at, a.ends_at,
+			COALESCE(bu.full_name, ''), a.reason
+		FROM advisor_assignments a
+		JOIN lecturers l ON l.id = a.lecturer_id
+		JOIN users lu ON lu.id = l.user_id
+		LEFT JOIN users bu ON bu.id = a.assigned_by
+		WHERE a.student_id = $1
+		ORDER BY a.starts_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []model.AdvisorAssignment{}
+	for rows.Next() {
+		var a model.AdvisorAssignment
+		var endsAt sql.NullTime
+		err := rows.Scan(&a.ID, &a.LecturerID, &a.NIP, &a.LecturerName, &a.StartsAt, &endsAt,
+			&a.AssignedByName, &a.Reason)
+		if err != nil {
+			return nil, err
+		}
+		if endsAt.Valid {
+			a.EndsAt = &endsAt.Time
+		}
+		history = append(history, a)
+	}
+	return history, rows.Err()
+}
+
+// FindUnassigned memuat mahasiswa aktif tanpa dosen wali beserta jurusan
+// program studinya, diurutkan per NIM. academicYear kosong berarti tidak
+// difilter.
+func (r *StudentRepo) FindUnassigned(ctx context.Context, unit model.AcademicUnitFilter, academicYear string) ([]model.Student, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.FindUnassigned")
+	defer finish()
+
+	unitScope, unitArgs := studentUnitScope(unit, 2)
+	query := `
+		SELECT s.id, s.user_id, s.student_id, COALESCE(s.program_study, ''), s.study_program_id, sp.department_id,
+			s.academic_year, u.full_name
+		FROM students s
+		JOIN users u ON u.id = s.user_id
+		LEFT JOIN study_programs sp ON sp.id = s.study_program_id
+		WHERE s.advisor_id IS NULL AND u.is_active = true AND s.status = 'active'
+		  AND ($1 = '' OR s.academic_year = $1)` + unitScope + `
+		ORDER BY s.student_id`
+	rows, err := r.DB.QueryContext(ctx, query, append([]interface{}{academicYear}, unitArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := []model.Student{}
+	for rows.Next() {
+		var st model.Student
+		if err := rows.Scan(&st.ID, &st.UserID, &st.StudentID, &st.ProgramStudy, &st.StudyProgramID, &st.DepartmentID,
+			&st.AcademicYear, &st.User.FullName); err != nil {
+			return nil, err
+		}
+		students = append(students, st)
+	}
+	return students, rows.Err()
+}
+
+// AssignUnassigned menerapkan usulan pembagian dosen wali dalam satu
+// transaksi. sql.ErrNoRows bila ada mahasiswa yang sudah mendapat dosen wali
+// atau tidak lagi aktif, dosen yang tidak aktif atau bukan dari jurusan
+// program studi mahasiswa, atau dosen yang melewati batas bimbingan sejak
+// usulan dibuat.
+func (r *StudentRepo) AssignUnassigned(ctx context.Context, changes []model.AdvisorChange, assignedBy uuid.UUID, reason string) error {
+	ctx, finish := startOp(ctx, "StudentRepo.AssignUnassigned")
+	defer finish()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	lecturerIDs := make([]string, 0, len(changes))
+	for _, c := range changes {
+		var eligible bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT s.advisor_id IS NULL AND s.status = 'active' AND su.is_active AND EXISTS (
+				SELECT 1 FROM lecturers l
+				JOIN users lu ON lu.id = l.user_id
+				JOIN study_programs sp ON sp.department_id = l.department_id
+				WHERE l.id = $2 AND sp.id = s.study_program_id AND lu.is_active
+			)
+			FROM students s
+			JOIN users su ON su.id = s.user_id
+			WHERE s.id = $1
+			FOR UPDATE OF s`, c.StudentID, c.LecturerID).Scan(&eligible)
+		if err != nil {
+			return err
+		}
+		if !eligible {
+			return sql.ErrNoRows
+		}
+		if err := switchAdvisorTx(ctx, tx, c.StudentID, c.LecturerID, assignedBy, reason, now); err != nil {
+			return err
+		}
+		lecturerIDs = append(lecturerIDs, c.LecturerID.String())
+	}
+
+	var over int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM lecturers l
+		WHERE l.id = ANY($1::uuid[]) AND l.max_advisees IS NOT NULL
+		  AND l.max_advisees < (
+			SELECT COUNT(*) FROM students s JOIN users su ON su.id = s.user_id
+			WHERE s.advisor_id = l.id AND su.is_active = true AND`+enrolledStudent+`
+		  )`, pq.Array(lecturerIDs)).Scan(&over)
+	if err != nil {
+		return err
+	}
+	if over > 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// assignAdvisorTx mengganti dosen wali bila berbeda. Hasilnya false bila
+// mahasiswa sudah dibimbing advisorID.
+func assignAdvisorTx(ctx context.Context, tx *sql.Tx, studentID, advisorID, assignedBy uuid.UUID, reason string, now time.Time) (bool, error) {
+	current, err := lockAdvisorTx(ctx, tx, studentID)
+	if err != nil {
+		return false, err
+	}
+	if current.Valid && current.UUID == advisorID {
+		return false, nil
+	}
+	return true, switchAdvisorTx(ctx, tx, studentID, advisorID, assignedBy, reason, now)
+}
+
+// lockAdvisorTx mengunci baris mahasiswa dan mengembalikan dosen walinya saat ini.
+func lockAdvisorTx(ctx context.Context, tx *sql.Tx, studentID uuid.UUID) (uuid.NullUUID, error) {
+	var current uuid.NullUUID
+	err := tx.QueryRowContext(ctx, `SELECT advisor_id FROM students WHERE id = $1 FOR UPDATE`, studentID).Scan(&current)
+	return current, err
+}
+
+// switchAdvisorTx menutup penugasan aktif dan membuka penugasan baru.
+func switchAdvisorTx(ctx context.Context, tx *sql.Tx, studentID, advisorID, assignedBy uuid.UUID, reason string, now time.Time) error {
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE advisor_assignments SET ends_at = $1 WHERE student_id = $2 AND ends_at IS NULL`,
+		now, studentID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO advisor_assignments (student_id, lecturer_id, starts_at, assigned_by, reason)
+		VALUES ($1, $2, $3, $4, $5)`,
+		studentID, advisorID, now, assignedBy, reason); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `UPDATE students SET advisor_id = $1 WHERE id = $2`, advisorID, studentID)
+	return err
+}
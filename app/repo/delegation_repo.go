@@ -0,0 +1,163 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"fiber/skp/helper"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// advisorOrDelegateScope cocok bila user adalah dosen wali dengan alias
+// lecturers %[1]s atau penerima delegasinya yang sedang berlaku. %[2]s adalah
+// placeholder user_id.
+const advisorOrDelegateScope = `
+	(%[1]s.user_id = %[2]s OR EXISTS (
+		SELECT 1 FROM advisor_delegations d
+		JOIN lecturers dl ON dl.id = d.delegate_id
+		WHERE d.advisor_id = %[1]s.id AND dl.user_id = %[2]s AND d.revoked_at IS NULL
+		  AND CURRENT_DATE BETWEEN d.starts_at AND d.ends_at
+	))`
+
+type DelegationRepository interface {
+	FindAll(ctx context.Context, lecturerID *uuid.UUID, activeOnly bool) ([]model.AdvisorDelegation, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.AdvisorDelegation, error)
+	HasOverlap(ctx context.Context, advisorID uuid.UUID, startsAt, endsAt string) (bool, error)
+	Create(ctx context.Context, delegation *model.AdvisorDelegation, createdBy uuid.UUID) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+type DelegationRepo struct {
+	DB *sql.DB
+}
+
+func NewDelegationRepo(db *sql.DB) *DelegationRepo {
+	return &DelegationRepo{DB: db}
+}
+
+const delegationSelect = `
+	SELECT d.id, d.advisor_id, au.full_name, d.delegate_id, du.full_name, d.starts_at, d.ends_at, d.reason,
+		d.revoked_at IS NULL AND CURRENT_DATE BETWEEN d.starts_at AND d.ends_at,
+		d.created_at, d.revoked_at
+	FROM advisor_delegations d
+	JOIN lecturers al ON al.id = d.advisor_id
+	JOIN users au ON au.id = al.user_id
+	JOIN lecturers dl ON dl.id = d.delegate_id
+	JOIN users du ON du.id = dl.user_id`
+
+// FindAll memuat delegasi, terbaru lebih dulu. Bila lecturerID diisi hanya
+// delegasi yang diberikan atau diterima dosen tersebut.
+func (r *DelegationRepo) FindAll(ctx context.Context, lecturerID *uuid.UUID, activeOnly bool) ([]model.AdvisorDelegation, error) {
+	ctx, finish := startOp(ctx, "DelegationRepo.FindAll")
+	defer finish()
+
+	where := ` WHERE TRUE`
+	var args []interface{}
+	if lecturerID != nil {
+		args = append(args, *lecturerID)
+		where += fmt.Sprintf(` AND (d.advisor_id = $%d OR d.delegate_id = $%d)`, len(args), len(args))
+	}
+	if activeOnly {
+		where += ` AND d.revoked_at IS NULL AND d.ends_at >= CURRENT_DATE`
+	}
+
+	rows, err := r.DB.QueryContext(ctx, delegationSelect+where+` ORDER BY d.starts_at DESC, d.created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delegations := []model.AdvisorDelegation{}
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, *d)
+	}
+	return delegations, rows.Err()
+}
+
+func (r *DelegationRepo) FindByID(ctx context.Context, id uuid.UUID) (*model.AdvisorDelegation, error) {
+	ctx, finish := startOp(ctx, "DelegationRepo.FindByID")
+	defer finish()
+
+	return scanDelegation(r.DB.QueryRowContext(ctx, delegationSelect+` WHERE d.id = $1`, id))
+}
+
+// HasOverlap memeriksa apakah dosen wali sudah punya delegasi yang belum
+// dicabut dan beririsan dengan rentang tanggal tersebut.
+func (r *DelegationRepo) HasOverlap(ctx context.Context, advisorID uuid.UUID, startsAt, endsAt string) (bool, error) {
+	ctx, finish := startOp(ctx, "DelegationRepo.HasOverlap")
+	defer finish()
+
+	query := `
+		SELECT COUNT(*) FROM advisor_delegations
+		WHERE advisor_id = $1 AND revoked_at IS NULL AND starts_at <= $3 AND ends_at >= $2`
+	var count int64
+	if err := r.DB.QueryRowContext(ctx, query, advisorID, startsAt, endsAt).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *DelegationRepo) Create(ctx context.Context, delegation *model.AdvisorDelegation, createdBy uuid.UUID) error {
+	ctx, finish := startOp(ctx, "DelegationRepo.Create")
+	defer finish()
+
+	query := `
+		INSERT INTO advisor_delegations (advisor_id, delegate_id, starts_at, ends_at, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+	var id uuid.UUID
+	err := r.DB.QueryRowContext(ctx, query, delegation.AdvisorID, delegation.DelegateID,
+		delegation.StartsAt, delegation.EndsAt, delegation.Reason, createdBy, time.Now()).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	created, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	*delegation = *created
+	return nil
+}
+
+// Revoke mencabut delegasi; sql.ErrNoRows bila tidak ditemukan atau sudah
+// dicabut.
+func (r *DelegationRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	ctx, finish := startOp(ctx, "DelegationRepo.Revoke")
+	defer finish()
+
+	result, err := r.DB.ExecContext(ctx,
+		`UPDATE advisor_delegations SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`,
+		time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanDelegation(row rowScanner) (*model.AdvisorDelegation, error) {
+	var d model.AdvisorDelegation
+	var startsAt, endsAt time.Time
+	var revokedAt sql.NullTime
+	err := row.Scan(&d.ID, &d.AdvisorID, &d.AdvisorName, &d.DelegateID, &d.DelegateName, &startsAt, &endsAt,
+		&d.Reason, &d.Active, &d.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	d.StartsAt = startsAt.Format(helper.DateLayout)
+	d.EndsAt = endsAt.Format(helper.DateLayout)
+	if revokedAt.Valid {
+		d.RevokedAt = &revokedAt.Time
+	}
+	return &d, nil
+}
@@ -1,21 +1,29 @@
 package repo
 
 import (
+	"context"
 	"database/sql"
 	"fiber/skp/app/model"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type LecturerRepository interface {
-	Create(lecturer *model.Lecturer) error
-	FindAll(page, limit int, search, sortBy, order string) ([]model.Lecturer, int64, error)
-	FindByID(id uuid.UUID) (*model.Lecturer, error)
-	GetAdvisees(advisorID uuid.UUID) ([]model.Student, error)
-	ExistsByLecturerID(lecturerID string) (bool, error)
-	DeleteByUserID(userID uuid.UUID) error
+	Create(ctx context.Context, lecturer *model.Lecturer) error
+	FindAll(ctx context.Context, page, limit int, search, sortBy, order string, unit model.AcademicUnitFilter) ([]model.Lecturer, int64, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Lecturer, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Lecturer, error)
+	GetAdvisees(ctx context.Context, advisorID uuid.UUID) ([]model.Student, error)
+	ExistsByLecturerID(ctx context.Context, lecturerID string) (bool, error)
+	FindByLecturerIDs(ctx context.Context, lecturerIDs []string) (map[string]model.Lecturer, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+	SetAppealReviewer(ctx context.Context, id uuid.UUID, enabled bool) error
+	SetDepartmentHead(ctx context.Context, id uuid.UUID, enabled bool) error
+	SetMaxAdvisees(ctx context.Context, id uuid.UUID, maxAdvisees *int) error
+	FindAdvisorLoads(ctx context.Context) ([]model.AdvisorLoad, error)
 }
 
 type LecturerRepo struct {
@@ -35,23 +43,30 @@ var lecturerSortWhitelist = map[string]string{
 	"department":  "l.department",
 }
 
-func (r *LecturerRepo) Create(lecturer *model.Lecturer) error {
+func (r *LecturerRepo) Create(ctx context.Context, lecturer *model.Lecturer) error {
+	ctx, finish := startOp(ctx, "LecturerRepo.Create")
+	defer finish()
+
 	query := `
-		INSERT INTO lecturers (user_id, lecturer_id, department, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO lecturers (user_id, lecturer_id, department, department_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id`
 
 	now := time.Now()
-	return r.DB.QueryRow(
+	return r.DB.QueryRowContext(ctx,
 		query,
 		lecturer.UserID,
 		lecturer.LecturerID,
 		lecturer.Department,
+		lecturer.DepartmentID,
 		now,
 	).Scan(&lecturer.ID)
 }
 
-func (r *LecturerRepo) FindAll(page, limit int, search, sortBy, order string) ([]model.Lecturer, int64, error) {
+func (r *LecturerRepo) FindAll(ctx context.Context, page, limit int, search, sortBy, order string, unit model.AcademicUnitFilter) ([]model.Lecturer, int64, error) {
+	ctx, finish := startOp(ctx, "LecturerRepo.FindAll")
+	defer finish()
+
 	var total int64
 
 	countQuery := `SELECT COUNT(*) FROM lecturers l JOIN users u ON u.id = l.user_id WHERE u.is_active = true`
@@ -63,14 +78,17 @@ func (r *LecturerRepo) FindAll(page, limit int, search, sortBy, order string) ([
 		countArgs = append(countArgs, "%"+search+"%")
 		argIndex++
 	}
+	unitScope, unitArgs := lecturerUnitScope(unit, argIndex)
+	countQuery += unitScope
+	countArgs = append(countArgs, unitArgs...)
 
-	err := r.DB.QueryRow(countQuery, countArgs...).Scan(&total)
+	err := r.DB.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := `
-		SELECT l.id, l.user_id, l.lecturer_id, l.department, l.created_at,
+		SELECT l.id, l.user_id, l.lecturer_id, l.department, l.department_id, l.created_at,
 		       u.username, u.email, u.full_name
 		FROM lecturers l
 		JOIN users u ON u.id = l.user_id
@@ -84,6 +102,10 @@ func (r *LecturerRepo) FindAll(page, limit int, search, sortBy, order string) ([
 		selectArgs = append(selectArgs, "%"+search+"%")
 		selectArgIndex++
 	}
+	unitScope, unitArgs = lecturerUnitScope(unit, selectArgIndex)
+	query += unitScope
+	selectArgs = append(selectArgs, unitArgs...)
+	selectArgIndex += len(unitArgs)
 
 	if order != "asc" && order != "desc" {
 		order = "desc"
@@ -98,7 +120,7 @@ func (r *LecturerRepo) FindAll(page, limit int, search, sortBy, order string) ([
 	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", selectArgIndex, selectArgIndex+1)
 	selectArgs = append(selectArgs, limit, offset)
 
-	rows, err := r.DB.Query(query, selectArgs...)
+	rows, err := r.DB.QueryContext(ctx, query, selectArgs...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -110,7 +132,7 @@ func (r *LecturerRepo) FindAll(page, limit int, search, sortBy, order string) ([
 		var userName, userEmail, userFullName sql.NullString
 
 		if err := rows.Scan(
-			&l.ID, &l.UserID, &l.LecturerID, &l.Department, &l.CreatedAt,
+			&l.ID, &l.UserID, &l.LecturerID, &l.Department, &l.DepartmentID, &l.CreatedAt,
 			&userName, &userEmail, &userFullName,
 		); err != nil {
 			return nil, 0, err
@@ -129,19 +151,33 @@ func (r *LecturerRepo) FindAll(page, limit int, search, sortBy, order string) ([
 	return lecturers, total, nil
 }
 
-func (r *LecturerRepo) FindByID(id uuid.UUID) (*model.Lecturer, error) {
+func (r *LecturerRepo) FindByID(ctx context.Context, id uuid.UUID) (*model.Lecturer, error) {
+	ctx, finish := startOp(ctx, "LecturerRepo.FindByID")
+	defer finish()
+
+	return r.findOne(ctx, "l.id", id)
+}
+
+func (r *LecturerRepo) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Lecturer, error) {
+	ctx, finish := startOp(ctx, "LecturerRepo.FindByUserID")
+	defer finish()
+
+	return r.findOne(ctx, "l.user_id", userID)
+}
+
+func (r *LecturerRepo) findOne(ctx context.Context, column string, id uuid.UUID) (*model.Lecturer, error) {
 	query := `
-		SELECT l.id, l.user_id, l.lecturer_id, l.department, l.created_at,
+		SELECT l.id, l.user_id, l.lecturer_id, l.department, l.department_id, l.created_at,
 		       u.username, u.email, u.full_name
 		FROM lecturers l
 		JOIN users u ON u.id = l.user_id
-		WHERE l.id = $1 AND u.is_active = true`
+		WHERE ` + column + ` = $1 AND u.is_active = true`
 
 	var l model.Lecturer
 	var userName, userEmail, userFullName sql.NullString
 
-	err := r.DB.QueryRow(query, id).Scan(
-		&l.ID, &l.UserID, &l.LecturerID, &l.Department, &l.CreatedAt,
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(
+		&l.ID, &l.UserID, &l.LecturerID, &l.Department, &l.DepartmentID, &l.CreatedAt,
 		&userName, &userEmail, &userFullName,
 	)
 	if err != nil {
@@ -158,15 +194,18 @@ func (r *LecturerRepo) FindByID(id uuid.UUID) (*model.Lecturer, error) {
 	return &l, nil
 }
 
-func (r *LecturerRepo) GetAdvisees(advisorID uuid.UUID) ([]model.Student, error) {
+func (r *LecturerRepo) GetAdvisees(ctx context.Context, advisorID uuid.UUID) ([]model.Student, error) {
+	ctx, finish := startOp(ctx, "LecturerRepo.GetAdvisees")
+	defer finish()
+
 	query := `
-		SELECT s.id, s.user_id, s.student_id, s.program_study, s.academic_year, s.advisor_id, s.created_at,
+		SELECT s.id, s.user_id, s.student_id, s.program_study, s.academic_year, s.advisor_id, s.status, s.created_at,
 		       u.id, u.username, u.email, u.full_name
 		FROM students s
 		JOIN users u ON u.id = s.user_id
 		WHERE s.advisor_id = $1 AND u.is_active = true`
 
-	rows, err := r.DB.Query(query, advisorID)
+	rows, err := r.DB.QueryContext(ctx, query, advisorID)
 	if err != nil {
 		return nil, err
 	}
@@ -178,7 +217,7 @@ func (r *LecturerRepo) GetAdvisees(advisorID uuid.UUID) ([]model.Student, error)
 		var userID, userName, userEmail, userFullName sql.NullString
 
 		if err := rows.Scan(
-			&s.ID, &s.UserID, &s.StudentID, &s.ProgramStudy, &s.AcademicYear, &s.AdvisorID, &s.CreatedAt,
+			&s.ID, &s.UserID, &s.StudentID, &s.ProgramStudy, &s.AcademicYear, &s.AdvisorID, &s.Status, &s.CreatedAt,
 			&userID, &userName, &userEmail, &userFullName,
 		); err != nil {
 			return nil, err
@@ -197,15 +236,138 @@ func (r *LecturerRepo) GetAdvisees(advisorID uuid.UUID) ([]model.Student, error)
 	return students, nil
 }
 
-func (r *LecturerRepo) ExistsByLecturerID(lecturerID string) (bool, error) {
+func (r *LecturerRepo) ExistsByLecturerID(ctx context.Context, lecturerID string) (bool, error) {
+	ctx, finish := startOp(ctx, "LecturerRepo.ExistsByLecturerID")
+	defer finish()
+
 	var count int64
 	query := `SELECT COUNT(*) FROM lecturers WHERE lecturer_id = $1`
-	err := r.DB.QueryRow(query, lecturerID).Scan(&count)
+	err := r.DB.QueryRowContext(ctx, query, lecturerID).Scan(&count)
 	return count > 0, err
 }
 
-func (r *LecturerRepo) DeleteByUserID(userID uuid.UUID) error {
+// FindByLecturerIDs mencari dosen aktif berdasarkan NIP; NIP yang tidak
+// ditemukan tidak ada di map hasil.
+func (r *LecturerRepo) FindByLecturerIDs(ctx context.Context, lecturerIDs []string) (map[string]model.Lecturer, error) {
+	ctx, finish := startOp(ctx, "LecturerRepo.FindByLecturerIDs")
+	defer finish()
+
+	query := `
+		SELECT l.id, l.user_id, l.lecturer_id, l.department, u.full_name
+		FROM lecturers l
+		JOIN users u ON u.id = l.user_id
+		WHERE l.lecturer_id = ANY($1) AND u.is_active = true`
+	rows, err := r.DB.QueryContext(ctx, query, pq.Array(lecturerIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lecturers := make(map[string]model.Lecturer)
+	for rows.Next() {
+		var l model.Lecturer
+		if err := rows.Scan(&l.ID, &l.UserID, &l.LecturerID, &l.Department, &l.User.FullName); err != nil {
+			return nil, err
+		}
+		lecturers[l.LecturerID] = l
+	}
+	return lecturers, rows.Err()
+}
+
+func (r *LecturerRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	ctx, finish := startOp(ctx, "LecturerRepo.DeleteByUserID")
+	defer finish()
+
 	query := `DELETE FROM lecturers WHERE user_id = $1`
-	_, err := r.DB.Exec(query, userID)
+	_, err := r.DB.ExecContext(ctx, query, userID)
 	return err
 }
+
+func (r *LecturerRepo) SetAppealReviewer(ctx context.Context, id uuid.UUID, enabled bool) error {
+	ctx, finish := startOp(ctx, "LecturerRepo.SetAppealReviewer")
+	defer finish()
+
+	query := `UPDATE lecturers SET is_appeal_reviewer = $1 WHERE id = $2`
+	result, err := r.DB.ExecContext(ctx, query, enabled, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *LecturerRepo) SetDepartmentHead(ctx context.Context, id uuid.UUID, enabled bool) error {
+	ctx, finish := startOp(ctx, "LecturerRepo.SetDepartmentHead")
+	defer finish()
+
+	query := `UPDATE lecturers SET is_department_head = $1 WHERE id = $2`
+	result, err := r.DB.ExecContext(ctx, query, enabled, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetMaxAdvisees mengatur batas mahasiswa bimbingan; nil menghapus batas.
+func (r *LecturerRepo) SetMaxAdvisees(ctx context.Context, id uuid.UUID, maxAdvisees *int) error {
+	ctx, finish := startOp(ctx, "LecturerRepo.SetMaxAdvisees")
+	defer finish()
+
+	result, err := r.DB.ExecContext(ctx, `UPDATE lecturers SET max_advisees = $1 WHERE id = $2`, maxAdvisees, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// FindAdvisorLoads memuat jumlah mahasiswa bimbingan aktif setiap dosen aktif,
+// diurutkan per NIP.
+func (r *LecturerRepo) FindAdvisorLoads(ctx context.Context) ([]model.AdvisorLoad, error) {
+	ctx, finish := startOp(ctx, "LecturerRepo.FindAdvisorLoads")
+	defer finish()
+
+	query := `
+		SELECT l.id, l.lecturer_id, u.full_name, COALESCE(l.department, ''), l.department_id, l.max_advisees,
+			(SELECT COUNT(*) FROM students s JOIN users su ON su.id = s.user_id
+			 WHERE s.advisor_id = l.id AND su.is_active = true AND` + enrolledStudent + `)
+		FROM lecturers l
+		JOIN users u ON u.id = l.user_id
+		WHERE u.is_active = true
+		ORDER BY l.lecturer_id`
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	loads := []model.AdvisorLoad{}
+	for rows.Next() {
+		var l model.AdvisorLoad
+		var maxAdvisees sql.NullInt64
+		if err := rows.Scan(&l.ID, &l.LecturerID, &l.FullName, &l.Department, &l.DepartmentID, &maxAdvisees, &l.Advisees); err != nil {
+			return nil, err
+		}
+		if maxAdvisees.Valid {
+			n := int(maxAdvisees.Int64)
+			l.MaxAdvisees = &n
+		}
+		loads = append(loads, l)
+	}
+	return loads, rows.Err()
+}
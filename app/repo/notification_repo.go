@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fiber/skp/app/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n *model.Notification) error
+	FindByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]model.Notification, int64, error)
+	MarkRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+}
+
+type NotificationRepo struct {
+	DB *sql.DB
+}
+
+func NewNotificationRepo(db *sql.DB) *NotificationRepo {
+	return &NotificationRepo{DB: db}
+}
+
+func (r *NotificationRepo) Create(ctx context.Context, n *model.Notification) error {
+	ctx, finish := startOp(ctx, "NotificationRepo.Create")
+	defer finish()
+
+	if n.Params == nil {
+		n.Params = []string{}
+	}
+	params, err := json.Marshal(n.Params)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, type, achievement_id, params, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	return r.DB.QueryRowContext(ctx, query, n.UserID, n.Type, n.AchievementID, params, time.Now()).
+		Scan(&n.ID, &n.CreatedAt)
+}
+
+func (r *NotificationRepo) FindByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]model.Notification, int64, error) {
+	ctx, finish := startOp(ctx, "NotificationRepo.FindByUser")
+	defer finish()
+
+	where := ` WHERE user_id = $1`
+	if unreadOnly {
+		where += ` AND read_at IS NULL`
+	}
+
+	var total int64
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications`+where, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	query := `
+		SELECT id, user_id, type, achievement_id, params, read_at, created_at
+		FROM notifications` + where + `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+	rows, err := r.DB.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var notifications []model.Notification
+	for rows.Next() {
+		var n model.Notification
+		var achievementID uuid.NullUUID
+		var params []byte
+		var readAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &achievementID, &params, &readAt, &n.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		if achievementID.Valid {
+			n.AchievementID = &achievementID.UUID
+		}
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+		}
+		if err := json.Unmarshal(params, &n.Params); err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, total, rows.Err()
+}
+
+func (r *NotificationRepo) MarkRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	ctx, finish := startOp(ctx, "NotificationRepo.MarkRead")
+	defer finish()
+
+	result, err := r.DB.ExecContext(ctx,
+		`UPDATE notifications SET read_at = COALESCE(read_at, $1) WHERE id = $2 AND user_id = $3`,
+		time.Now(), id, userID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *NotificationRepo) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	ctx, finish := startOp(ctx, "NotificationRepo.MarkAllRead")
+	defer finish()
+
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE notifications SET read_at = $1 WHERE user_id = $2 AND read_at IS NULL`,
+		time.Now(), userID)
+	return err
+}
@@ -0,0 +1,19 @@
+package repo
+
+import (
+	"context"
+
+	"fiber/skp/config"
+	"fiber/skp/tracing"
+)
+
+// startOp membuka span untuk satu pemanggilan repository dan membatasi
+// durasinya dengan DB_QUERY_TIMEOUT. finish wajib dipanggil setelah selesai.
+func startOp(ctx context.Context, name string) (context.Context, func()) {
+	ctx, cancel := context.WithTimeout(ctx, config.GetDBQueryTimeout())
+	ctx, span := tracing.Tracer().Start(ctx, name)
+	return ctx, func() {
+		span.End()
+		cancel()
+	}
+}
@@ -0,0 +1,317 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ReportRepository interface {
+	GetStatistics(ctx context.Context, role string, userID uuid.UUID, filter model.ReportFilter) (*model.StatsResponse, error)
+	GetStudentStats(ctx context.Context, studentID uuid.UUID, filter model.ReportFilter) (*model.StatsResponse, error)
+	GetAdvisorAttribution(ctx context.Context, unit model.AcademicUnitFilter) ([]model.AdvisorAttribution, error)
+}
+
+type ReportRepo struct {
+	pgDB    *sql.DB
+	mongoDB *mongo.Database
+}
+
+func NewReportRepo(pgDB *sql.DB, mongoDB *mongo.Database) *ReportRepo {
+	return &ReportRepo{pgDB: pgDB, mongoDB: mongoDB}
+}
+
+const topStudentLimit = 10
+
+func (r *ReportRepo) GetStatistics(ctx context.Context, role string, userID uuid.UUID, filter model.ReportFilter) (*model.StatsResponse, error) {
+	ctx, finish := startOp(ctx, "ReportRepo.GetStatistics")
+	defer finish()
+
+	scope := ""
+	var args []interface{}
+
+	if role == model.RoleMahasiswa {
+		scope = " AND ar.student_id = (SELECT id FROM students WHERE user_id = $2)"
+		args = append(args, userID)
+	} else if role == model.RoleDosenWali {
+		scope = " AND ar.student_id IN (SELECT s.id FROM students s JOIN lecturers l ON s.advisor_id = l.id WHERE l.user_id = $2)"
+		args = append(args, userID)
+	}
+
+	if unitScope, unitArgs := studentUnitScope(filter.Unit, len(args)+2); len(unitArgs) > 0 {
+		scope += " AND ar.student_id IN (SELECT s.id FROM students s WHERE TRUE" + unitScope + ")"
+		args = append(args, unitArgs...)
+	}
+
+	return r.aggregate(ctx, filter, scope, args...)
+}
+
+func (r *ReportRepo) GetStudentStats(ctx context.Context, studentID uuid.UUID, filter model.ReportFilter) (*model.StatsResponse, error) {
+	ctx, finish := startOp(ctx, "ReportRepo.GetStudentStats")
+	defer finish()
+
+	return r.aggregate(ctx, filter, " AND ar.student_id = $2", studentID)
+}
+
+// GetAdvisorAttribution menghitung keputusan verifikasi per dosen wali
+// berdasarkan riwayat penugasan pada saat achievement diputus, bukan dosen
+// wali mahasiswa saat ini.
+func (r *ReportRepo) GetAdvisorAttribution(ctx context.Context, unit model.AcademicUnitFilter) ([]model.AdvisorAttribution, error) {
+	ctx, finish := startOp(ctx, "ReportRepo.GetAdvisorAttribution")
+	defer finish()
+
+	unitScope, unitArgs := lecturerUnitScope(unit, 3)
+
+	query := `
+		SELECT l.id, l.lecturer_id, u.full_name, COALESCE(l.department, ''),
+			COUNT(DISTINCT ar.student_id),
+			COUNT(*) FILTER (WHERE ar.status = $1),
+			COUNT(*) FILTER (WHERE ar.status = $2)
+		FROM achievement_references ar
+		JOIN advisor_assignments aa ON aa.student_id = ar.student_id
+			AND aa.starts_at <= ar.verified_at AND (aa.ends_at IS NULL OR aa.ends_at > ar.verified_at)
+		JOIN lecturers l ON l.id = aa.lecturer_id
+		JOIN users u ON u.id = l.user_id
+		WHERE ar.verified_at IS NOT NULL AND ar.status IN ($1, $2)` + unitScope + `
+		GROUP BY l.id, l.lecturer_id, u.full_name, l.department
+		ORDER BY 6 DESC, u.full_name`
+	rows, err := r.pgDB.QueryContext(ctx, query, append([]interface{}{model.StatusVerified, model.StatusRejected}, unitArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.AdvisorAttribution{}
+	for rows.Next() {
+		var a model.AdvisorAttribution
+		if err := rows.Scan(&a.ID, &a.LecturerID, &a.FullName, &a.Department, &a.Students, &a.Verified, &a.Rejected); err != nil {
+			return nil, err
+		}
+		items = append(items, a)
+	}
+	return items, rows.Err()
+}
+
+// aggregate menghitung statistik achievement terverifikasi dalam cakupan scope,
+// yaitu kondisi tambahan atas achievement_references ar dengan argumen mulai $2.
+func (r *ReportRepo) aggregate(ctx context.Context, filter model.ReportFilter, scope string, scopeArgs ...interface{}) (*model.StatsResponse, error) {
+	var totalRevoked int64
+	revokedQuery := `SELECT COUNT(DISTINCT ar.mongo_achievement_id) FROM achievement_references ar WHERE ar.status = $1` + scope
+	err := r.pgDB.QueryRowContext(ctx, revokedQuery, append([]interface{}{model.StatusRevoked}, scopeArgs...)...).Scan(&totalRevoked)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ar.mongo_achievement_id, ar.student_id, p.year, p.semester, p.starts_on
+		FROM achievement_references ar
+		LEFT JOIN academic_periods p ON p.id = ar.period_id
+		WHERE ar.status = $1` + scope
+	rows, err := r.pgDB.QueryContext(ctx, query, append([]interface{}{model.StatusVerified}, scopeArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Achievement tim dirujuk beberapa baris; dokumen dihitung sekali, sedangkan
+	// poin dihitung per mahasiswa yang masuk cakupan query.
+	var mongoOIDs []primitive.ObjectID
+	seenOIDs := make(map[primitive.ObjectID]bool)
+	studentIDs := bson.A{}
+	seenStudents := make(map[string]bool)
+	periods := make(map[primitive.ObjectID]statPeriod)
+	for rows.Next() {
+		var hexID, studentID string
+		var year, semester sql.NullString
+		var startsOn sql.NullTime
+		if err := rows.Scan(&hexID, &studentID, &year, &semester, &startsOn); err != nil {
+			return nil, err
+		}
+		if !seenStudents[studentID] {
+			seenStudents[studentID] = true
+			studentIDs = append(studentIDs, studentID)
+		}
+		oid, err := primitive.ObjectIDFromHex(hexID)
+		if err != nil || seenOIDs[oid] {
+			continue
+		}
+		seenOIDs[oid] = true
+		mongoOIDs = append(mongoOIDs, oid)
+		if year.Valid {
+			periods[oid] = statPeriod{label: model.PeriodLabel(year.String, semester.String), startsOn: startsOn.Time}
+		}
+	}
+
+	stats := &model.StatsResponse{
+		TotalRevoked: totalRevoked,
+		ByType:       []model.StatItem{},
+		ByLevel:      []model.StatItem{},
+		ByPeriod:     []model.StatItem{},
+		TopStudents:  []model.TopStudent{},
+	}
+	if len(mongoOIDs) == 0 {
+		return stats, nil
+	}
+
+	match := bson.M{"_id": bson.M{"$in": mongoOIDs}}
+	if filter.ExcludeExpired {
+		match["$nor"] = bson.A{bson.M{
+			"achievementType":    model.TypeCertification,
+			"details.validUntil": bson.M{"$lt": time.Now()},
+		}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: bson.M{
+			"total": bson.A{
+				bson.M{"$count": "count"},
+			},
+			"byType": bson.A{
+				bson.M{"$group": bson.M{"_id": "$achievementType", "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.M{"count": -1}},
+			},
+			"byLevel": bson.A{
+				bson.M{"$match": bson.M{"details.competitionLevel": bson.M{"$nin": bson.A{nil, ""}}}},
+				bson.M{"$group": bson.M{"_id": "$details.competitionLevel", "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.M{"count": -1}},
+			},
+			// Periode akademik ada di PostgreSQL; facet ini hanya mengembalikan
+			// dokumen yang lolos filter untuk dikelompokkan setelahnya.
+			"byPeriod": bson.A{
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"topStudents": bson.A{
+				bson.M{"$project": bson.M{"members": bson.M{"$ifNull": bson.A{
+					"$memberPoints",
+					bson.A{bson.M{"studentId": "$studentId", "points": "$points"}},
+				}}}},
+				bson.M{"$unwind": "$members"},
+				bson.M{"$match": bson.M{"members.studentId": bson.M{"$in": studentIDs}}},
+				bson.M{"$group": bson.M{
+					"_id":    "$members.studentId",
+					"total":  bson.M{"$sum": 1},
+					"points": bson.M{"$sum": "$members.points"},
+				}},
+				bson.M{"$sort": bson.M{"points": -1}},
+				bson.M{"$limit": topStudentLimit},
+			},
+		}}},
+	}
+
+	coll := r.mongoDB.Collection("achievements")
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		ByType   []model.StatItem `bson:"byType"`
+		ByLevel  []model.StatItem `bson:"byLevel"`
+		ByPeriod []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		} `bson:"byPeriod"`
+		TopStudents []struct {
+			StudentID string `bson:"_id"`
+			Total     int    `bson:"total"`
+			Points    int    `bson:"points"`
+		} `bson:"topStudents"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+	if len(facets) == 0 {
+		return stats, nil
+	}
+
+	facet := facets[0]
+	if len(facet.Total) > 0 {
+		stats.TotalAchievements = facet.Total[0].Count
+	}
+	if facet.ByType != nil {
+		stats.ByType = facet.ByType
+	}
+	if facet.ByLevel != nil {
+		stats.ByLevel = facet.ByLevel
+	}
+	oids := make([]primitive.ObjectID, len(facet.ByPeriod))
+	for i, doc := range facet.ByPeriod {
+		oids[i] = doc.ID
+	}
+	stats.ByPeriod = countByPeriod(oids, periods)
+
+	for _, ts := range facet.TopStudents {
+		top := model.TopStudent{
+			TotalAchievements: ts.Total,
+			TotalPoints:       ts.Points,
+		}
+
+		studentUUID, err := uuid.Parse(ts.StudentID)
+		if err != nil {
+			continue
+		}
+
+		var nim, fullName string
+		var program sql.NullString
+		err = r.pgDB.QueryRowContext(ctx, `
+			SELECT s.student_id, u.full_name, s.program_study
+			FROM students s
+			JOIN users u ON u.id = s.user_id
+			WHERE s.id = $1`, studentUUID).Scan(&nim, &fullName, &program)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return nil, fmt.Errorf("gagal memuat data mahasiswa %s: %w", studentUUID, err)
+		}
+
+		top.StudentID = nim
+		top.StudentName = fullName
+		top.Program = program.String
+		stats.TopStudents = append(stats.TopStudents, top)
+	}
+
+	return stats, nil
+}
+
+type statPeriod struct {
+	label    string
+	startsOn time.Time
+}
+
+// countByPeriod menghitung achievement per periode akademik, diurutkan dari
+// periode terlama. Achievement tanpa periode tidak dihitung.
+func countByPeriod(oids []primitive.ObjectID, periods map[primitive.ObjectID]statPeriod) []model.StatItem {
+	counts := make(map[statPeriod]int)
+	for _, oid := range oids {
+		if p, ok := periods[oid]; ok {
+			counts[p]++
+		}
+	}
+
+	keys := make([]statPeriod, 0, len(counts))
+	for p := range counts {
+		keys = append(keys, p)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].startsOn.Before(keys[j].startsOn)
+	})
+
+	items := make([]model.StatItem, len(keys))
+	for i, p := range keys {
+		items[i] = model.StatItem{Label: p.label, Count: counts[p]}
+	}
+	return items
+}
@@ -1,22 +1,35 @@
 package repo
 
 import (
+	"context"
 	"database/sql"
 	"fiber/skp/app/model"
+	"fiber/skp/helper"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type StudentRepository interface {
-	Create(student *model.Student) error
-	FindAll(page, limit int, search, sortBy, order string) ([]model.Student, int64, error)
-	FindByID(id uuid.UUID) (*model.Student, error)
-	FindByUserID(userID uuid.UUID) (*model.Student, error)
-	UpdateAdvisor(studentID uuid.UUID, advisorID uuid.UUID) error
-	ExistsByStudentID(studentID string) (bool, error)
-	DeleteByUserID(userID uuid.UUID) error
+	Create(ctx context.Context, student *model.Student) error
+	FindAll(ctx context.Context, page, limit int, search, sortBy, order, status string, unit model.AcademicUnitFilter) ([]model.Student, int64, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Student, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Student, error)
+	UpdateAdvisor(ctx context.Context, studentID, advisorID, assignedBy uuid.UUID, reason string) error
+	AssignAdvisors(ctx context.Context, changes []model.AdvisorChange, assignedBy uuid.UUID, reason string) (int, error)
+	ReassignAdvisees(ctx context.Context, fromID, toID, assignedBy uuid.UUID, reason string) (int, error)
+	FindAdvisorHistory(ctx context.Context, studentID uuid.UUID) ([]model.AdvisorAssignment, error)
+	FindUnassigned(ctx context.Context, unit model.AcademicUnitFilter, academicYear string) ([]model.Student, error)
+	AssignUnassigned(ctx context.Context, changes []model.AdvisorChange, assignedBy uuid.UUID, reason string) error
+	IsAdvisedBy(ctx context.Context, studentID uuid.UUID, lecturerUserID uuid.UUID) (bool, error)
+	ExistsByStudentID(ctx context.Context, studentID string) (bool, error)
+	FindByStudentIDs(ctx context.Context, studentIDs []string) (map[string]model.Student, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+	ChangeStatus(ctx context.Context, change *model.StudentStatusChange, from string) error
+	FindStatusHistory(ctx context.Context, studentID uuid.UUID) ([]model.StudentStatusChange, error)
+	CountPendingReviews(ctx context.Context, studentID uuid.UUID) (int, error)
 }
 
 type StudentRepo struct {
@@ -36,25 +49,58 @@ var studentSortWhitelist = map[string]string{
 	"program_study": "s.program_study",
 }
 
-func (r *StudentRepo) Create(student *model.Student) error {
+func (r *StudentRepo) Create(ctx context.Context, student *model.Student) error {
+	ctx, finish := startOp(ctx, "StudentRepo.Create")
+	defer finish()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO students (user_id, student_id, program_study, academic_year, advisor_id, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO students (user_id, student_id, program_study, study_program_id, academic_year, advisor_id, status, status_since, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::date, $8)
 		RETURNING id`
 
 	now := time.Now()
-	return r.DB.QueryRow(
+	err = tx.QueryRowContext(ctx,
 		query,
 		student.UserID,
 		student.StudentID,
 		student.ProgramStudy,
+		student.StudyProgramID,
 		student.AcademicYear,
 		student.AdvisorID,
+		model.StudentActive,
 		now,
 	).Scan(&student.ID)
+	if err != nil {
+		return err
+	}
+
+	// Riwayat status dimulai sejak profil mahasiswa dibuat.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO student_status_history (student_id, status, effective_date, created_at)
+		VALUES ($1, $2, $3::date, $3)`, student.ID, model.StudentActive, now)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	student.Status = model.StudentActive
+	student.StatusSince = now.Format(helper.DateLayout)
+	return nil
 }
 
-func (r *StudentRepo) FindAll(page, limit int, search, sortBy, order string) ([]model.Student, int64, error) {
+// FindAll memuat mahasiswa dengan akun aktif; status kosong berarti semua
+// status studi.
+func (r *StudentRepo) FindAll(ctx context.Context, page, limit int, search, sortBy, order, status string, unit model.AcademicUnitFilter) ([]model.Student, int64, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.FindAll")
+	defer finish()
+
 	var total int64
 
 	countQuery := `SELECT COUNT(*) FROM students s JOIN users u ON u.id = s.user_id WHERE u.is_active = true`
@@ -66,14 +112,23 @@ func (r *StudentRepo) FindAll(page, limit int, search, sortBy, order string) ([]
 		countArgs = append(countArgs, "%"+search+"%")
 		argIndex++
 	}
+	if status != "" {
+		countQuery += fmt.Sprintf(" AND s.status = $%d", argIndex)
+		countArgs = append(countArgs, status)
+		argIndex++
+	}
+	unitScope, unitArgs := studentUnitScope(unit, argIndex)
+	countQuery += unitScope
+	countArgs = append(countArgs, unitArgs...)
 
-	err := r.DB.QueryRow(countQuery, countArgs...).Scan(&total)
+	err := r.DB.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := `
-		SELECT s.id, s.user_id, s.student_id, s.program_study, s.academic_year, s.advisor_id, s.created_at,
+		SELECT s.id, s.user_id, s.student_id, s.program_study, s.study_program_id, s.academic_year, s.advisor_id,
+		       s.status, s.status_since, s.skp_frozen_at, s.created_at,
 		       u.username, u.email, u.full_name,
 		       a.id, a.lecturer_id,
 		       au.full_name
@@ -91,6 +146,15 @@ func (r *StudentRepo) FindAll(page, limit int, search, sortBy, order string) ([]
 		selectArgs = append(selectArgs, "%"+search+"%")
 		selectArgIndex++
 	}
+	if status != "" {
+		query += fmt.Sprintf(" AND s.status = $%d", selectArgIndex)
+		selectArgs = append(selectArgs, status)
+		selectArgIndex++
+	}
+	unitScope, unitArgs = studentUnitScope(unit, selectArgIndex)
+	query += unitScope
+	selectArgs = append(selectArgs, unitArgs...)
+	selectArgIndex += len(unitArgs)
 
 	if order != "asc" && order != "desc" {
 		order = "desc"
@@ -105,7 +169,7 @@ func (r *StudentRepo) FindAll(page, limit int, search, sortBy, order string) ([]
 	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", selectArgIndex, selectArgIndex+1)
 	selectArgs = append(selectArgs, limit, offset)
 
-	rows, err := r.DB.Query(query, selectArgs...)
+	rows, err := r.DB.QueryContext(ctx, query, selectArgs...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -114,18 +178,21 @@ func (r *StudentRepo) FindAll(page, limit int, search, sortBy, order string) ([]
 	var students []model.Student
 	for rows.Next() {
 		var s model.Student
+		var statusSince time.Time
 		var userName, userEmail, userFullName sql.NullString
 		var advisorID, advisorLecturerID sql.NullString
 		var advisorUserFullName sql.NullString
 
 		if err := rows.Scan(
-			&s.ID, &s.UserID, &s.StudentID, &s.ProgramStudy, &s.AcademicYear, &s.AdvisorID, &s.CreatedAt,
+			&s.ID, &s.UserID, &s.StudentID, &s.ProgramStudy, &s.StudyProgramID, &s.AcademicYear, &s.AdvisorID,
+			&s.Status, &statusSince, &s.SKPFrozenAt, &s.CreatedAt,
 			&userName, &userEmail, &userFullName,
 			&advisorID, &advisorLecturerID,
 			&advisorUserFullName,
 		); err != nil {
 			return nil, 0, err
 		}
+		s.StatusSince = statusSince.Format(helper.DateLayout)
 
 		s.User.ID = s.UserID
 		if userName.Valid {
@@ -149,9 +216,13 @@ func (r *StudentRepo) FindAll(page, limit int, search, sortBy, order string) ([]
 	return students, total, nil
 }
 
-func (r *StudentRepo) FindByID(id uuid.UUID) (*model.Student, error) {
+func (r *StudentRepo) FindByID(ctx context.Context, id uuid.UUID) (*model.Student, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.FindByID")
+	defer finish()
+
 	query := `
-		SELECT s.id, s.user_id, s.student_id, s.program_study, s.academic_year, s.advisor_id, s.created_at,
+		SELECT s.id, s.user_id, s.student_id, s.program_study, s.study_program_id, s.academic_year, s.advisor_id,
+		       s.status, s.status_since, s.skp_frozen_at, s.created_at,
 		       u.username, u.email, u.full_name,
 		       au.full_name
 		FROM students s
@@ -161,17 +232,20 @@ func (r *StudentRepo) FindByID(id uuid.UUID) (*model.Student, error) {
 		WHERE s.id = $1 AND u.is_active = true`
 
 	var s model.Student
+	var statusSince time.Time
 	var userName, userEmail, userFullName sql.NullString
 	var advisorUserFullName sql.NullString
 
-	err := r.DB.QueryRow(query, id).Scan(
-		&s.ID, &s.UserID, &s.StudentID, &s.ProgramStudy, &s.AcademicYear, &s.AdvisorID, &s.CreatedAt,
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(
+		&s.ID, &s.UserID, &s.StudentID, &s.ProgramStudy, &s.StudyProgramID, &s.AcademicYear, &s.AdvisorID,
+		&s.Status, &statusSince, &s.SKPFrozenAt, &s.CreatedAt,
 		&userName, &userEmail, &userFullName,
 		&advisorUserFullName,
 	)
 	if err != nil {
 		return nil, err
 	}
+	s.StatusSince = statusSince.Format(helper.DateLayout)
 
 	s.User.ID = s.UserID
 	if userName.Valid {
@@ -188,9 +262,13 @@ func (r *StudentRepo) FindByID(id uuid.UUID) (*model.Student, error) {
 	return &s, nil
 }
 
-func (r *StudentRepo) FindByUserID(userID uuid.UUID) (*model.Student, error) {
+func (r *StudentRepo) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Student, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.FindByUserID")
+	defer finish()
+
 	query := `
-		SELECT s.id, s.user_id, s.student_id, s.program_study, s.academic_year, s.advisor_id, s.created_at,
+		SELECT s.id, s.user_id, s.student_id, s.program_study, s.study_program_id, s.academic_year, s.advisor_id,
+		       s.status, s.status_since, s.skp_frozen_at, s.created_at,
 		       u.username, u.email, u.full_name,
 		       a.id, a.lecturer_id,
 		       au.full_name
@@ -201,12 +279,14 @@ func (r *StudentRepo) FindByUserID(userID uuid.UUID) (*model.Student, error) {
 		WHERE s.user_id = $1 AND u.is_active = true`
 
 	var s model.Student
+	var statusSince time.Time
 	var userName, userEmail, userFullName sql.NullString
 	var advisorID, advisorLecturerID sql.NullString
 	var advisorUserFullName sql.NullString
 
-	err := r.DB.QueryRow(query, userID).Scan(
-		&s.ID, &s.UserID, &s.StudentID, &s.ProgramStudy, &s.AcademicYear, &s.AdvisorID, &s.CreatedAt,
+	err := r.DB.QueryRowContext(ctx, query, userID).Scan(
+		&s.ID, &s.UserID, &s.StudentID, &s.ProgramStudy, &s.StudyProgramID, &s.AcademicYear, &s.AdvisorID,
+		&s.Status, &statusSince, &s.SKPFrozenAt, &s.CreatedAt,
 		&userName, &userEmail, &userFullName,
 		&advisorID, &advisorLecturerID,
 		&advisorUserFullName,
@@ -214,6 +294,7 @@ func (r *StudentRepo) FindByUserID(userID uuid.UUID) (*model.Student, error) {
 	if err != nil {
 		return nil, err
 	}
+	s.StatusSince = statusSince.Format(helper.DateLayout)
 
 	s.User.ID = s.UserID
 	if userName.Valid {
@@ -234,21 +315,63 @@ func (r *StudentRepo) FindByUserID(userID uuid.UUID) (*model.Student, error) {
 	return &s, nil
 }
 
-func (r *StudentRepo) UpdateAdvisor(studentID uuid.UUID, advisorID uuid.UUID) error {
-	query := `UPDATE students SET advisor_id = $1 WHERE id = $2`
-	_, err := r.DB.Exec(query, advisorID, studentID)
-	return err
+func (r *StudentRepo) IsAdvisedBy(ctx context.Context, studentID uuid.UUID, lecturerUserID uuid.UUID) (bool, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.IsAdvisedBy")
+	defer finish()
+
+	var count int64
+	query := `
+		SELECT COUNT(*)
+		FROM students s
+		JOIN lecturers l ON l.id = s.advisor_id
+		WHERE s.id = $1 AND l.user_id = $2`
+	err := r.DB.QueryRowContext(ctx, query, studentID, lecturerUserID).Scan(&count)
+	return count > 0, err
 }
 
-func (r *StudentRepo) ExistsByStudentID(studentID string) (bool, error) {
+func (r *StudentRepo) ExistsByStudentID(ctx context.Context, studentID string) (bool, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.ExistsByStudentID")
+	defer finish()
+
 	var count int64
 	query := `SELECT COUNT(*) FROM students WHERE student_id = $1`
-	err := r.DB.QueryRow(query, studentID).Scan(&count)
+	err := r.DB.QueryRowContext(ctx, query, studentID).Scan(&count)
 	return count > 0, err
 }
 
-func (r *StudentRepo) DeleteByUserID(userID uuid.UUID) error {
+// FindByStudentIDs mencari mahasiswa berdasarkan NIM; NIM yang tidak
+// ditemukan tidak ada di map hasil.
+func (r *StudentRepo) FindByStudentIDs(ctx context.Context, studentIDs []string) (map[string]model.Student, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.FindByStudentIDs")
+	defer finish()
+
+	query := `
+		SELECT s.id, s.user_id, s.student_id, u.full_name
+		FROM students s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.student_id = ANY($1)`
+	rows, err := r.DB.QueryContext(ctx, query, pq.Array(studentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := make(map[string]model.Student)
+	for rows.Next() {
+		var st model.Student
+		if err := rows.Scan(&st.ID, &st.UserID, &st.StudentID, &st.User.FullName); err != nil {
+			return nil, err
+		}
+		students[st.StudentID] = st
+	}
+	return students, rows.Err()
+}
+
+func (r *StudentRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	ctx, finish := startOp(ctx, "StudentRepo.DeleteByUserID")
+	defer finish()
+
 	query := `DELETE FROM students WHERE user_id = $1`
-	_, err := r.DB.Exec(query, userID)
+	_, err := r.DB.ExecContext(ctx, query, userID)
 	return err
 }
@@ -0,0 +1,106 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"fiber/skp/helper"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// enrolledStudent cocok untuk mahasiswa alias s yang masih terdaftar (belum
+// lulus atau keluar); dipakai untuk menghitung beban dosen wali.
+const enrolledStudent = ` s.status NOT IN ('graduated', 'dropped_out')`
+
+// ChangeStatus mencatat status baru beserta riwayatnya. Status lulus
+// membekukan rekam SKP. sql.ErrNoRows bila mahasiswa tidak ditemukan atau
+// statusnya bukan lagi from.
+func (r *StudentRepo) ChangeStatus(ctx context.Context, change *model.StudentStatusChange, from string) error {
+	ctx, finish := startOp(ctx, "StudentRepo.ChangeStatus")
+	defer finish()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var frozenAt interface{}
+	if change.Status == model.StudentGraduated {
+		frozenAt = now
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE students SET status = $1, status_since = $2, skp_frozen_at = COALESCE($3, skp_frozen_at)
+		WHERE id = $4 AND status = $5`,
+		change.Status, change.EffectiveDate, frozenAt, change.StudentID, from)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO student_status_history (student_id, status, effective_date, reason, changed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		change.StudentID, change.Status, change.EffectiveDate, change.Reason, change.ChangedBy, now).Scan(&change.ID)
+	if err != nil {
+		return err
+	}
+	change.CreatedAt = now
+	return tx.Commit()
+}
+
+// FindStatusHistory memuat riwayat status mahasiswa, terbaru lebih dulu.
+func (r *StudentRepo) FindStatusHistory(ctx context.Context, studentID uuid.UUID) ([]model.StudentStatusChange, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.FindStatusHistory")
+	defer finish()
+
+	query := `
+		SELECT h.id, h.student_id, h.status, h.effective_date, h.reason, h.changed_by, COALESCE(u.full_name, ''), h.created_at
+		FROM student_status_history h
+		LEFT JOIN users u ON u.id = h.changed_by
+		WHERE h.student_id = $1
+		ORDER BY h.effective_date DESC, h.created_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []model.StudentStatusChange{}
+	for rows.Next() {
+		var h model.StudentStatusChange
+		var effectiveDate time.Time
+		var changedBy uuid.NullUUID
+		if err := rows.Scan(&h.ID, &h.StudentID, &h.Status, &effectiveDate, &h.Reason, &changedBy, &h.ChangedByName, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		h.EffectiveDate = effectiveDate.Format(helper.DateLayout)
+		if changedBy.Valid {
+			h.ChangedBy = &changedBy.UUID
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
+
+// CountPendingReviews menghitung achievement mahasiswa yang masih menunggu
+// verifikasi atau keputusan banding.
+func (r *StudentRepo) CountPendingReviews(ctx context.Context, studentID uuid.UUID) (int, error) {
+	ctx, finish := startOp(ctx, "StudentRepo.CountPendingReviews")
+	defer finish()
+
+	query := `
+		SELECT COUNT(*) FROM achievement_references ar
+		WHERE ar.student_id = $1 AND (ar.status = $2 OR EXISTS (
+			SELECT 1 FROM achievement_appeals ap WHERE ap.achievement_id = ar.id AND ap.status = $3
+		))`
+	var count int
+	err := r.DB.QueryRowContext(ctx, query, studentID, model.StatusSubmitted, model.AppealPending).Scan(&count)
+	return count, err
+}
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"context"
 	"database/sql"
 	"fiber/skp/app/model"
 	"fmt"
@@ -10,17 +11,19 @@ import (
 )
 
 type UserRepository interface {
-	Create(user *model.User) error
-	FindByUsername(username string) (*model.User, error)
-	FindByUserID(id uuid.UUID) (*model.User, error)
-	FindByUserIDSimple(id uuid.UUID) (*model.User, error)
-	FindAll(page, limit int, search, sortBy, order string) ([]model.User, int64, error)
-	Update(user *model.User) error
-	Delete(id uuid.UUID) error
-	UpdateRole(userID uuid.UUID, roleID uuid.UUID) error
-	AddBlacklistToken(token model.BlacklistedToken) error
-	ClearRefreshToken(userID uuid.UUID) error
-	FindRoleByName(name string) (*model.Role, error)
+	Create(ctx context.Context, user *model.User) error
+	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	FindByUserID(ctx context.Context, id uuid.UUID) (*model.User, error)
+	FindByUserIDSimple(ctx context.Context, id uuid.UUID) (*model.User, error)
+	FindAll(ctx context.Context, page, limit int, search, sortBy, order string) ([]model.User, int64, error)
+	Update(ctx context.Context, user *model.User) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	UpdateRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error
+	AddBlacklistToken(ctx context.Context, token model.BlacklistedToken) error
+	IsTokenBlacklisted(ctx context.Context, token string) (bool, error)
+	ClearRefreshToken(ctx context.Context, userID uuid.UUID) error
+	FindRoleByName(ctx context.Context, name string) (*model.Role, error)
+	FindActiveIDsByRole(ctx context.Context, roleName string) ([]uuid.UUID, error)
 }
 
 type UserRepo struct {
@@ -40,14 +43,17 @@ var userSortWhitelist = map[string]string{
 	"full_name":  "u.full_name",
 }
 
-func (r *UserRepo) Create(user *model.User) error {
+func (r *UserRepo) Create(ctx context.Context, user *model.User) error {
+	ctx, finish := startOp(ctx, "UserRepo.Create")
+	defer finish()
+
 	query := `
 		INSERT INTO users (username, email, password_hash, full_name, role_id, is_active, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id`
 
 	now := time.Now()
-	return r.DB.QueryRow(
+	return r.DB.QueryRowContext(ctx,
 		query,
 		user.Username,
 		user.Email,
@@ -60,7 +66,10 @@ func (r *UserRepo) Create(user *model.User) error {
 	).Scan(&user.ID)
 }
 
-func (r *UserRepo) FindByUsername(username string) (*model.User, error) {
+func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*model.User, error) {
+	ctx, finish := startOp(ctx, "UserRepo.FindByUsername")
+	defer finish()
+
 	query := `
 		SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.role_id, u.is_active, u.created_at, u.updated_at, u.refresh_token,
 		       r.id, r.name, r.description
@@ -72,7 +81,7 @@ func (r *UserRepo) FindByUsername(username string) (*model.User, error) {
 	var roleID, roleName, roleDesc sql.NullString
 	var refreshToken sql.NullString
 
-	err := r.DB.QueryRow(query, username).Scan(
+	err := r.DB.QueryRowContext(ctx, query, username).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.FullName,
 		&user.RoleID, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &refreshToken,
 		&roleID, &roleName, &roleDesc,
@@ -92,7 +101,7 @@ func (r *UserRepo) FindByUsername(username string) (*model.User, error) {
 	}
 
 	if user.RoleID != nil {
-		permissions, err := r.getPermissionsForRole(*user.RoleID)
+		permissions, err := r.getPermissionsForRole(ctx, *user.RoleID)
 		if err == nil {
 			user.Role.Permissions = permissions
 		}
@@ -101,14 +110,14 @@ func (r *UserRepo) FindByUsername(username string) (*model.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepo) getPermissionsForRole(roleID uuid.UUID) ([]model.Permission, error) {
+func (r *UserRepo) getPermissionsForRole(ctx context.Context, roleID uuid.UUID) ([]model.Permission, error) {
 	query := `
 		SELECT p.id, p.name, p.resource, p.action, p.description
 		FROM permissions p
 		JOIN role_permissions rp ON p.id = rp.permission_id
 		WHERE rp.role_id = $1`
 
-	rows, err := r.DB.Query(query, roleID)
+	rows, err := r.DB.QueryContext(ctx, query, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +138,10 @@ func (r *UserRepo) getPermissionsForRole(roleID uuid.UUID) ([]model.Permission,
 	return permissions, nil
 }
 
-func (r *UserRepo) FindByUserID(id uuid.UUID) (*model.User, error) {
+func (r *UserRepo) FindByUserID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	ctx, finish := startOp(ctx, "UserRepo.FindByUserID")
+	defer finish()
+
 	query := `
 		SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.role_id, u.is_active, u.created_at, u.updated_at, u.refresh_token,
 		       r.id, r.name, r.description
@@ -141,7 +153,7 @@ func (r *UserRepo) FindByUserID(id uuid.UUID) (*model.User, error) {
 	var roleID, roleName, roleDesc sql.NullString
 	var refreshToken sql.NullString
 
-	err := r.DB.QueryRow(query, id).Scan(
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.FullName,
 		&user.RoleID, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &refreshToken,
 		&roleID, &roleName, &roleDesc,
@@ -161,7 +173,7 @@ func (r *UserRepo) FindByUserID(id uuid.UUID) (*model.User, error) {
 	}
 
 	if user.RoleID != nil {
-		permissions, err := r.getPermissionsForRole(*user.RoleID)
+		permissions, err := r.getPermissionsForRole(ctx, *user.RoleID)
 		if err == nil {
 			user.Role.Permissions = permissions
 		}
@@ -170,7 +182,10 @@ func (r *UserRepo) FindByUserID(id uuid.UUID) (*model.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepo) FindByUserIDSimple(id uuid.UUID) (*model.User, error) {
+func (r *UserRepo) FindByUserIDSimple(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	ctx, finish := startOp(ctx, "UserRepo.FindByUserIDSimple")
+	defer finish()
+
 	query := `
 		SELECT u.id, u.username, u.email, u.full_name,
 		       r.id, r.name, r.description
@@ -181,7 +196,7 @@ func (r *UserRepo) FindByUserIDSimple(id uuid.UUID) (*model.User, error) {
 	var user model.User
 	var roleID, roleName, roleDesc sql.NullString
 
-	err := r.DB.QueryRow(query, id).Scan(
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(
 		&user.ID, &user.Username, &user.Email, &user.FullName,
 		&roleID, &roleName, &roleDesc,
 	)
@@ -198,7 +213,10 @@ func (r *UserRepo) FindByUserIDSimple(id uuid.UUID) (*model.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepo) FindAll(page, limit int, search, sortBy, order string) ([]model.User, int64, error) {
+func (r *UserRepo) FindAll(ctx context.Context, page, limit int, search, sortBy, order string) ([]model.User, int64, error) {
+	ctx, finish := startOp(ctx, "UserRepo.FindAll")
+	defer finish()
+
 	var total int64
 
 	countQuery := `SELECT COUNT(*) FROM users WHERE is_active = true`
@@ -211,7 +229,7 @@ func (r *UserRepo) FindAll(page, limit int, search, sortBy, order string) ([]mod
 		argIndex++
 	}
 
-	err := r.DB.QueryRow(countQuery, args...).Scan(&total)
+	err := r.DB.QueryRowContext(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -245,7 +263,7 @@ func (r *UserRepo) FindAll(page, limit int, search, sortBy, order string) ([]mod
 	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", selectArgIndex, selectArgIndex+1)
 	selectArgs = append(selectArgs, limit, offset)
 
-	rows, err := r.DB.Query(query, selectArgs...)
+	rows, err := r.DB.QueryContext(ctx, query, selectArgs...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -275,52 +293,80 @@ func (r *UserRepo) FindAll(page, limit int, search, sortBy, order string) ([]mod
 	return users, total, nil
 }
 
-func (r *UserRepo) Update(user *model.User) error {
+func (r *UserRepo) Update(ctx context.Context, user *model.User) error {
+	ctx, finish := startOp(ctx, "UserRepo.Update")
+	defer finish()
+
 	query := `
 		UPDATE users 
 		SET username = $1, email = $2, password_hash = $3, full_name = $4, refresh_token = $5, updated_at = $6
 		WHERE id = $7`
 
-	_, err := r.DB.Exec(query, user.Username, user.Email, user.PasswordHash, user.FullName, user.RefreshToken, time.Now(), user.ID)
+	_, err := r.DB.ExecContext(ctx, query, user.Username, user.Email, user.PasswordHash, user.FullName, user.RefreshToken, time.Now(), user.ID)
 	return err
 }
 
-func (r *UserRepo) Delete(id uuid.UUID) error {
+func (r *UserRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, finish := startOp(ctx, "UserRepo.Delete")
+	defer finish()
+
 	query := `UPDATE users SET is_active = false WHERE id = $1`
-	_, err := r.DB.Exec(query, id)
+	_, err := r.DB.ExecContext(ctx, query, id)
 	return err
 }
 
-func (r *UserRepo) UpdateRole(userID uuid.UUID, roleID uuid.UUID) error {
+func (r *UserRepo) UpdateRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error {
+	ctx, finish := startOp(ctx, "UserRepo.UpdateRole")
+	defer finish()
+
 	query := `UPDATE users SET role_id = $1, updated_at = $2 WHERE id = $3 AND is_active = true`
-	result, err := r.DB.Exec(query, roleID, time.Now(), userID)
+	result, err := r.DB.ExecContext(ctx, query, roleID, time.Now(), userID)
 	if err != nil {
 		return err
 	}
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("User tidak ditemukan atau user tidak aktif")
+		return sql.ErrNoRows
 	}
 	return nil
 }
 
-func (r *UserRepo) AddBlacklistToken(token model.BlacklistedToken) error {
+func (r *UserRepo) AddBlacklistToken(ctx context.Context, token model.BlacklistedToken) error {
+	ctx, finish := startOp(ctx, "UserRepo.AddBlacklistToken")
+	defer finish()
+
 	query := `INSERT INTO blacklisted_tokens (token, expires_at, created_at) VALUES ($1, $2, $3)`
-	_, err := r.DB.Exec(query, token.Token, token.ExpiresAt, time.Now())
+	_, err := r.DB.ExecContext(ctx, query, token.Token, token.ExpiresAt, time.Now())
 	return err
 }
 
-func (r *UserRepo) ClearRefreshToken(userID uuid.UUID) error {
+func (r *UserRepo) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	ctx, finish := startOp(ctx, "UserRepo.IsTokenBlacklisted")
+	defer finish()
+
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM blacklisted_tokens WHERE token = $1)`
+	err := r.DB.QueryRowContext(ctx, query, token).Scan(&exists)
+	return exists, err
+}
+
+func (r *UserRepo) ClearRefreshToken(ctx context.Context, userID uuid.UUID) error {
+	ctx, finish := startOp(ctx, "UserRepo.ClearRefreshToken")
+	defer finish()
+
 	query := `UPDATE users SET refresh_token = '' WHERE id = $1`
-	_, err := r.DB.Exec(query, userID)
+	_, err := r.DB.ExecContext(ctx, query, userID)
 	return err
 }
 
-func (r *UserRepo) FindRoleByName(name string) (*model.Role, error) {
+func (r *UserRepo) FindRoleByName(ctx context.Context, name string) (*model.Role, error) {
+	ctx, finish := startOp(ctx, "UserRepo.FindRoleByName")
+	defer finish()
+
 	query := `SELECT id, name, description, created_at FROM roles WHERE name = $1`
 	var role model.Role
 	var desc sql.NullString
-	err := r.DB.QueryRow(query, name).Scan(&role.ID, &role.Name, &desc, &role.CreatedAt)
+	err := r.DB.QueryRowContext(ctx, query, name).Scan(&role.ID, &role.Name, &desc, &role.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -329,3 +375,29 @@ func (r *UserRepo) FindRoleByName(name string) (*model.Role, error) {
 	}
 	return &role, nil
 }
+
+// FindActiveIDsByRole mengembalikan id semua user aktif dengan role tersebut.
+func (r *UserRepo) FindActiveIDsByRole(ctx context.Context, roleName string) ([]uuid.UUID, error) {
+	ctx, finish := startOp(ctx, "UserRepo.FindActiveIDsByRole")
+	defer finish()
+
+	query := `
+		SELECT u.id FROM users u
+		JOIN roles ro ON ro.id = u.role_id
+		WHERE ro.name = $1 AND u.is_active`
+	rows, err := r.DB.QueryContext(ctx, query, roleName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
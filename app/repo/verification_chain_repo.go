@@ -0,0 +1,231 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type VerificationChainRepository interface {
+	FindAll(ctx context.Context, includeInactive bool) ([]model.VerificationChain, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.VerificationChain, error)
+	Create(ctx context.Context, chain *model.VerificationChain) error
+	Update(ctx context.Context, chain *model.VerificationChain) error
+	Deactivate(ctx context.Context, id uuid.UUID) error
+	Match(ctx context.Context, achievementType string, details map[string]interface{}) (*model.VerificationChain, error)
+}
+
+type VerificationChainRepo struct {
+	DB *sql.DB
+}
+
+func NewVerificationChainRepo(db *sql.DB) *VerificationChainRepo {
+	return &VerificationChainRepo{DB: db}
+}
+
+const verificationChainColumns = `id, name, achievement_type, level_field, level_value, is_active, created_at, updated_at`
+
+func (r *VerificationChainRepo) FindAll(ctx context.Context, includeInactive bool) ([]model.VerificationChain, error) {
+	ctx, finish := startOp(ctx, "VerificationChainRepo.FindAll")
+	defer finish()
+
+	query := `SELECT ` + verificationChainColumns + ` FROM verification_chains`
+	if !includeInactive {
+		query += ` WHERE is_active`
+	}
+	query += ` ORDER BY achievement_type, level_field, level_value`
+
+	return r.findChains(ctx, query)
+}
+
+func (r *VerificationChainRepo) FindByID(ctx context.Context, id uuid.UUID) (*model.VerificationChain, error) {
+	ctx, finish := startOp(ctx, "VerificationChainRepo.FindByID")
+	defer finish()
+
+	chains, err := r.findChains(ctx, `SELECT `+verificationChainColumns+` FROM verification_chains WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(chains) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &chains[0], nil
+}
+
+func (r *VerificationChainRepo) Create(ctx context.Context, chain *model.VerificationChain) error {
+	ctx, finish := startOp(ctx, "VerificationChainRepo.Create")
+	defer finish()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO verification_chains (name, achievement_type, level_field, level_value, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, TRUE, $5, $5)
+		RETURNING id`,
+		chain.Name, chain.AchievementType, chain.LevelField, chain.LevelValue, now).Scan(&chain.ID)
+	if err != nil {
+		return err
+	}
+	if err := insertStages(ctx, tx, chain); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	chain.IsActive = true
+	chain.CreatedAt = now
+	chain.UpdatedAt = now
+	return nil
+}
+
+// Update menyimpan perubahan rantai dan mengganti seluruh tahapnya.
+func (r *VerificationChainRepo) Update(ctx context.Context, chain *model.VerificationChain) error {
+	ctx, finish := startOp(ctx, "VerificationChainRepo.Update")
+	defer finish()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	chain.UpdatedAt = time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE verification_chains SET name = $1, is_active = $2, updated_at = $3 WHERE id = $4`,
+		chain.Name, chain.IsActive, chain.UpdatedAt, chain.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM verification_stages WHERE chain_id = $1`, chain.ID); err != nil {
+		return err
+	}
+	if err := insertStages(ctx, tx, chain); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Deactivate menonaktifkan rantai. Achievement yang sudah disubmit tetap
+// memakai salinan tahapnya sendiri.
+func (r *VerificationChainRepo) Deactivate(ctx context.Context, id uuid.UUID) error {
+	ctx, finish := startOp(ctx, "VerificationChainRepo.Deactivate")
+	defer finish()
+
+	result, err := r.DB.ExecContext(ctx,
+		`UPDATE verification_chains SET is_active = false, updated_at = $1 WHERE id = $2`,
+		time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Match memilih rantai aktif untuk tipe achievement. Rantai dengan tingkat
+// yang cocok dengan details didahulukan dari rantai umum tipe tersebut.
+// Mengembalikan sql.ErrNoRows bila tidak ada yang cocok.
+func (r *VerificationChainRepo) Match(ctx context.Context, achievementType string, details map[string]interface{}) (*model.VerificationChain, error) {
+	ctx, finish := startOp(ctx, "VerificationChainRepo.Match")
+	defer finish()
+
+	chains, err := r.findChains(ctx, `
+		SELECT `+verificationChainColumns+` FROM verification_chains
+		WHERE is_active AND achievement_type = $1
+		ORDER BY (level_field <> '') DESC, created_at`, achievementType)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range chains {
+		if chains[i].LevelField == "" {
+			return &chains[i], nil
+		}
+		value := fmt.Sprint(details[chains[i].LevelField])
+		if strings.EqualFold(strings.TrimSpace(value), chains[i].LevelValue) {
+			return &chains[i], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *VerificationChainRepo) findChains(ctx context.Context, query string, args ...interface{}) ([]model.VerificationChain, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chains := []model.VerificationChain{}
+	index := make(map[uuid.UUID]int)
+	var ids []string
+	for rows.Next() {
+		var c model.VerificationChain
+		if err := rows.Scan(&c.ID, &c.Name, &c.AchievementType, &c.LevelField, &c.LevelValue, &c.IsActive, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		c.Stages = []model.VerificationStage{}
+		index[c.ID] = len(chains)
+		ids = append(ids, c.ID.String())
+		chains = append(chains, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return chains, nil
+	}
+
+	stageRows, err := r.DB.QueryContext(ctx, `
+		SELECT chain_id, position, name, approver, sla_hours
+		FROM verification_stages
+		WHERE chain_id = ANY($1::uuid[])
+		ORDER BY chain_id, position`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer stageRows.Close()
+
+	for stageRows.Next() {
+		var chainID uuid.UUID
+		var st model.VerificationStage
+		if err := stageRows.Scan(&chainID, &st.Position, &st.Name, &st.Approver, &st.SLAHours); err != nil {
+			return nil, err
+		}
+		i := index[chainID]
+		chains[i].Stages = append(chains[i].Stages, st)
+	}
+	return chains, stageRows.Err()
+}
+
+func insertStages(ctx context.Context, db execer, chain *model.VerificationChain) error {
+	for i := range chain.Stages {
+		chain.Stages[i].Position = i + 1
+		st := chain.Stages[i]
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO verification_stages (chain_id, position, name, approver, sla_hours)
+			VALUES ($1, $2, $3, $4, $5)`,
+			chain.ID, st.Position, st.Name, st.Approver, st.SLAHours)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
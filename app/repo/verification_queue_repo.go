@@ -0,0 +1,197 @@
+package repo
+
+import (
+	"context"
+	"fiber/skp/app/model"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// stageApproverScope mencakup tahap yang boleh diputus user: tahap dosen wali
+// untuk mahasiswa bimbingannya atau yang didelegasikan kepadanya, tahap kepala
+// jurusan untuk jurusan dosen wali, dan tahap admin. userParam adalah
+// placeholder user_id, roleParam placeholder role.
+func stageApproverScope(userParam, roleParam string) string {
+	return `
+	(
+		(st.approver = 'advisor' AND` + fmt.Sprintf(advisorOrDelegateScope, "adv", userParam) + `)
+		OR (st.approver = 'department_head' AND ` + roleParam + ` = 'dosen_wali' AND EXISTS (
+			SELECT 1 FROM lecturers h
+			WHERE h.department_id = adv.department_id AND h.user_id = ` + userParam + ` AND h.is_department_head
+		))
+		OR (st.approver = 'admin' AND ` + roleParam + ` = 'admin')
+	)`
+}
+
+// openStageFrom menghubungkan tahap yang sedang berjalan dengan achievement,
+// mahasiswa, dan dosen walinya (au adalah akun dosen wali).
+const openStageFrom = `
+	FROM achievement_stages st
+	JOIN achievement_references ar ON ar.id = st.achievement_id
+	JOIN students s ON s.id = ar.student_id
+	JOIN users u ON u.id = s.user_id
+	LEFT JOIN lecturers adv ON adv.id = s.advisor_id
+	LEFT JOIN users au ON au.id = adv.user_id
+	WHERE st.started_at IS NOT NULL AND st.decided_at IS NULL AND ar.status = $1`
+
+// FindVerificationQueue memuat achievement yang menunggu keputusan user,
+// yang paling lama disubmit lebih dulu.
+func (r *AchievementRepo) FindVerificationQueue(ctx context.Context, userID uuid.UUID, role string, page, limit int) ([]model.QueueItem, int64, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindVerificationQueue")
+	defer finish()
+
+	where := openStageFrom + ` AND` + stageApproverScope("$2", "$3")
+	args := []interface{}{model.StatusSubmitted, userID, role}
+
+	var total int64
+	if err := r.pgDB.QueryRowContext(ctx, `SELECT COUNT(*)`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	query := `
+		SELECT ar.id, ar.mongo_achievement_id, s.student_id, u.full_name, st.name,
+			COALESCE(ar.submitted_at, st.started_at), st.started_at, st.sla_hours` + where + `
+		ORDER BY COALESCE(ar.submitted_at, st.started_at) ASC
+		LIMIT $4 OFFSET $5`
+	rows, err := r.pgDB.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	items := []model.QueueItem{}
+	mongoIDs := []string{}
+	for rows.Next() {
+		var item model.QueueItem
+		var mongoID string
+		var slaHours int
+		err := rows.Scan(&item.AchievementID, &mongoID, &item.NIM, &item.StudentName, &item.StageName,
+			&item.SubmittedAt, &item.StageStartedAt, &slaHours)
+		if err != nil {
+			return nil, 0, err
+		}
+		item.DueAt = item.StageStartedAt.Add(time.Duration(slaHours) * time.Hour)
+		item.DaysPending = int(now.Sub(item.SubmittedAt).Hours() / 24)
+		item.Overdue = now.After(item.DueAt)
+		items = append(items, item)
+		mongoIDs = append(mongoIDs, mongoID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	titles, err := r.findTitles(ctx, mongoIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		items[i].Title = titles[mongoIDs[i]]
+	}
+	return items, total, nil
+}
+
+// FindOverdueStages memuat tahap yang melewati SLA sebelum now dan belum
+// pernah dieskalasi.
+func (r *AchievementRepo) FindOverdueStages(ctx context.Context, now time.Time) ([]model.OverdueStage, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindOverdueStages")
+	defer finish()
+
+	query := `
+		SELECT ar.id, ar.mongo_achievement_id, st.position, st.name, u.full_name, COALESCE(au.full_name, ''),
+			st.started_at + st.sla_hours * INTERVAL '1 hour'` + openStageFrom + `
+		  AND st.escalated_at IS NULL
+		  AND st.started_at + st.sla_hours * INTERVAL '1 hour' < $2
+		ORDER BY st.started_at`
+	rows, err := r.pgDB.QueryContext(ctx, query, model.StatusSubmitted, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stages := []model.OverdueStage{}
+	mongoIDs := []string{}
+	for rows.Next() {
+		var st model.OverdueStage
+		err := rows.Scan(&st.AchievementID, &st.MongoID, &st.Position, &st.StageName, &st.StudentName, &st.AdvisorName, &st.DueAt)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, st)
+		mongoIDs = append(mongoIDs, st.MongoID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	titles, err := r.findTitles(ctx, mongoIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stages {
+		stages[i].Title = titles[stages[i].MongoID]
+	}
+	return stages, nil
+}
+
+// MarkStageEscalated menandai tahap sudah dieskalasi agar admin tidak
+// menerima notifikasi yang sama berulang kali.
+func (r *AchievementRepo) MarkStageEscalated(ctx context.Context, id uuid.UUID, position int) error {
+	ctx, finish := startOp(ctx, "AchievementRepo.MarkStageEscalated")
+	defer finish()
+
+	_, err := r.pgDB.ExecContext(ctx,
+		`UPDATE achievement_stages SET escalated_at = $1 WHERE achievement_id = $2 AND position = $3`,
+		time.Now(), id, position)
+	return err
+}
+
+// FindSLAStats merangkum ketepatan waktu keputusan verifikasi per dosen.
+func (r *AchievementRepo) FindSLAStats(ctx context.Context) ([]model.LecturerSLAStats, error) {
+	ctx, finish := startOp(ctx, "AchievementRepo.FindSLAStats")
+	defer finish()
+
+	query := `
+		SELECT l.id, l.lecturer_id, u.full_name, COALESCE(l.department, ''),
+			COUNT(*) FILTER (WHERE st.decided_at IS NOT NULL),
+			COUNT(*) FILTER (WHERE st.decided_at <= st.started_at + st.sla_hours * INTERVAL '1 hour'),
+			COALESCE(AVG(EXTRACT(EPOCH FROM st.decided_at - st.started_at) / 3600)
+				FILTER (WHERE st.decided_at IS NOT NULL), 0),
+			COUNT(*) FILTER (WHERE st.decided_at IS NULL AND ar.status = $1),
+			COUNT(*) FILTER (WHERE st.decided_at IS NULL AND ar.status = $1
+				AND st.started_at + st.sla_hours * INTERVAL '1 hour' < $2)
+		FROM achievement_stages st
+		JOIN achievement_references ar ON ar.id = st.achievement_id
+		JOIN students s ON s.id = ar.student_id
+		LEFT JOIN lecturers adv ON adv.id = s.advisor_id
+		JOIN lecturers l ON l.user_id = COALESCE(st.decided_by, CASE WHEN st.approver = 'advisor' THEN adv.user_id END)
+		JOIN users u ON u.id = l.user_id
+		WHERE st.started_at IS NOT NULL AND ar.status != $3
+		GROUP BY l.id, l.lecturer_id, u.full_name, l.department
+		ORDER BY 9 DESC, u.full_name`
+	rows, err := r.pgDB.QueryContext(ctx, query, model.StatusSubmitted, time.Now(), model.StatusDeleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []model.LecturerSLAStats{}
+	for rows.Next() {
+		var st model.LecturerSLAStats
+		err := rows.Scan(&st.ID, &st.LecturerID, &st.FullName, &st.Department,
+			&st.Decided, &st.DecidedWithinSLA, &st.AvgHoursToDecide, &st.Pending, &st.Overdue)
+		if err != nil {
+			return nil, err
+		}
+		if st.Decided > 0 {
+			st.ComplianceRate = math.Round(float64(st.DecidedWithinSLA)/float64(st.Decided)*1000) / 10
+		}
+		st.AvgHoursToDecide = math.Round(st.AvgHoursToDecide*10) / 10
+		stats = append(stats, st)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"slices"
+	"strings"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GET /api/v1/students/:id/advisor-history
+func (s *AcademicService) GetAdvisorHistory(c *fiber.Ctx) error {
+	studentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidStudentID, "student.invalid_id")
+	}
+
+	if _, err := s.studentRepo.FindByID(c.UserContext(), studentID); err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentNotFound, "student.not_found"))
+	}
+
+	history, err := s.studentRepo.FindAdvisorHistory(c.UserContext(), studentID)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.AdvisorAssignment]{
+		Success: true,
+		Data:    history,
+	})
+}
+
+// POST /api/v1/lecturers/:id/reassign-advisees
+func (s *AcademicService) ReassignAdvisees(c *fiber.Ctx) error {
+	fromID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidLecturerID, "lecturer.invalid_id")
+	}
+
+	var req model.ReassignAdviseesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	toID := uuid.MustParse(req.ToLecturerID)
+	if toID == fromID {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+			{Field: "to_lecturer_id", Rule: "invalid"},
+		})
+	}
+	if _, err := s.lecturerRepo.FindByID(c.UserContext(), toID); err != nil {
+		return apperror.Translate(err, apperror.Validation(apperror.CodeLecturerNotFound, "lecturer.not_found", []model.FieldError{
+			{Field: "to_lecturer_id", Rule: "exists"},
+		}))
+	}
+
+	userID := c.Locals("user_id").(uuid.UUID)
+	count, err := s.studentRepo.ReassignAdvisees(c.UserContext(), fromID, toID, userID, req.Reason)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[model.AdvisorReassignResponse]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "advisor.reassigned", count),
+		Data:    model.AdvisorReassignResponse{Reassigned: count},
+	})
+}
+
+// POST /api/v1/students/advisors/bulk
+//
+// Menerima body JSON atau upload CSV (field "file") dengan kolom nim dan nip.
+// Semua pemetaan diterapkan sekaligus; satu baris tidak valid menggagalkan
+// seluruh permintaan.
+func (s *AcademicService) BulkAssignAdvisors(c *fiber.Ctx) error {
+	var req model.BulkAssignAdvisorRequest
+	if file, err := c.FormFile("file"); err == nil {
+		items, err := parseAdvisorCSV(file)
+		if err != nil {
+			return apperror.BadRequest(apperror.CodeInvalidCSV, "advisor.csv_invalid").Wrap(err)
+		}
+		req.Items = items
+		req.Reason = c.FormValue("reason")
+	} else if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	nims := make([]string, len(req.Items))
+	nips := make([]string, len(req.Items))
+	for i := range req.Items {
+		req.Items[i].NIM = strings.TrimSpace(req.Items[i].NIM)
+		req.Items[i].NIP = strings.TrimSpace(req.Items[i].NIP)
+		nims[i] = req.Items[i].NIM
+		nips[i] = req.Items[i].NIP
+	}
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	students, err := s.studentRepo.FindByStudentIDs(c.UserContext(), nims)
+	if err != nil {
+		return apperror.From(err)
+	}
+	lecturers, err := s.lecturerRepo.FindByLecturerIDs(c.UserContext(), nips)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	var fields []model.FieldError
+	seen := make(map[string]bool, len(nims))
+	changes := make([]model.AdvisorChange, 0, len(req.Items))
+	for i, item := range req.Items {
+		nimField := fmt.Sprintf("items[%d].nim", i)
+		if seen[item.NIM] {
+			fields = append(fields, model.FieldError{Field: nimField, Rule: "unique"})
+			continue
+		}
+		seen[item.NIM] = true
+
+		student, ok := students[item.NIM]
+		if !ok {
+			fields = append(fields, model.FieldError{Field: nimField, Rule: "exists"})
+		}
+		lecturer, found := lecturers[item.NIP]
+		if !found {
+			fields = append(fields, model.FieldError{Field: fmt.Sprintf("items[%d].nip", i), Rule: "exists"})
+		}
+		if ok && found {
+			changes = append(changes, model.AdvisorChange{StudentID: student.ID, LecturerID: lecturer.ID})
+		}
+	}
+	if len(fields) > 0 {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", fields)
+	}
+
+	userID := c.Locals("user_id").(uuid.UUID)
+	count, err := s.studentRepo.AssignAdvisors(c.UserContext(), changes, userID, req.Reason)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[model.AdvisorReassignResponse]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "advisor.reassigned", count),
+		Data:    model.AdvisorReassignResponse{Reassigned: count},
+	})
+}
+
+// parseAdvisorCSV membaca CSV dengan baris header; urutan kolom bebas selama
+// kolom nim dan nip ada.
+func parseAdvisorCSV(file *multipart.FileHeader) ([]model.AdvisorMappingItem, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	nimCol, nipCol := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case "nim":
+			nimCol = i
+		case "nip":
+			nipCol = i
+		}
+	}
+	if nimCol < 0 || nipCol < 0 {
+		return nil, fmt.Errorf("kolom nim dan nip wajib ada")
+	}
+
+	var items []model.AdvisorMappingItem
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, model.AdvisorMappingItem{NIM: record[nimCol], NIP: record[nipCol]})
+	}
+	return items, nil
+}
+
+// PUT /api/v1/lecturers/:id/capacity
+func (s *AcademicService) SetMaxAdvisees(c *fiber.Ctx) error {
+	lecturerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidLecturerID, "lecturer.invalid_id")
+	}
+
+	var req model.AdvisorCapacityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	if err := s.lecturerRepo.SetMaxAdvisees(c.UserContext(), lecturerID, req.MaxAdvisees); err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeLecturerNotFound, "lecturer.not_found"))
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "lecturer.capacity_updated"),
+	})
+}
+
+// POST /api/v1/students/advisors/auto-assign/preview
+func (s *AcademicService) PreviewAutoAssign(c *fiber.Ctx) error {
+	req, err := parseAutoAssignRequest(c)
+	if err != nil {
+		return err
+	}
+
+	plan, err := s.planAutoAssign(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(model.SuccessResponse[model.AutoAssignPlan]{
+		Success: true,
+		Data:    plan,
+	})
+}
+
+// POST /api/v1/students/advisors/auto-assign
+//
+// Usulan yang diterapkan adalah usulan preview yang dikirim ulang admin,
+// diperiksa kembali dalam satu transaksi. Bila data berubah sejak preview,
+// tidak ada yang diterapkan dan admin perlu meminta preview baru.
+func (s *AcademicService) ApplyAutoAssign(c *fiber.Ctx) error {
+	var req model.ApplyAutoAssignRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	var fields []model.FieldError
+	seen := make(map[uuid.UUID]bool, len(req.Proposals))
+	changes := make([]model.AdvisorChange, 0, len(req.Proposals))
+	for i, p := range req.Proposals {
+		studentID := uuid.MustParse(p.StudentID)
+		if seen[studentID] {
+			fields = append(fields, model.FieldError{Field: fmt.Sprintf("proposals[%d].student_id", i), Rule: "unique"})
+			continue
+		}
+		seen[studentID] = true
+		changes = append(changes, model.AdvisorChange{StudentID: studentID, LecturerID: uuid.MustParse(p.LecturerID)})
+	}
+	if len(fields) > 0 {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", fields)
+	}
+
+	userID := c.Locals("user_id").(uuid.UUID)
+	if err := s.studentRepo.AssignUnassigned(c.UserContext(), changes, userID, req.Reason); err != nil {
+		return apperror.Translate(err, apperror.Conflict(apperror.CodeAdvisorPlanStale, "advisor.plan_stale"))
+	}
+
+	return c.JSON(model.SuccessResponse[model.AdvisorReassignResponse]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "advisor.auto_assigned", len(changes)),
+		Data:    model.AdvisorReassignResponse{Reassigned: len(changes)},
+	})
+}
+
+func parseAutoAssignRequest(c *fiber.Ctx) (model.AutoAssignRequest, error) {
+	var req model.AutoAssignRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return req, apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+		}
+	}
+	req.AcademicYear = strings.TrimSpace(req.AcademicYear)
+	if err := helper.ValidateStruct(req); err != nil {
+		return req, apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+	return req, nil
+}
+
+func (s *AcademicService) planAutoAssign(c *fiber.Ctx, req model.AutoAssignRequest) (model.AutoAssignPlan, error) {
+	unit := model.AcademicUnitFilter{
+		FacultyID:      optionalUUID(req.FacultyID),
+		DepartmentID:   optionalUUID(req.DepartmentID),
+		StudyProgramID: optionalUUID(req.StudyProgramID),
+	}
+
+	students, err := s.studentRepo.FindUnassigned(c.UserContext(), unit, req.AcademicYear)
+	if err != nil {
+		return model.AutoAssignPlan{}, apperror.From(err)
+	}
+	loads, err := s.lecturerRepo.FindAdvisorLoads(c.UserContext())
+	if err != nil {
+		return model.AutoAssignPlan{}, apperror.From(err)
+	}
+	return balanceAdvisors(students, loads), nil
+}
+
+// balanceAdvisors membagi mahasiswa ke dosen dari jurusan yang menaungi
+// program studi mahasiswa. Setiap mahasiswa diberikan ke dosen dengan beban
+// terkecil yang masih di bawah batas; beban sama diputus menurut NIP agar
+// preview selalu sama untuk data yang sama.
+func balanceAdvisors(students []model.Student, loads []model.AdvisorLoad) model.AutoAssignPlan {
+	slices.SortStableFunc(loads, func(a, b model.AdvisorLoad) int {
+		return strings.Compare(a.LecturerID, b.LecturerID)
+	})
+	byDepartment := make(map[uuid.UUID][]int)
+	for i, l := range loads {
+		if l.DepartmentID != nil {
+			byDepartment[*l.DepartmentID] = append(byDepartment[*l.DepartmentID], i)
+		}
+	}
+
+	plan := model.AutoAssignPlan{
+		Proposals: []model.AdvisorProposal{},
+		Unplaced:  []model.UnplacedStudent{},
+	}
+	relevant := make(map[uuid.UUID]bool)
+	for _, st := range students {
+		var candidates []int
+		if st.DepartmentID != nil {
+			relevant[*st.DepartmentID] = true
+			candidates = byDepartment[*st.DepartmentID]
+		}
+		best := -1
+		for _, i := range candidates {
+			l := loads[i]
+			total := l.Advisees + l.Proposed
+			if l.MaxAdvisees != nil && total >= *l.MaxAdvisees {
+				continue
+			}
+			if best < 0 || total < loads[best].Advisees+loads[best].Proposed {
+				best = i
+			}
+		}
+
+		if best < 0 {
+			reason := model.UnplacedCapacityFull
+			if len(candidates) == 0 {
+				reason = model.UnplacedNoLecturer
+			}
+			plan.Unplaced = append(plan.Unplaced, model.UnplacedStudent{
+				StudentID:    st.ID,
+				NIM:          st.StudentID,
+				StudentName:  st.User.FullName,
+				ProgramStudy: st.ProgramStudy,
+				Reason:       reason,
+			})
+			continue
+		}
+
+		loads[best].Proposed++
+		plan.Proposals = append(plan.Proposals, model.AdvisorProposal{
+			StudentID:    st.ID,
+			NIM:          st.StudentID,
+			StudentName:  st.User.FullName,
+			ProgramStudy: st.ProgramStudy,
+			LecturerID:   loads[best].ID,
+			NIP:          loads[best].LecturerID,
+			LecturerName: loads[best].FullName,
+		})
+	}
+
+	// Hanya dosen dari jurusan mahasiswa yang dibagikan yang ditampilkan.
+	plan.Loads = []model.AdvisorLoad{}
+	for _, l := range loads {
+		if l.DepartmentID != nil && relevant[*l.DepartmentID] {
+			plan.Loads = append(plan.Loads, l)
+		}
+	}
+	return plan
+}
+
+// optionalUUID mengubah string yang sudah divalidasi menjadi pointer; string
+// kosong menjadi nil.
+func optionalUUID(raw string) *uuid.UUID {
+	if raw == "" {
+		return nil
+	}
+	id := uuid.MustParse(raw)
+	return &id
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"fiber/skp/app/model"
+
+	"github.com/google/uuid"
+)
+
+func TestBalanceAdvisors(t *testing.T) {
+	deptA := uuid.New()
+	deptB := uuid.New()
+	one := 1
+
+	student := func(nim string, dept *uuid.UUID) model.Student {
+		return model.Student{ID: uuid.New(), StudentID: nim, DepartmentID: dept}
+	}
+	lecturer := func(nip string, dept *uuid.UUID, advisees int, max *int) model.AdvisorLoad {
+		return model.AdvisorLoad{ID: uuid.New(), LecturerID: nip, DepartmentID: dept, Advisees: advisees, MaxAdvisees: max}
+	}
+
+	tests := []struct {
+		name     string
+		students []model.Student
+		loads    []model.AdvisorLoad
+		// want berisi NIP dosen per NIM, atau alasan bila tidak terbagi.
+		want map[string]string
+	}{
+		{
+			name:     "beban terkecil didahulukan",
+			students: []model.Student{student("1", &deptA), student("2", &deptA)},
+			loads:    []model.AdvisorLoad{lecturer("100", &deptA, 3, nil), lecturer("200", &deptA, 1, nil)},
+			want:     map[string]string{"1": "200", "2": "200"},
+		},
+		{
+			name:     "batas bimbingan tercapai",
+			students: []model.Student{student("1", &deptA), student("2", &deptA)},
+			loads:    []model.AdvisorLoad{lecturer("100", &deptA, 0, &one)},
+			want:     map[string]string{"1": "100", "2": model.UnplacedCapacityFull},
+		},
+		{
+			name:     "tidak ada dosen di jurusan",
+			students: []model.Student{student("1", &deptB), student("2", nil)},
+			loads:    []model.AdvisorLoad{lecturer("100", &deptA, 0, nil)},
+			want:     map[string]string{"1": model.UnplacedNoLecturer, "2": model.UnplacedNoLecturer},
+		},
+		{
+			name:     "beban sama diputus menurut NIP",
+			students: []model.Student{student("1", &deptA), student("2", &deptA), student("3", &deptA)},
+			loads:    []model.AdvisorLoad{lecturer("300", &deptA, 0, nil), lecturer("100", &deptA, 0, nil), lecturer("200", &deptA, 0, nil)},
+			want:     map[string]string{"1": "100", "2": "200", "3": "300"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := balanceAdvisors(tt.students, tt.loads)
+
+			got := make(map[string]string)
+			for _, p := range plan.Proposals {
+				got[p.NIM] = p.NIP
+			}
+			for _, u := range plan.Unplaced {
+				got[u.NIM] = u.Reason
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for nim, want := range tt.want {
+				if got[nim] != want {
+					t.Errorf("NIM %s: got %q, want %q", nim, got[nim], want)
+				}
+			}
+		})
+	}
+}
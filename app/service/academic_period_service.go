@@ -0,0 +1,301 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	errAcademicPeriodNotFound   = apperror.NotFound(apperror.CodeAcademicPeriodNotFound, "academic_period.not_found")
+	errSubmissionWindowNotFound = apperror.NotFound(apperror.CodeSubmissionWindowNotFound, "submission_window.not_found")
+)
+
+type AcademicPeriodService struct {
+	periodRepo  repo.AcademicPeriodRepository
+	studentRepo repo.StudentRepository
+}
+
+func NewAcademicPeriodService(periodRepo repo.AcademicPeriodRepository, studentRepo repo.StudentRepository) *AcademicPeriodService {
+	return &AcademicPeriodService{periodRepo: periodRepo, studentRepo: studentRepo}
+}
+
+// GET /api/v1/academic-periods
+func (s *AcademicPeriodService) List(c *fiber.Ctx) error {
+	periods, err := s.periodRepo.FindAll(c.UserContext())
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.AcademicPeriod]{
+		Success: true,
+		Data:    periods,
+	})
+}
+
+// POST /api/v1/academic-periods
+func (s *AcademicPeriodService) Create(c *fiber.Ctx) error {
+	req, err := s.parsePeriodRequest(c, nil)
+	if err != nil {
+		return err
+	}
+
+	period := model.AcademicPeriod{Year: req.Year, Semester: req.Semester, StartsOn: req.StartsOn, EndsOn: req.EndsOn}
+	if err := s.periodRepo.Create(c.UserContext(), &period); err != nil {
+		return periodWriteError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.SuccessResponse[model.AcademicPeriod]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "academic_period.created"),
+		Data:    period,
+	})
+}
+
+// PUT /api/v1/academic-periods/:id
+func (s *AcademicPeriodService) Update(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAcademicPeriodID, "academic_period.invalid_id")
+	}
+
+	req, err := s.parsePeriodRequest(c, &id)
+	if err != nil {
+		return err
+	}
+
+	period := model.AcademicPeriod{ID: id, Year: req.Year, Semester: req.Semester, StartsOn: req.StartsOn, EndsOn: req.EndsOn}
+	if err := s.periodRepo.Update(c.UserContext(), &period); err != nil {
+		return periodWriteError(err)
+	}
+
+	return c.JSON(model.SuccessResponse[model.AcademicPeriod]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "academic_period.updated"),
+		Data:    period,
+	})
+}
+
+// DELETE /api/v1/academic-periods/:id
+func (s *AcademicPeriodService) Delete(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAcademicPeriodID, "academic_period.invalid_id")
+	}
+
+	if err := s.periodRepo.Delete(c.UserContext(), id); err != nil {
+		return apperror.Translate(err, errAcademicPeriodNotFound)
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "academic_period.deleted"),
+	})
+}
+
+// GET /api/v1/academic-periods/:id/windows
+func (s *AcademicPeriodService) ListWindows(c *fiber.Ctx) error {
+	period, err := s.findPeriod(c)
+	if err != nil {
+		return err
+	}
+
+	windows, err := s.periodRepo.FindWindows(c.UserContext(), period.ID)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.SubmissionWindow]{
+		Success: true,
+		Data:    windows,
+	})
+}
+
+// POST /api/v1/academic-periods/:id/windows
+func (s *AcademicPeriodService) CreateWindow(c *fiber.Ctx) error {
+	period, err := s.findPeriod(c)
+	if err != nil {
+		return err
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	var req model.SubmissionWindowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	window := model.SubmissionWindow{
+		PeriodID: period.ID,
+		OpensAt:  req.OpensAt,
+		ClosesAt: req.ClosesAt,
+		Note:     req.Note,
+	}
+	if err := s.periodRepo.CreateWindow(c.UserContext(), &window, userID); err != nil {
+		return apperror.From(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.SuccessResponse[model.SubmissionWindow]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "submission_window.created"),
+		Data:    window,
+	})
+}
+
+// DELETE /api/v1/academic-periods/:id/windows/:windowId
+func (s *AcademicPeriodService) DeleteWindow(c *fiber.Ctx) error {
+	periodID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAcademicPeriodID, "academic_period.invalid_id")
+	}
+	windowID, err := uuid.Parse(c.Params("windowId"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidSubmissionWindowID, "submission_window.invalid_id")
+	}
+
+	if err := s.periodRepo.DeleteWindow(c.UserContext(), periodID, windowID); err != nil {
+		return apperror.Translate(err, errSubmissionWindowNotFound)
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "submission_window.deleted"),
+	})
+}
+
+// GET /api/v1/submission-exemptions
+func (s *AcademicPeriodService) ListExemptions(c *fiber.Ctx) error {
+	exemptions, err := s.periodRepo.FindExemptions(c.UserContext(), c.QueryBool("active", false))
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.SubmissionExemption]{
+		Success: true,
+		Data:    exemptions,
+	})
+}
+
+// POST /api/v1/submission-exemptions
+func (s *AcademicPeriodService) CreateExemption(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	var req model.SubmissionExemptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+	if !req.ExpiresAt.After(time.Now()) {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+			{Field: "expires_at", Rule: "date_after", Param: time.Now().Format(helper.DateLayout)},
+		})
+	}
+
+	student, err := s.studentRepo.FindByID(c.UserContext(), uuid.MustParse(req.StudentID))
+	if err != nil {
+		return apperror.Translate(err, apperror.Validation(apperror.CodeStudentNotFound, "student.not_found", []model.FieldError{
+			{Field: "student_id", Rule: "exists"},
+		}))
+	}
+
+	exemption := model.SubmissionExemption{
+		StudentID: student.ID,
+		ExpiresAt: req.ExpiresAt,
+		Reason:    req.Reason,
+	}
+	if req.PeriodID != "" {
+		period, err := s.periodRepo.FindByID(c.UserContext(), uuid.MustParse(req.PeriodID))
+		if err != nil {
+			return apperror.Translate(err, apperror.Validation(apperror.CodeAcademicPeriodNotFound, "academic_period.not_found", []model.FieldError{
+				{Field: "period_id", Rule: "exists"},
+			}))
+		}
+		exemption.PeriodID = &period.ID
+	}
+
+	if err := s.periodRepo.CreateExemption(c.UserContext(), &exemption, userID); err != nil {
+		return apperror.From(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.SuccessResponse[model.SubmissionExemption]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "submission_exemption.created"),
+		Data:    exemption,
+	})
+}
+
+// DELETE /api/v1/submission-exemptions/:id
+func (s *AcademicPeriodService) DeleteExemption(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidExemptionID, "submission_exemption.invalid_id")
+	}
+
+	if err := s.periodRepo.DeleteExemption(c.UserContext(), id); err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeExemptionNotFound, "submission_exemption.not_found"))
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "submission_exemption.deleted"),
+	})
+}
+
+func (s *AcademicPeriodService) findPeriod(c *fiber.Ctx) (*model.AcademicPeriod, error) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.CodeInvalidAcademicPeriodID, "academic_period.invalid_id")
+	}
+	period, err := s.periodRepo.FindByID(c.UserContext(), id)
+	if err != nil {
+		return nil, apperror.Translate(err, errAcademicPeriodNotFound)
+	}
+	return period, nil
+}
+
+// parsePeriodRequest memvalidasi body periode dan menolak rentang yang
+// beririsan dengan periode lain selain excludeID.
+func (s *AcademicPeriodService) parsePeriodRequest(c *fiber.Ctx, excludeID *uuid.UUID) (model.AcademicPeriodRequest, error) {
+	var req model.AcademicPeriodRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Year = strings.TrimSpace(req.Year)
+	if err := helper.ValidateStruct(req); err != nil {
+		return req, apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	overlap, err := s.periodRepo.HasOverlap(c.UserContext(), req.StartsOn, req.EndsOn, excludeID)
+	if err != nil {
+		return req, apperror.From(err)
+	}
+	if overlap {
+		return req, apperror.Conflict(apperror.CodeAcademicPeriodOverlap, "academic_period.overlap")
+	}
+	return req, nil
+}
+
+// periodWriteError memetakan pelanggaran unique (tahun, semester).
+func periodWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return apperror.Conflict(apperror.CodeAcademicPeriodExists, "academic_period.exists").Wrap(err)
+	}
+	return apperror.Translate(err, errAcademicPeriodNotFound)
+}
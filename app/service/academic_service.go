@@ -1,9 +1,13 @@
 package service
 
 import (
+	"fiber/skp/app/apperror"
 	"fiber/skp/app/model"
 	"fiber/skp/app/repo"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
 	"math"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -37,12 +41,22 @@ func (s *AcademicService) GetAllStudents(c *fiber.Ctx) error {
 		sortBy = "created_at"
 	}
 
-	students, total, err := s.studentRepo.FindAll(page, limit, search, sortBy, order)
+	unit, err := academicUnitFilter(c)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return err
+	}
+
+	// Secara default hanya mahasiswa aktif; status=all untuk semua status.
+	status := c.Query("status", model.StudentActive)
+	if status == "all" {
+		status = ""
+	} else if _, ok := model.StudentStatusTransitions[status]; !ok {
+		return apperror.BadRequest(apperror.CodeInvalidStudentStatus, "student.invalid_status")
+	}
+
+	students, total, err := s.studentRepo.FindAll(c.UserContext(), page, limit, search, sortBy, order, status, unit)
+	if err != nil {
+		return apperror.From(err)
 	}
 
 	var response []model.StudentListResponse
@@ -53,11 +67,13 @@ func (s *AcademicService) GetAllStudents(c *fiber.Ctx) error {
 		}
 
 		response = append(response, model.StudentListResponse{
-			ID:           st.ID,
-			StudentID:    st.StudentID,
-			FullName:     st.User.FullName,
-			ProgramStudy: st.ProgramStudy,
-			AdvisorName:  advisorName,
+			ID:             st.ID,
+			StudentID:      st.StudentID,
+			FullName:       st.User.FullName,
+			ProgramStudy:   st.ProgramStudy,
+			StudyProgramID: st.StudyProgramID,
+			Status:         st.Status,
+			AdvisorName:    advisorName,
 		})
 	}
 
@@ -85,20 +101,12 @@ func (s *AcademicService) GetStudentDetail(c *fiber.Ctx) error {
 	id := c.Params("id")
 	studentUUID, err := uuid.Parse(id)
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "student_id tidak valid",
-			Error:   err.Error(),
-		})
+		return apperror.BadRequest(apperror.CodeInvalidStudentID, "student.invalid_id")
 	}
 
-	st, err := s.studentRepo.FindByID(studentUUID)
+	st, err := s.studentRepo.FindByID(c.UserContext(), studentUUID)
 	if err != nil {
-		return c.Status(404).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Mahasiswa tidak ditemukan",
-			Error:   err.Error(),
-		})
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentNotFound, "student.not_found"))
 	}
 
 	advisorName := ""
@@ -109,12 +117,16 @@ func (s *AcademicService) GetStudentDetail(c *fiber.Ctx) error {
 	return c.JSON(model.SuccessResponse[model.StudentDetailResponse]{
 		Success: true,
 		Data: model.StudentDetailResponse{
-			ID:           st.ID,
-			StudentID:    st.StudentID,
-			FullName:     st.User.FullName,
-			Email:        st.User.Email,
-			ProgramStudy: st.ProgramStudy,
-			AdvisorName:  advisorName,
+			ID:             st.ID,
+			StudentID:      st.StudentID,
+			FullName:       st.User.FullName,
+			Email:          st.User.Email,
+			ProgramStudy:   st.ProgramStudy,
+			StudyProgramID: st.StudyProgramID,
+			Status:         st.Status,
+			StatusSince:    st.StatusSince,
+			SKPFrozenAt:    st.SKPFrozenAt,
+			AdvisorName:    advisorName,
 		},
 	})
 }
@@ -123,48 +135,36 @@ func (s *AcademicService) GetStudentDetail(c *fiber.Ctx) error {
 func (s *AcademicService) AssignAdvisor(c *fiber.Ctx) error {
 	studentID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "student_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidStudentID, "student.invalid_id")
 	}
 
-	var req struct {
-		AdvisorID string `json:"lecturer_id"`
-	}
+	var req model.AssignAdvisorRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Invalid input",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input")
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
 	}
 
-	advisorUUID, err := uuid.Parse(req.AdvisorID)
+	advisorUUID, err := uuid.Parse(req.LecturerID)
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "lecturer_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidLecturerID, "lecturer.invalid_id")
 	}
 
-	_, err = s.lecturerRepo.FindByID(advisorUUID)
+	_, err = s.lecturerRepo.FindByID(c.UserContext(), advisorUUID)
 	if err != nil {
-		return c.Status(404).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Lecturer tidak ditemukan",
-		})
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeLecturerNotFound, "lecturer.not_found"))
 	}
 
-	if err := s.studentRepo.UpdateAdvisor(studentID, advisorUUID); err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal mengassign advisor",
-		})
+	userID := c.Locals("user_id").(uuid.UUID)
+	if err := s.studentRepo.UpdateAdvisor(c.UserContext(), studentID, advisorUUID, userID, req.Reason); err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentNotFound, "student.not_found"))
 	}
 
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "Advisor berhasil diassign",
+		Message: i18n.T(c.UserContext(), "student.advisor_assigned"),
 	})
 }
 
@@ -172,10 +172,7 @@ func (s *AcademicService) AssignAdvisor(c *fiber.Ctx) error {
 func (s *AcademicService) GetStudentAchievements(c *fiber.Ctx) error {
 	studentID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "student_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidStudentID, "student.invalid_id")
 	}
 
 	page := c.QueryInt("page", 1)
@@ -189,20 +186,14 @@ func (s *AcademicService) GetStudentAchievements(c *fiber.Ctx) error {
 		sortBy = "created_at"
 	}
 
-	student, err := s.studentRepo.FindByID(studentID)
+	student, err := s.studentRepo.FindByID(c.UserContext(), studentID)
 	if err != nil {
-		return c.Status(404).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Mahasiswa tidak ditemukan",
-		})
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentNotFound, "student.not_found"))
 	}
 
-	achievements, total, err := s.achieveRepo.FindAll(model.RoleMahasiswa, student.UserID, page, limit, search, sortBy, order)
+	achievements, total, err := s.achieveRepo.FindAll(c.UserContext(), model.RoleMahasiswa, student.UserID, page, limit, search, sortBy, order)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal mengambil data achievement",
-		})
+		return apperror.From(err)
 	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
@@ -237,21 +228,24 @@ func (s *AcademicService) GetAllLecturers(c *fiber.Ctx) error {
 		sortBy = "created_at"
 	}
 
-	lecturers, total, err := s.lecturerRepo.FindAll(page, limit, search, sortBy, order)
+	unit, err := academicUnitFilter(c)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return err
+	}
+
+	lecturers, total, err := s.lecturerRepo.FindAll(c.UserContext(), page, limit, search, sortBy, order, unit)
+	if err != nil {
+		return apperror.From(err)
 	}
 
 	var response []model.LecturerListResponse
 	for _, l := range lecturers {
 		response = append(response, model.LecturerListResponse{
-			ID:         l.ID,
-			LecturerID: l.LecturerID,
-			FullName:   l.User.FullName,
-			Department: l.Department,
+			ID:           l.ID,
+			LecturerID:   l.LecturerID,
+			FullName:     l.User.FullName,
+			Department:   l.Department,
+			DepartmentID: l.DepartmentID,
 		})
 	}
 
@@ -278,26 +272,17 @@ func (s *AcademicService) GetAllLecturers(c *fiber.Ctx) error {
 func (s *AcademicService) GetAdvisees(c *fiber.Ctx) error {
 	advisorID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "lecturer_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidLecturerID, "lecturer.invalid_id")
 	}
 
-	lecturer, err := s.lecturerRepo.FindByID(advisorID)
+	lecturer, err := s.lecturerRepo.FindByID(c.UserContext(), advisorID)
 	if err != nil {
-		return c.Status(404).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Lecturer tidak ditemukan",
-		})
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeLecturerNotFound, "lecturer.not_found"))
 	}
 
-	students, err := s.lecturerRepo.GetAdvisees(advisorID)
+	students, err := s.lecturerRepo.GetAdvisees(c.UserContext(), advisorID)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal mengambil data mahasiswa",
-		})
+		return apperror.From(err)
 	}
 
 	var response []model.StudentListResponse
@@ -327,3 +312,55 @@ func (s *AcademicService) GetAdvisees(c *fiber.Ctx) error {
 		},
 	})
 }
+
+// PUT /api/v1/lecturers/:id/appeal-reviewer
+func (s *AcademicService) SetAppealReviewer(c *fiber.Ctx) error {
+	lecturerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidLecturerID, "lecturer.invalid_id")
+	}
+
+	var req model.AppealReviewerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input")
+	}
+
+	if err := s.lecturerRepo.SetAppealReviewer(c.UserContext(), lecturerID, req.Enabled); err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeLecturerNotFound, "lecturer.not_found"))
+	}
+
+	key := "lecturer.appeal_reviewer_disabled"
+	if req.Enabled {
+		key = "lecturer.appeal_reviewer_enabled"
+	}
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), key),
+	})
+}
+
+// PUT /api/v1/lecturers/:id/department-head
+func (s *AcademicService) SetDepartmentHead(c *fiber.Ctx) error {
+	lecturerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidLecturerID, "lecturer.invalid_id")
+	}
+
+	var req model.DepartmentHeadRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input")
+	}
+
+	if err := s.lecturerRepo.SetDepartmentHead(c.UserContext(), lecturerID, req.Enabled); err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeLecturerNotFound, "lecturer.not_found"))
+	}
+
+	key := "lecturer.department_head_disabled"
+	if req.Enabled {
+		key = "lecturer.department_head_enabled"
+	}
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), key),
+	})
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// PUT /api/v1/students/:id/status
+func (s *AcademicService) ChangeStudentStatus(c *fiber.Ctx) error {
+	studentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidStudentID, "student.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	var req model.ChangeStudentStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	student, err := s.studentRepo.FindByID(c.UserContext(), studentID)
+	if err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentNotFound, "student.not_found"))
+	}
+	if !slices.Contains(model.StudentStatusTransitions[student.Status], req.Status) {
+		return apperror.Conflict(apperror.CodeStudentStatusTransition, "student.status_transition",
+			studentStatusLabel(c.UserContext(), student.Status), studentStatusLabel(c.UserContext(), req.Status))
+	}
+
+	// Tanggal berlaku boleh mundur, tetapi tidak sebelum perubahan terakhir
+	// dan tidak di masa depan.
+	today := time.Now().Format(helper.DateLayout)
+	if req.EffectiveDate == "" {
+		req.EffectiveDate = today
+	}
+	if req.EffectiveDate > today {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+			{Field: "effective_date", Rule: "invalid"},
+		})
+	}
+	if req.EffectiveDate < student.StatusSince {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+			{Field: "effective_date", Rule: "date_after", Param: student.StatusSince},
+		})
+	}
+
+	// Rekam SKP dibekukan saat lulus, jadi tidak boleh ada yang masih diproses.
+	if req.Status == model.StudentGraduated {
+		pending, err := s.studentRepo.CountPendingReviews(c.UserContext(), studentID)
+		if err != nil {
+			return apperror.From(err)
+		}
+		if pending > 0 {
+			return apperror.Conflict(apperror.CodeStudentPendingReviews, "student.pending_reviews", pending)
+		}
+	}
+
+	change := model.StudentStatusChange{
+		StudentID:     studentID,
+		Status:        req.Status,
+		EffectiveDate: req.EffectiveDate,
+		Reason:        req.Reason,
+		ChangedBy:     &userID,
+	}
+	if err := s.studentRepo.ChangeStatus(c.UserContext(), &change, student.Status); err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentNotFound, "student.not_found"))
+	}
+
+	return c.JSON(model.SuccessResponse[model.StudentStatusChange]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "student.status_changed"),
+		Data:    change,
+	})
+}
+
+// GET /api/v1/students/:id/status-history
+func (s *AcademicService) GetStatusHistory(c *fiber.Ctx) error {
+	studentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidStudentID, "student.invalid_id")
+	}
+
+	if _, err := s.studentRepo.FindByID(c.UserContext(), studentID); err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentNotFound, "student.not_found"))
+	}
+
+	history, err := s.studentRepo.FindStatusHistory(c.UserContext(), studentID)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.StudentStatusChange]{
+		Success: true,
+		Data:    history,
+	})
+}
+
+// ensureStudentActive menolak pembuatan dan pengajuan achievement oleh
+// mahasiswa yang tidak berstatus aktif.
+func ensureStudentActive(ctx context.Context, student *model.Student) error {
+	if student.Status == model.StudentActive {
+		return nil
+	}
+	return apperror.Forbidden(apperror.CodeStudentNotActive, "student.not_active", studentStatusLabel(ctx, student.Status))
+}
+
+func studentStatusLabel(ctx context.Context, status string) string {
+	return i18n.T(ctx, "student_status."+status)
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var errAcademicUnitNotFound = apperror.NotFound(apperror.CodeAcademicUnitNotFound, "academic_unit.not_found")
+
+// parentUnitKind adalah jenis induk setiap unit; fakultas tidak punya induk.
+var parentUnitKind = map[string]string{
+	model.UnitDepartment:   model.UnitFaculty,
+	model.UnitStudyProgram: model.UnitDepartment,
+}
+
+// AcademicUnitService melayani fakultas, jurusan, dan program studi dengan
+// handler yang sama; setiap handler dibuat untuk satu jenis unit.
+type AcademicUnitService struct {
+	unitRepo repo.AcademicUnitRepository
+}
+
+func NewAcademicUnitService(unitRepo repo.AcademicUnitRepository) *AcademicUnitService {
+	return &AcademicUnitService{unitRepo: unitRepo}
+}
+
+// GET /api/v1/{faculties,departments,study-programs}
+func (s *AcademicUnitService) List(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var parentID *uuid.UUID
+		if raw := c.Query("parent_id"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return apperror.BadRequest(apperror.CodeInvalidAcademicUnitID, "academic_unit.invalid_id")
+			}
+			parentID = &id
+		}
+
+		units, err := s.unitRepo.FindAll(c.UserContext(), kind, parentID)
+		if err != nil {
+			return apperror.From(err)
+		}
+
+		return c.JSON(model.SuccessResponse[[]model.AcademicUnit]{
+			Success: true,
+			Data:    units,
+		})
+	}
+}
+
+// POST /api/v1/{faculties,departments,study-programs}
+func (s *AcademicUnitService) Create(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req, err := s.parseUnitRequest(c, kind)
+		if err != nil {
+			return err
+		}
+
+		unit := model.AcademicUnit{Code: req.Code, Name: req.Name, ParentID: req.parentID}
+		if err := s.unitRepo.Create(c.UserContext(), kind, &unit); err != nil {
+			return unitWriteError(err)
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(model.SuccessResponse[model.AcademicUnit]{
+			Success: true,
+			Message: i18n.T(c.UserContext(), "academic_unit.created"),
+			Data:    unit,
+		})
+	}
+}
+
+// PUT /api/v1/{faculties,departments,study-programs}/:id
+func (s *AcademicUnitService) Update(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return apperror.BadRequest(apperror.CodeInvalidAcademicUnitID, "academic_unit.invalid_id")
+		}
+
+		req, err := s.parseUnitRequest(c, kind)
+		if err != nil {
+			return err
+		}
+
+		unit := model.AcademicUnit{ID: id, Code: req.Code, Name: req.Name, ParentID: req.parentID}
+		if err := s.unitRepo.Update(c.UserContext(), kind, &unit); err != nil {
+			return unitWriteError(err)
+		}
+
+		return c.JSON(model.SuccessResponse[model.AcademicUnit]{
+			Success: true,
+			Message: i18n.T(c.UserContext(), "academic_unit.updated"),
+			Data:    unit,
+		})
+	}
+}
+
+// DELETE /api/v1/{faculties,departments,study-programs}/:id
+func (s *AcademicUnitService) Delete(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return apperror.BadRequest(apperror.CodeInvalidAcademicUnitID, "academic_unit.invalid_id")
+		}
+
+		if err := s.unitRepo.Delete(c.UserContext(), kind, id); err != nil {
+			return unitWriteError(err)
+		}
+
+		return c.JSON(model.SuccessMessageResponse{
+			Success: true,
+			Message: i18n.T(c.UserContext(), "academic_unit.deleted"),
+		})
+	}
+}
+
+// POST /api/v1/{faculties,departments,study-programs}/:id/merge
+func (s *AcademicUnitService) Merge(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sourceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return apperror.BadRequest(apperror.CodeInvalidAcademicUnitID, "academic_unit.invalid_id")
+		}
+
+		var req model.MergeAcademicUnitRequest
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+		}
+		if err := helper.ValidateStruct(req); err != nil {
+			return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+		}
+		targetID := uuid.MustParse(req.TargetID)
+		if targetID == sourceID {
+			return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+				{Field: "target_id", Rule: "invalid"},
+			})
+		}
+
+		if err := s.unitRepo.Merge(c.UserContext(), kind, sourceID, targetID); err != nil {
+			return apperror.Translate(err, errAcademicUnitNotFound)
+		}
+
+		return c.JSON(model.SuccessMessageResponse{
+			Success: true,
+			Message: i18n.T(c.UserContext(), "academic_unit.merged"),
+		})
+	}
+}
+
+type unitRequest struct {
+	model.AcademicUnitRequest
+	parentID *uuid.UUID
+}
+
+func (s *AcademicUnitService) parseUnitRequest(c *fiber.Ctx, kind string) (unitRequest, error) {
+	var req unitRequest
+	if err := c.BodyParser(&req.AcademicUnitRequest); err != nil {
+		return req, apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Code = strings.TrimSpace(req.Code)
+	req.Name = strings.Join(strings.Fields(req.Name), " ")
+	if err := helper.ValidateStruct(req.AcademicUnitRequest); err != nil {
+		return req, apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	parentKind, hasParent := parentUnitKind[kind]
+	if !hasParent {
+		return req, nil
+	}
+	if req.ParentID == "" {
+		return req, apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+			{Field: "parent_id", Rule: "required"},
+		})
+	}
+	parent, err := s.unitRepo.FindByID(c.UserContext(), parentKind, uuid.MustParse(req.ParentID))
+	if err != nil {
+		return req, apperror.Translate(err, apperror.Validation(apperror.CodeAcademicUnitNotFound, "academic_unit.not_found", []model.FieldError{
+			{Field: "parent_id", Rule: "exists"},
+		}))
+	}
+	req.parentID = &parent.ID
+	return req, nil
+}
+
+// unitWriteError memetakan pelanggaran constraint saat menyimpan atau menghapus unit.
+func unitWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return apperror.Conflict(apperror.CodeAcademicUnitExists, "academic_unit.exists").Wrap(err)
+		case "23503":
+			return apperror.Conflict(apperror.CodeAcademicUnitInUse, "academic_unit.in_use").Wrap(err)
+		}
+	}
+	return apperror.Translate(err, errAcademicUnitNotFound)
+}
+
+// academicUnitFilter membaca filter hierarki dari query faculty_id,
+// department_id, dan study_program_id.
+func academicUnitFilter(c *fiber.Ctx) (model.AcademicUnitFilter, error) {
+	var f model.AcademicUnitFilter
+	for param, dst := range map[string]**uuid.UUID{
+		"faculty_id":       &f.FacultyID,
+		"department_id":    &f.DepartmentID,
+		"study_program_id": &f.StudyProgramID,
+	} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return f, apperror.BadRequest(apperror.CodeInvalidAcademicUnitID, "academic_unit.invalid_id")
+		}
+		*dst = &id
+	}
+	return f, nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+	"fiber/skp/logging"
+	"fiber/skp/metrics"
+	"math"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var errAppealNotFound = apperror.NotFound(apperror.CodeAppealNotFound, "appeal.not_found")
+
+// POST /api/v1/achievements/:id/appeal
+func (s *AchievementService) Appeal(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	ownerID, err := s.repo.GetOwnerID(c.UserContext(), id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if ownerID != userID {
+		return apperror.Forbidden(apperror.CodeAchievementNotOwner, "appeal.forbidden")
+	}
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	currentStatus, err := s.repo.GetStatus(c.UserContext(), id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if currentStatus != string(model.StatusRejected) {
+		return apperror.Conflict(apperror.CodeAchievementNotRejected, "appeal.not_rejected")
+	}
+	if err := s.ensureNotFrozen(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	var req model.CreateAppealRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Statement = strings.TrimSpace(req.Statement)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	appeal, err := s.repo.CreateAppeal(c.UserContext(), id, userID, req.Statement)
+	if err != nil {
+		// Banding pending ganda yang lolos bersamaan ditolak oleh unique index.
+		var pqErr *pq.Error
+		if errors.Is(err, sql.ErrNoRows) || (errors.As(err, &pqErr) && pqErr.Code == "23505") {
+			return apperror.Conflict(apperror.CodeAppealNotAllowed, "appeal.not_allowed").Wrap(err)
+		}
+		return apperror.From(err)
+	}
+
+	logging.FromContext(c.UserContext()).Info("Banding achievement diajukan", "achievement_id", id, "appeal_id", appeal.ID)
+
+	return c.Status(fiber.StatusCreated).JSON(model.SuccessResponse[*model.Appeal]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "appeal.created"),
+		Data:    appeal,
+	})
+}
+
+// GET /api/v1/appeals
+func (s *AchievementService) ListAppeals(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	status := c.Query("status", model.AppealPending)
+	validStatuses := map[string]bool{"all": true, model.AppealPending: true, model.AppealUpheld: true, model.AppealOverturned: true}
+	if !validStatuses[status] {
+		status = model.AppealPending
+	}
+	filter := status
+	if status == "all" {
+		filter = ""
+	}
+
+	data, total, err := s.repo.FindAppeals(c.UserContext(), role, userID, filter, page, limit)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[model.PaginationData[model.Appeal]]{
+		Success: true,
+		Data: model.PaginationData[model.Appeal]{
+			Items: data,
+			Meta: model.MetaInfo{
+				Page:   page,
+				Limit:  limit,
+				Total:  total,
+				Pages:  int(math.Ceil(float64(total) / float64(limit))),
+				SortBy: "created_at",
+				Order:  "asc",
+			},
+		},
+	})
+}
+
+// POST /api/v1/appeals/:id/decide
+func (s *AchievementService) DecideAppeal(c *fiber.Ctx) error {
+	appealID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAppealID, "appeal.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	var req model.DecideAppealRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	appeal, err := s.repo.GetAppeal(c.UserContext(), appealID)
+	if err != nil {
+		return apperror.Translate(err, errAppealNotFound)
+	}
+	if appeal.Status != model.AppealPending {
+		return apperror.Conflict(apperror.CodeAppealDecided, "appeal.already_decided")
+	}
+	if err := s.ensureNotFrozen(c.UserContext(), appeal.AchievementID); err != nil {
+		return err
+	}
+
+	// Banding tidak ditinjau oleh dosen wali maupun dosen yang menolak.
+	if role != model.RoleAdmin {
+		allowed, err := s.repo.CanReviewAppeal(c.UserContext(), appealID, userID)
+		if err != nil {
+			return apperror.From(err)
+		}
+		if !allowed {
+			return apperror.Forbidden(apperror.CodeAppealReviewForbidden, "appeal.review_forbidden")
+		}
+	}
+
+	status, err := s.repo.DecideAppeal(c.UserContext(), appealID, userID, req.Decision, req.Note, req.Points)
+	if err != nil {
+		return apperror.Translate(err, apperror.Conflict(apperror.CodeAppealDecided, "appeal.already_decided"))
+	}
+
+	notificationType := model.NotificationAppealUpheld
+	message := i18n.T(c.UserContext(), "appeal.upheld")
+	if req.Decision == model.AppealDecisionOverturn {
+		notificationType = model.NotificationAppealOverturned
+		message = i18n.T(c.UserContext(), "appeal.overturned")
+		if status == string(model.StatusVerified) {
+			s.recordEvent(c.UserContext(), metrics.EventVerified, appeal.AchievementID)
+		} else if stage, err := s.currentStage(c.UserContext(), appeal.AchievementID); err == nil {
+			message = i18n.T(c.UserContext(), "appeal.overturned_next_stage", stage.Name)
+		}
+	}
+	s.notifyAppealDecided(c.UserContext(), appeal, notificationType, req.Note)
+
+	logging.FromContext(c.UserContext()).Info("Banding achievement diputus", "appeal_id", appealID, "decision", req.Decision, "status", status)
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: message,
+	})
+}
+
+// ensureNoPendingAppeal menolak perubahan achievement yang penolakannya
+// sedang dibanding.
+func (s *AchievementService) ensureNoPendingAppeal(ctx context.Context, id uuid.UUID) error {
+	pending, err := s.repo.HasPendingAppeal(ctx, id)
+	if err != nil {
+		return apperror.From(err)
+	}
+	if pending {
+		return apperror.Conflict(apperror.CodeAppealPending, "appeal.pending")
+	}
+	return nil
+}
+
+func (s *AchievementService) notifyAppealDecided(ctx context.Context, appeal *model.Appeal, notificationType, note string) {
+	log := logging.FromContext(ctx)
+
+	userIDs, err := s.repo.FindStudentUserIDs(ctx, appeal.AchievementID)
+	if err != nil {
+		log.Error("Gagal memuat mahasiswa untuk notifikasi banding", "appeal_id", appeal.ID, "error", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		n := &model.Notification{
+			UserID:        userID,
+			Type:          notificationType,
+			AchievementID: &appeal.AchievementID,
+			Params:        []string{appeal.Title, note},
+		}
+		if err := s.notificationRepo.Create(ctx, n); err != nil {
+			log.Error("Gagal membuat notifikasi banding", "appeal_id", appeal.ID, "error", err)
+		}
+	}
+}
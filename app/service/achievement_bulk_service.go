@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+	"fiber/skp/logging"
+	"fiber/skp/metrics"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// POST /api/v1/achievements/bulk/verify
+func (s *AchievementService) BulkVerify(c *fiber.Ctx) error {
+	var req model.BulkVerifyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	decisions := make([]model.BulkDecision, len(req.Items))
+	for i, item := range req.Items {
+		decisions[i] = model.BulkDecision{
+			ID:       uuid.MustParse(item.ID),
+			Decision: model.StageApproved,
+			Points:   item.Points,
+		}
+	}
+	return s.bulkDecide(c, decisions)
+}
+
+// POST /api/v1/achievements/bulk/reject
+func (s *AchievementService) BulkReject(c *fiber.Ctx) error {
+	var req model.BulkRejectRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	for i := range req.Items {
+		req.Items[i].RejectionNote = strings.TrimSpace(req.Items[i].RejectionNote)
+	}
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	decisions := make([]model.BulkDecision, len(req.Items))
+	for i, item := range req.Items {
+		decisions[i] = model.BulkDecision{
+			ID:       uuid.MustParse(item.ID),
+			Decision: model.StageRejected,
+			Note:     item.RejectionNote,
+		}
+	}
+	return s.bulkDecide(c, decisions)
+}
+
+// bulkDecide memeriksa kewenangan atas semua achievement sekaligus, lalu
+// menerapkan keputusan yang lolos dan melaporkan hasil per achievement.
+func (s *AchievementService) bulkDecide(c *fiber.Ctx, decisions []model.BulkDecision) error {
+	ctx := c.UserContext()
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	ids := make([]uuid.UUID, len(decisions))
+	for i, d := range decisions {
+		ids[i] = d.ID
+	}
+	targets, err := s.repo.FindVerificationTargets(ctx, userID, role, ids)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	results := make([]model.BulkItemResult, len(decisions))
+	var allowed []model.BulkDecision
+	var allowedIdx []int
+	for i, d := range decisions {
+		target, ok := targets[d.ID]
+		if err := checkBulkTarget(target, ok, d.Decision); err != nil {
+			results[i] = bulkFailure(ctx, d.ID, err)
+			continue
+		}
+		d.StagePosition = target.Stage.Position
+		d.StageName = target.Stage.Name
+		if target.Stage.Approver == model.ApproverAdvisor && target.AdvisorUserID != userID {
+			advisorID := target.AdvisorUserID
+			d.OnBehalfOf = &advisorID
+		}
+		allowed = append(allowed, d)
+		allowedIdx = append(allowedIdx, i)
+	}
+
+	if len(allowed) > 0 {
+		applied, err := s.repo.BulkDecide(ctx, userID, allowed)
+		if err != nil {
+			return apperror.From(err)
+		}
+		for j, res := range applied {
+			i := allowedIdx[j]
+			if res.Err != nil {
+				results[i] = bulkFailure(ctx, res.ID, apperror.Translate(res.Err, apperror.Conflict(apperror.CodeStageDecided, "verification.stage_decided")))
+				continue
+			}
+
+			result := model.BulkItemResult{ID: res.ID.String(), Success: true, Status: res.Status}
+			switch res.Status {
+			case string(model.StatusVerified):
+				result.Message = i18n.T(ctx, "achievement.verified")
+				s.recordEvent(ctx, metrics.EventVerified, res.ID)
+			case string(model.StatusRejected):
+				result.Message = i18n.T(ctx, "achievement.rejected")
+				s.recordEvent(ctx, metrics.EventRejected, res.ID)
+			default:
+				result.Message = i18n.T(ctx, "verification.stage_approved", allowed[j].StageName)
+			}
+			results[i] = result
+		}
+	}
+
+	succeeded := 0
+	for _, r := range results {
+		if r.Success {
+			succeeded++
+		}
+	}
+
+	return c.JSON(model.SuccessResponse[model.BulkResultResponse]{
+		Success: true,
+		Message: i18n.T(ctx, "achievement.bulk_processed", succeeded, len(results)),
+		Data: model.BulkResultResponse{
+			Succeeded: succeeded,
+			Failed:    len(results) - succeeded,
+			Results:   results,
+		},
+	})
+}
+
+// checkBulkTarget menerapkan pemeriksaan yang sama dengan verifikasi tunggal
+// terhadap hasil FindVerificationTargets.
+func checkBulkTarget(target model.VerificationTarget, found bool, decision string) *apperror.Error {
+	if !found {
+		return errAchievementNotFound
+	}
+	if target.TeamRole == model.TeamRoleMember {
+		return apperror.Forbidden(apperror.CodeTeamLeaderOnly, "team.leader_only")
+	}
+	if target.Status != string(model.StatusSubmitted) {
+		if decision == model.StageRejected {
+			return apperror.Conflict(apperror.CodeAchievementNotSubmitted, "achievement.reject_not_submitted")
+		}
+		return apperror.Conflict(apperror.CodeAchievementNotSubmitted, "achievement.verify_not_submitted")
+	}
+	if !target.Authorized {
+		if target.Stage.Approver == model.ApproverAdvisor {
+			return apperror.Forbidden(apperror.CodeNotStudentAdvisor, "advisor.not_advisor")
+		}
+		return apperror.Forbidden(apperror.CodeNotStageApprover, "verification.not_stage_approver", target.Stage.Name)
+	}
+	return nil
+}
+
+func bulkFailure(ctx context.Context, id uuid.UUID, err *apperror.Error) model.BulkItemResult {
+	if err.Kind == apperror.KindInternal {
+		logging.FromContext(ctx).Error("Gagal memproses achievement dalam verifikasi massal", "achievement_id", id, "error", err)
+	}
+	return model.BulkItemResult{
+		ID:      id.String(),
+		Code:    err.Code,
+		Message: i18n.T(ctx, err.Key, err.Args...),
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+	"fiber/skp/logging"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GET /api/v1/achievements/:id/comments
+func (s *AchievementService) ListComments(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	if err := s.ensureCanView(c.UserContext(), userID, role, id); err != nil {
+		return err
+	}
+
+	comments, err := s.repo.FindComments(c.UserContext(), id)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.AchievementComment]{
+		Success: true,
+		Data:    comments,
+	})
+}
+
+// POST /api/v1/achievements/:id/comments
+func (s *AchievementService) AddComment(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	if err := s.ensureCanView(c.UserContext(), userID, role, id); err != nil {
+		return err
+	}
+
+	var req model.CreateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	comment := model.AchievementComment{
+		AchievementID: id,
+		AuthorID:      userID,
+		AuthorRole:    role,
+		Body:          req.Body,
+	}
+	if req.ParentID != "" {
+		parentID := uuid.MustParse(req.ParentID)
+		comment.ParentID = &parentID
+	}
+
+	if err := s.repo.AddComment(c.UserContext(), &comment); err != nil {
+		return apperror.Translate(err, apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+			{Field: "parent_id", Rule: "exists"},
+		}))
+	}
+	s.notifyComment(c.UserContext(), id, userID, role)
+
+	return c.Status(fiber.StatusCreated).JSON(model.SuccessResponse[model.AchievementComment]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "comment.created"),
+		Data:    comment,
+	})
+}
+
+// POST /api/v1/achievements/:id/request-changes
+func (s *AchievementService) RequestChanges(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	var req model.RequestChangesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	items := make([]string, 0, len(req.Items))
+	for i := range req.Items {
+		req.Items[i].Description = strings.TrimSpace(req.Items[i].Description)
+		items = append(items, req.Items[i].Description)
+	}
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	currentStatus, err := s.repo.GetStatus(c.UserContext(), id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if currentStatus != string(model.StatusSubmitted) {
+		return apperror.Conflict(apperror.CodeAchievementNotSubmitted, "achievement.request_changes_not_submitted")
+	}
+
+	stage, err := s.currentStage(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureStageApprover(c.UserContext(), userID, role, id, stage); err != nil {
+		return err
+	}
+
+	onBehalfOf := s.delegatedFor(c.UserContext(), userID, id, stage)
+
+	created, err := s.repo.RequestChanges(c.UserContext(), id, userID, onBehalfOf, req.Note, items)
+	if err != nil {
+		return apperror.Translate(err, apperror.Conflict(apperror.CodeAchievementNotSubmitted, "achievement.request_changes_not_submitted"))
+	}
+	s.notifyChangesRequested(c.UserContext(), id, len(created))
+
+	return c.JSON(model.SuccessResponse[[]model.ChangeItem]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "achievement.changes_requested"),
+		Data:    created,
+	})
+}
+
+// ensureCanView memastikan user boleh melihat achievement: pemilik, dosen
+// yang terlibat dalam verifikasi, atau admin.
+func (s *AchievementService) ensureCanView(ctx context.Context, userID uuid.UUID, role string, id uuid.UUID) error {
+	if role == model.RoleMahasiswa {
+		ownerID, err := s.repo.GetOwnerID(ctx, id)
+		if err != nil {
+			return apperror.Translate(err, errAchievementNotFound)
+		}
+		if ownerID != userID {
+			return apperror.Forbidden(apperror.CodeAchievementNotOwner, "achievement.view_forbidden")
+		}
+	} else if role == model.RoleDosenWali {
+		allowed, err := s.canViewAsLecturer(ctx, userID, id)
+		if err != nil {
+			return apperror.Translate(err, errAchievementNotFound)
+		}
+		if !allowed {
+			return apperror.Forbidden(apperror.CodeNotStudentAdvisor, "achievement.view_not_advisee")
+		}
+	}
+	return nil
+}
+
+// changeResponses menyusun tanggapan mahasiswa per butir perbaikan.
+func changeResponses(addressed []model.AddressedChangeRequest) map[uuid.UUID]string {
+	responses := make(map[uuid.UUID]string, len(addressed))
+	for _, a := range addressed {
+		responses[uuid.MustParse(a.ItemID)] = strings.TrimSpace(a.Response)
+	}
+	return responses
+}
+
+func (s *AchievementService) notifyComment(ctx context.Context, id uuid.UUID, authorID uuid.UUID, role string) {
+	log := logging.FromContext(ctx)
+
+	data, err := s.repo.FindByAchievementID(ctx, id)
+	if err != nil {
+		log.Error("Gagal memuat achievement untuk notifikasi komentar", "achievement_id", id, "error", err)
+		return
+	}
+
+	// Komentar mahasiswa diteruskan ke dosen wali, komentar peninjau ke mahasiswa.
+	var recipients []uuid.UUID
+	if role == model.RoleMahasiswa {
+		advisorID, err := s.repo.GetAdvisorUserID(ctx, id)
+		if err != nil {
+			log.Error("Gagal memuat dosen wali untuk notifikasi komentar", "achievement_id", id, "error", err)
+			return
+		}
+		if advisorID != uuid.Nil {
+			recipients = append(recipients, advisorID)
+		}
+	} else {
+		recipients, err = s.repo.FindStudentUserIDs(ctx, id)
+		if err != nil {
+			log.Error("Gagal memuat mahasiswa untuk notifikasi komentar", "achievement_id", id, "error", err)
+			return
+		}
+	}
+
+	for _, userID := range recipients {
+		if userID == authorID {
+			continue
+		}
+		n := &model.Notification{
+			UserID:        userID,
+			Type:          model.NotificationNewComment,
+			AchievementID: &id,
+			Params:        []string{data.Title},
+		}
+		if err := s.notificationRepo.Create(ctx, n); err != nil {
+			log.Error("Gagal membuat notifikasi komentar", "achievement_id", id, "error", err)
+		}
+	}
+}
+
+func (s *AchievementService) notifyChangesRequested(ctx context.Context, id uuid.UUID, count int) {
+	log := logging.FromContext(ctx)
+
+	data, err := s.repo.FindByAchievementID(ctx, id)
+	if err != nil {
+		log.Error("Gagal memuat achievement untuk notifikasi perbaikan", "achievement_id", id, "error", err)
+		return
+	}
+	userIDs, err := s.repo.FindStudentUserIDs(ctx, id)
+	if err != nil {
+		log.Error("Gagal memuat mahasiswa untuk notifikasi perbaikan", "achievement_id", id, "error", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		n := &model.Notification{
+			UserID:        userID,
+			Type:          model.NotificationChangesRequested,
+			AchievementID: &id,
+			Params:        []string{data.Title, strconv.Itoa(count)},
+		}
+		if err := s.notificationRepo.Create(ctx, n); err != nil {
+			log.Error("Gagal membuat notifikasi perbaikan", "achievement_id", id, "error", err)
+		}
+	}
+}
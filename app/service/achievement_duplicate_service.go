@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+	"fiber/skp/helper"
+	"fiber/skp/logging"
+	"fiber/skp/metrics"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// checkDuplicates mencocokkan achievement yang baru disubmit dengan achievement
+// lain milik mahasiswa yang sama maupun seluruh institusi. Dugaan duplikat
+// dicatat untuk dosen wali tanpa menggagalkan submit.
+func (s *AchievementService) checkDuplicates(ctx context.Context, id uuid.UUID) {
+	log := logging.FromContext(ctx)
+
+	data, err := s.repo.FindByAchievementID(ctx, id)
+	if err != nil {
+		log.Error("Gagal memuat achievement untuk cek duplikat", "achievement_id", id, "error", err)
+		return
+	}
+
+	fingerprints := achievementFingerprints(data)
+	if err := s.repo.SaveFingerprints(ctx, id, fingerprints); err != nil {
+		log.Error("Gagal menyimpan sidik achievement", "achievement_id", id, "error", err)
+		return
+	}
+
+	suspects, err := s.repo.FindDuplicates(ctx, id, fingerprints)
+	if err != nil {
+		log.Error("Gagal mencari duplikat achievement", "achievement_id", id, "error", err)
+		return
+	}
+	if err := s.repo.SaveDuplicateFlags(ctx, id, suspects); err != nil {
+		log.Error("Gagal menyimpan dugaan duplikat", "achievement_id", id, "error", err)
+		return
+	}
+	if len(suspects) == 0 {
+		return
+	}
+
+	for _, d := range suspects {
+		for _, reason := range d.Reasons {
+			metrics.DuplicateSuspects.WithLabelValues(reason).Inc()
+		}
+	}
+	log.Info("Dugaan duplikat ditemukan", "achievement_id", id, "count", len(suspects))
+
+	advisorID, err := s.repo.GetAdvisorUserID(ctx, id)
+	if err != nil || advisorID == uuid.Nil {
+		return
+	}
+	n := &model.Notification{
+		UserID:        advisorID,
+		Type:          model.NotificationDuplicateSuspected,
+		AchievementID: &id,
+		Params:        []string{data.StudentName, data.Title, strconv.Itoa(len(suspects))},
+	}
+	if err := s.notificationRepo.Create(ctx, n); err != nil {
+		log.Error("Gagal membuat notifikasi dugaan duplikat", "achievement_id", id, "error", err)
+	}
+}
+
+// BackfillFingerprints menyimpan sidik semua achievement yang belum dihapus
+// agar achievement yang disubmit sebelum ada pengecekan duplikat ikut
+// dicocokkan. Achievement yang gagal diproses dicatat di log dan dilewati.
+func BackfillFingerprints(ctx context.Context, achievementRepo repo.AchievementRepository) (int, error) {
+	log := logging.FromContext(ctx)
+
+	ids, err := achievementRepo.FindFingerprintTargets(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	saved := 0
+	for _, id := range ids {
+		data, err := achievementRepo.FindByAchievementID(ctx, id)
+		if err != nil {
+			log.Error("Gagal memuat achievement untuk backfill sidik", "achievement_id", id, "error", err)
+			continue
+		}
+		if err := achievementRepo.SaveFingerprints(ctx, id, achievementFingerprints(data)); err != nil {
+			log.Error("Gagal menyimpan sidik achievement", "achievement_id", id, "error", err)
+			continue
+		}
+		saved++
+	}
+	return saved, nil
+}
+
+// duplicateSuspects memuat dugaan duplikat beserta tautan ke achievement yang cocok.
+func (s *AchievementService) duplicateSuspects(ctx context.Context, id uuid.UUID) ([]model.DuplicateSuspect, error) {
+	suspects, err := s.repo.FindDuplicateFlags(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range suspects {
+		suspects[i].URL = fmt.Sprintf("/api/v1/achievements/%s", suspects[i].AchievementID)
+	}
+	return suspects, nil
+}
+
+// achievementFingerprints menyusun sidik "alasan:nilai" dari data achievement.
+// Judul hanya dibandingkan dengan achievement milik mahasiswa yang sama karena
+// judul umum seperti "Juara 1" wajar muncul di banyak mahasiswa.
+func achievementFingerprints(data *model.AchievementResponse) []string {
+	var fingerprints []string
+	add := func(reason string, parts ...string) {
+		for _, part := range parts {
+			if part == "" {
+				return
+			}
+		}
+		fingerprints = append(fingerprints, reason+":"+strings.Join(parts, "|"))
+	}
+
+	title := helper.NormalizeText(data.Title)
+	add(model.DuplicateReasonTitle, data.StudentID.String(), title)
+
+	details := data.Details
+	add(model.DuplicateReasonCompetition, helper.NormalizeText(detailString(details, "competitionName")), detailDate(details, "eventDate"))
+	add(model.DuplicateReasonCertificationNumber, normalizeCode(detailString(details, "certificationNumber")))
+
+	publicationTitle := helper.NormalizeText(detailString(details, "publicationTitle"))
+	if publicationTitle == "" {
+		publicationTitle = title
+	}
+	add(model.DuplicateReasonISSNTitle, normalizeCode(detailString(details, "issn")), publicationTitle)
+
+	for _, attachment := range data.Attachments {
+		add(model.DuplicateReasonAttachment, attachment.Checksum)
+	}
+	return fingerprints
+}
+
+func detailString(details map[string]interface{}, key string) string {
+	value, _ := details[key].(string)
+	return value
+}
+
+func detailDate(details map[string]interface{}, key string) string {
+	switch v := details[key].(type) {
+	case time.Time:
+		return v.Format(helper.DateLayout)
+	case primitive.DateTime:
+		return v.Time().UTC().Format(helper.DateLayout)
+	case string:
+		return v
+	}
+	return ""
+}
+
+// normalizeCode menyamakan nomor seperti ISSN atau nomor sertifikat: hanya
+// huruf besar dan angka.
+func normalizeCode(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(helper.NormalizeText(s), " ", ""))
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"fiber/skp/app/model"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAchievementFingerprints(t *testing.T) {
+	studentID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	eventDate := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		data model.AchievementResponse
+		want []string
+	}{
+		{
+			name: "hanya judul",
+			data: model.AchievementResponse{StudentID: studentID, Title: "Juara 1, Lomba Debat!"},
+			want: []string{"title:" + studentID.String() + "|juara 1 lomba debat"},
+		},
+		{
+			name: "judul kosong tidak menghasilkan sidik",
+			data: model.AchievementResponse{StudentID: studentID, Title: "  ...  "},
+			want: nil,
+		},
+		{
+			name: "kompetisi dengan tanggal time.Time",
+			data: model.AchievementResponse{StudentID: studentID, Title: "Juara", Details: bson.M{
+				"competitionName": "Gemastik  XVII",
+				"eventDate":       eventDate,
+			}},
+			want: []string{
+				"title:" + studentID.String() + "|juara",
+				"competition:gemastik xvii|2025-03-14",
+			},
+		},
+		{
+			name: "kompetisi dengan tanggal dari MongoDB",
+			data: model.AchievementResponse{StudentID: studentID, Title: "Juara", Details: bson.M{
+				"competitionName": "Gemastik XVII",
+				"eventDate":       primitive.NewDateTimeFromTime(eventDate),
+			}},
+			want: []string{
+				"title:" + studentID.String() + "|juara",
+				"competition:gemastik xvii|2025-03-14",
+			},
+		},
+		{
+			name: "kompetisi tanpa tanggal diabaikan",
+			data: model.AchievementResponse{StudentID: studentID, Title: "Juara", Details: bson.M{
+				"competitionName": "Gemastik XVII",
+			}},
+			want: []string{"title:" + studentID.String() + "|juara"},
+		},
+		{
+			name: "nomor sertifikat dinormalisasi",
+			data: model.AchievementResponse{StudentID: studentID, Title: "Sertifikasi", Details: bson.M{
+				"certificationNumber": "bnsp-123/45 a",
+			}},
+			want: []string{
+				"title:" + studentID.String() + "|sertifikasi",
+				"certification_number:BNSP12345A",
+			},
+		},
+		{
+			name: "ISSN memakai judul achievement bila judul publikasi kosong",
+			data: model.AchievementResponse{StudentID: studentID, Title: "Analisis Data", Details: bson.M{
+				"issn": "1234-567x",
+			}},
+			want: []string{
+				"title:" + studentID.String() + "|analisis data",
+				"issn_title:1234567X|analisis data",
+			},
+		},
+		{
+			name: "ISSN dengan judul publikasi",
+			data: model.AchievementResponse{StudentID: studentID, Title: "Publikasi", Details: bson.M{
+				"issn":             "1234-567X",
+				"publicationTitle": "Deep Learning: Survei",
+			}},
+			want: []string{
+				"title:" + studentID.String() + "|publikasi",
+				"issn_title:1234567X|deep learning survei",
+			},
+		},
+		{
+			name: "lampiran tanpa checksum diabaikan",
+			data: model.AchievementResponse{StudentID: studentID, Title: "Juara", Attachments: []model.Attachment{
+				{FileName: "a.pdf", Checksum: "abc"},
+				{FileName: "b.pdf"},
+			}},
+			want: []string{
+				"title:" + studentID.String() + "|juara",
+				"attachment:abc",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := achievementFingerprints(&tt.data)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,21 @@
 package service
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fiber/skp/app/apperror"
 	"fiber/skp/app/model"
 	"fiber/skp/app/repo"
+	"fiber/skp/config"
 	"fiber/skp/helper"
+	"fiber/skp/i18n"
+	"fiber/skp/logging"
+	"fiber/skp/metrics"
 	"fmt"
+	"io"
 	"math"
+	"mime/multipart"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,17 +25,28 @@ import (
 	"github.com/google/uuid"
 )
 
+var errAchievementNotFound = apperror.NotFound(apperror.CodeAchievementNotFound, "achievement.not_found")
+
 type AchievementService struct {
 	repo         repo.AchievementRepository
 	studentRepo  repo.StudentRepository
 	lecturerRepo repo.LecturerRepository
+	typeRepo     repo.AchievementTypeRepository
+	chainRepo    repo.VerificationChainRepository
+	periodRepo   repo.AcademicPeriodRepository
+
+	notificationRepo repo.NotificationRepository
 }
 
-func NewAchievementService(repo repo.AchievementRepository, studentRepo repo.StudentRepository, lecturerRepo repo.LecturerRepository) *AchievementService {
+func NewAchievementService(repo repo.AchievementRepository, studentRepo repo.StudentRepository, lecturerRepo repo.LecturerRepository, typeRepo repo.AchievementTypeRepository, notificationRepo repo.NotificationRepository, chainRepo repo.VerificationChainRepository, periodRepo repo.AcademicPeriodRepository) *AchievementService {
 	return &AchievementService{
-		repo:         repo,
-		studentRepo:  studentRepo,
-		lecturerRepo: lecturerRepo,
+		repo:             repo,
+		studentRepo:      studentRepo,
+		lecturerRepo:     lecturerRepo,
+		typeRepo:         typeRepo,
+		chainRepo:        chainRepo,
+		periodRepo:       periodRepo,
+		notificationRepo: notificationRepo,
 	}
 }
 
@@ -45,12 +66,9 @@ func (s *AchievementService) List(c *fiber.Ctx) error {
 		sortBy = "created_at"
 	}
 
-	data, total, err := s.repo.FindAll(role, userID, page, limit, search, sortBy, order)
+	data, total, err := s.repo.FindAll(c.UserContext(), role, userID, page, limit, search, sortBy, order)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
@@ -76,46 +94,40 @@ func (s *AchievementService) List(c *fiber.Ctx) error {
 func (s *AchievementService) Get(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "achievement_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
 	}
 
-	data, err := s.repo.FindByAchievementID(id)
+	data, err := s.repo.FindByAchievementID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Achievement tidak ditemukan",
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 
 	userID := c.Locals("user_id").(uuid.UUID)
 	role := c.Locals("role").(string)
 
-	if role == model.RoleMahasiswa {
-		ownerID, err := s.repo.GetOwnerID(id)
-		if err != nil || ownerID != userID {
-			return c.Status(403).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Anda tidak berhak melihat achievement orang lain.",
-			})
+	if err := s.ensureCanView(c.UserContext(), userID, role, id); err != nil {
+		return err
+	}
+
+	if data.TeamRole != "" {
+		if err := s.teamWithPoints(c.UserContext(), data); err != nil {
+			return apperror.Translate(err, errAchievementNotFound)
 		}
-	} else if role == model.RoleDosenWali {
-		isAdvisor, err := s.repo.IsAdvisor(userID, id)
+	}
+	if role != model.RoleMahasiswa {
+		data.Duplicates, err = s.duplicateSuspects(c.UserContext(), id)
 		if err != nil {
-			return c.Status(500).JSON(model.ErrorResponse{
-				Success: false,
-				Message: err.Error(),
-			})
-		}
-		if !isAdvisor {
-			return c.Status(403).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Anda tidak berhak melihat achievement mahasiswa yang bukan bimbingan Anda.",
-			})
+			return apperror.From(err)
 		}
 	}
+	data.Comments, err = s.repo.FindComments(c.UserContext(), id)
+	if err != nil {
+		return apperror.From(err)
+	}
+	data.ChangeRequests, err = s.repo.FindChangeItems(c.UserContext(), id)
+	if err != nil {
+		return apperror.From(err)
+	}
 
 	return c.JSON(model.SuccessResponse[*model.AchievementResponse]{
 		Success: true,
@@ -128,37 +140,39 @@ func (s *AchievementService) Create(c *fiber.Ctx) error {
 
 	var req model.CreateAchievementRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Input tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
 	}
 
 	if err := helper.ValidateStruct(req); err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Validasi gagal",
-			Error:   helper.FormatValidationErrors(err),
-		})
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	details := req.Details
+	if details == nil {
+		details = legacyDetails(req.CompetitionDetails, req.PublicationDetails, req.OrganizationDetails, req.CertificationDetails)
 	}
+	details, err := validateDetails(c.UserContext(), s.typeRepo, req.AchievementType, details)
+	if err != nil {
+		return err
+	}
+	req.Details = details
 
 	userID := c.Locals("user_id").(uuid.UUID)
 
-	student, err := s.studentRepo.FindByUserID(userID)
+	student, err := s.studentRepo.FindByUserID(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(404).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Profil mahasiswa tidak ditemukan",
-		})
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentProfileNotFound, "student.profile_not_found"))
+	}
+	if err := ensureStudentActive(c.UserContext(), student); err != nil {
+		return err
 	}
 
-	res, err := s.repo.Create(student.ID, req)
+	res, err := s.repo.Create(c.UserContext(), student.ID, req)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
+	metrics.AchievementEvent(metrics.EventCreated, req.AchievementType)
+	s.addHistory(c.UserContext(), res.ID, model.ActionCreated, userID, "", nil)
 
 	return c.Status(201).JSON(model.SuccessResponse[*model.AchievementResponse]{
 		Success: true,
@@ -170,68 +184,84 @@ func (s *AchievementService) Create(c *fiber.Ctx) error {
 func (s *AchievementService) Update(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "achievement_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
 	}
 	userID := c.Locals("user_id").(uuid.UUID)
 
-	ownerID, err := s.repo.GetOwnerID(id)
+	ownerID, err := s.repo.GetOwnerID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	if ownerID != userID {
-		return c.Status(403).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Anda tidak berhak mengubah achievement yang bukan milik Anda",
-		})
+		return apperror.Forbidden(apperror.CodeAchievementNotOwner, "achievement.update_forbidden")
+	}
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
 	}
 
 	// Check status - can only update draft or rejected
-	currentStatus, err := s.repo.GetStatus(id)
+	currentStatus, err := s.repo.GetStatus(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false, Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	if currentStatus != "draft" && currentStatus != "rejected" {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Hanya achievement dengan status 'draft' atau 'rejected' yang dapat diubah",
-		})
+		return apperror.Conflict(apperror.CodeAchievementNotEditable, "achievement.not_editable")
+	}
+	if currentStatus == "rejected" {
+		if err := s.ensureNoPendingAppeal(c.UserContext(), id); err != nil {
+			return err
+		}
 	}
 
 	var req model.UpdateAchievementRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Input tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
 	}
 
-	if currentStatus == "rejected" {
-		if err := s.repo.UpdateStatus(id, "draft", nil, "", 0); err != nil {
-			return c.Status(500).JSON(model.ErrorResponse{
-				Success: false,
-				Message: err.Error(),
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	if req.Details == nil && (req.CompetitionDetails != nil || req.PublicationDetails != nil || req.OrganizationDetails != nil || req.CertificationDetails != nil) {
+		req.Details = legacyDetails(req.CompetitionDetails, req.PublicationDetails, req.OrganizationDetails, req.CertificationDetails)
+	}
+	if req.Details != nil || req.AchievementType != nil {
+		// Pergantian tipe wajib disertai details baru karena schema-nya berbeda.
+		if req.Details == nil {
+			return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+				{Field: "details", Rule: "required"},
 			})
 		}
+
+		achievementType := ""
+		if req.AchievementType != nil {
+			achievementType = *req.AchievementType
+		} else {
+			achievementType, err = s.repo.GetAchievementType(c.UserContext(), id)
+			if err != nil {
+				return apperror.Translate(err, errAchievementNotFound)
+			}
+		}
+
+		req.Details, err = validateDetails(c.UserContext(), s.typeRepo, achievementType, req.Details)
+		if err != nil {
+			return err
+		}
+	}
+
+	if currentStatus == "rejected" {
+		if err := s.repo.UpdateStatus(c.UserContext(), id, "draft", nil, "", 0); err != nil {
+			return apperror.Translate(err, errAchievementNotFound)
+		}
 	}
 
-	_, err = s.repo.Update(id, req)
+	_, err = s.repo.Update(c.UserContext(), id, req)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "achievement berhasil diubah",
+		Message: i18n.T(c.UserContext(), "achievement.updated"),
 	})
 }
 
@@ -239,49 +269,35 @@ func (s *AchievementService) Update(c *fiber.Ctx) error {
 func (s *AchievementService) Delete(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "achievement_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
 	}
 	userID := c.Locals("user_id").(uuid.UUID)
 
-	ownerID, err := s.repo.GetOwnerID(id)
+	ownerID, err := s.repo.GetOwnerID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	if ownerID != userID {
-		return c.Status(403).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Anda tidak berhak menghapus achievement yang bukan milik Anda",
-		})
+		return apperror.Forbidden(apperror.CodeAchievementNotOwner, "achievement.delete_forbidden")
+	}
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
 	}
 
-	currentStatus, err := s.repo.GetStatus(id)
+	currentStatus, err := s.repo.GetStatus(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false, Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	if currentStatus != "draft" {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Hanya achievement dengan status 'draft' yang dapat dihapus",
-		})
+		return apperror.Conflict(apperror.CodeAchievementNotDraft, "achievement.delete_not_draft")
 	}
 
-	if err := s.repo.Delete(id); err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+	if err := s.repo.Delete(c.UserContext(), id); err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "achievement berhasil dihapus",
+		Message: i18n.T(c.UserContext(), "achievement.deleted"),
 	})
 }
 
@@ -289,48 +305,74 @@ func (s *AchievementService) Delete(c *fiber.Ctx) error {
 func (s *AchievementService) Submit(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false, Message: "achievement_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
 	}
 	userID := c.Locals("user_id").(uuid.UUID)
 
-	ownerID, err := s.repo.GetOwnerID(id)
+	ownerID, err := s.repo.GetOwnerID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	if ownerID != userID {
-		return c.Status(403).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Anda tidak berhak mengajukan achievement yang bukan milik Anda",
-		})
+		return apperror.Forbidden(apperror.CodeAchievementNotOwner, "achievement.submit_forbidden")
+	}
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
+	}
+	student, err := s.studentRepo.FindByUserID(c.UserContext(), userID)
+	if err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentProfileNotFound, "student.profile_not_found"))
+	}
+	if err := ensureStudentActive(c.UserContext(), student); err != nil {
+		return err
+	}
+	// Anggota tim yang sudah lulus membuat achievement tim tidak bisa diajukan.
+	if err := s.ensureNotFrozen(c.UserContext(), id); err != nil {
+		return err
 	}
 
-	currentStatus, err := s.repo.GetStatus(id)
+	currentStatus, err := s.repo.GetStatus(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false, Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	if currentStatus != "draft" {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Hanya achievement dengan status 'draft' yang dapat disubmit",
-		})
+		return apperror.Conflict(apperror.CodeAchievementNotDraft, "achievement.submit_not_draft")
+	}
+	if err := s.ensureTeamReady(c.UserContext(), id); err != nil {
+		return err
+	}
+	if err := s.ensureSubmissionWindow(c.UserContext(), id); err != nil {
+		return err
 	}
 
-	if err := s.repo.UpdateStatus(id, "submitted", nil, "", 0); err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+	// Body opsional, berisi butir perbaikan yang dikerjakan saat submit ulang.
+	var req model.SubmitRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+		}
+		if err := helper.ValidateStruct(req); err != nil {
+			return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+		}
+	}
+	chainName, stages, err := s.verificationStages(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	open, err := s.repo.Submit(c.UserContext(), id, userID, changeResponses(req.Addressed), chainName, stages)
+	if err != nil {
+		return apperror.Translate(err, apperror.Conflict(apperror.CodeAchievementNotDraft, "achievement.submit_not_draft"))
+	}
+	if len(req.Addressed) > 0 {
+		logging.FromContext(c.UserContext()).Info("Butir perbaikan dikerjakan", "achievement_id", id, "addressed", len(req.Addressed), "open", open)
 	}
+	s.checkDuplicates(c.UserContext(), id)
+	s.recordEvent(c.UserContext(), metrics.EventSubmitted, id)
+
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "Achievement berhasil disubmit",
+		Message: i18n.T(c.UserContext(), "achievement.submitted"),
 	})
 }
 
@@ -339,65 +381,58 @@ func (s *AchievementService) Verify(c *fiber.Ctx) error {
 
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "achievement_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
 	}
 	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
 
-	isAdvisor, err := s.repo.IsAdvisor(userID, id)
-	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
 	}
 
-	if !isAdvisor {
-		return c.Status(403).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Anda bukan dosen wali dari mahasiswa ini",
-		})
+	currentStatus, err := s.repo.GetStatus(c.UserContext(), id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if currentStatus != "submitted" {
+		return apperror.Conflict(apperror.CodeAchievementNotSubmitted, "achievement.verify_not_submitted")
 	}
 
-	currentStatus, err := s.repo.GetStatus(id)
+	stage, err := s.currentStage(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false, Message: err.Error(),
-		})
+		return err
 	}
-	if currentStatus != "submitted" {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Achievement harus disubmit sebelum diverifikasi",
-		})
+	if err := s.ensureStageApprover(c.UserContext(), userID, role, id, stage); err != nil {
+		return err
 	}
+	onBehalfOf := s.delegatedFor(c.UserContext(), userID, id, stage)
 
 	var req model.VerifyRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Input tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
 	}
 
-	if req.Points <= 0 {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Points harus lebih dari 0",
-		})
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeInvalidPoints, "achievement.points_invalid", helper.FieldErrors(err))
 	}
 
-	if err := s.repo.UpdateStatus(id, "verified", &userID, "", req.Points); err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
+	// Poin hanya diberikan setelah tahap terakhir; tahap sebelumnya mencatat
+	// poin yang diusulkan.
+	status, err := s.repo.DecideVerification(c.UserContext(), id, stage, userID, onBehalfOf, model.StageApproved, "", req.Points)
+	if err != nil {
+		return apperror.Translate(err, apperror.Conflict(apperror.CodeStageDecided, "verification.stage_decided"))
+	}
+	if status != string(model.StatusVerified) {
+		return c.JSON(model.SuccessMessageResponse{
+			Success: true,
+			Message: i18n.T(c.UserContext(), "verification.stage_approved", stage.Name),
 		})
 	}
+	s.recordEvent(c.UserContext(), metrics.EventVerified, id)
+
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "Achievement berhasil diverifikasi",
+		Message: i18n.T(c.UserContext(), "achievement.verified"),
 	})
 }
 
@@ -406,65 +441,107 @@ func (s *AchievementService) Reject(c *fiber.Ctx) error {
 
 	var req model.RejectRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Input tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
 	}
 
-	if strings.TrimSpace(req.RejectionNote) == "" {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Catatan penolakan (rejection_note) wajib diisi",
-		})
+	req.RejectionNote = strings.TrimSpace(req.RejectionNote)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeRejectionNoteRequired, "achievement.rejection_note_required", helper.FieldErrors(err))
 	}
 
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "achievement_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
 	}
 	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
 
-	isAdvisor, err := s.repo.IsAdvisor(userID, id)
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	currentStatus, err := s.repo.GetStatus(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
-	if !isAdvisor {
-		return c.Status(403).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Anda bukan dosen wali dari mahasiswa ini",
-		})
+	if currentStatus != "submitted" {
+		return apperror.Conflict(apperror.CodeAchievementNotSubmitted, "achievement.reject_not_submitted")
 	}
 
-	currentStatus, err := s.repo.GetStatus(id)
+	stage, err := s.currentStage(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return err
 	}
-	if currentStatus != "submitted" {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Achievement harus disubmit sebelum ditolak",
-		})
+	if err := s.ensureStageApprover(c.UserContext(), userID, role, id, stage); err != nil {
+		return err
+	}
+	onBehalfOf := s.delegatedFor(c.UserContext(), userID, id, stage)
+	if _, err := s.repo.DecideVerification(c.UserContext(), id, stage, userID, onBehalfOf, model.StageRejected, req.RejectionNote, 0); err != nil {
+		return apperror.Translate(err, apperror.Conflict(apperror.CodeStageDecided, "verification.stage_decided"))
 	}
+	s.recordEvent(c.UserContext(), metrics.EventRejected, id)
 
-	if err := s.repo.UpdateStatus(id, "rejected", &userID, req.RejectionNote, 0); err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "achievement.rejected"),
+	})
+}
+
+// POST /api/v1/achievements/:id/revoke
+func (s *AchievementService) Revoke(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	var req model.RevokeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeRevocationReasonRequired, "achievement.revocation_reason_required", helper.FieldErrors(err))
+	}
+
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	currentStatus, err := s.repo.GetStatus(c.UserContext(), id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if currentStatus != string(model.StatusVerified) {
+		return apperror.Conflict(apperror.CodeAchievementNotVerified, "achievement.revoke_not_verified")
+	}
+	if err := s.ensureNotFrozen(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	if role != model.RoleAdmin {
+		verifierID, err := s.repo.GetVerifierID(c.UserContext(), id)
+		if err != nil {
+			return apperror.Translate(err, errAchievementNotFound)
+		}
+		if verifierID != userID {
+			return apperror.Forbidden(apperror.CodePermissionDenied, "achievement.revoke_forbidden")
+		}
+	}
+
+	points, err := s.repo.Revoke(c.UserContext(), id, userID, req.Reason)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
 	}
+	s.recordEvent(c.UserContext(), metrics.EventRevoked, id)
+	s.notifyRevoked(c.UserContext(), id, req.Reason)
+
+	logging.FromContext(c.UserContext()).Info("Achievement dicabut", "achievement_id", id, "points_reversed", points)
+
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "Achievement berhasil ditolak",
+		Message: i18n.T(c.UserContext(), "achievement.revoked"),
 	})
 }
 
@@ -472,39 +549,49 @@ func (s *AchievementService) Reject(c *fiber.Ctx) error {
 func (s *AchievementService) GetHistory(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "achievement_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
 	}
 
 	userID := c.Locals("user_id").(uuid.UUID)
 	role := c.Locals("role").(string)
 
 	if role == model.RoleMahasiswa {
-		ownerID, err := s.repo.GetOwnerID(id)
-		if err != nil || ownerID != userID {
-			return c.Status(403).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Anda tidak berhak melihat history achievement ini",
-			})
+		ownerID, err := s.repo.GetOwnerID(c.UserContext(), id)
+		if err != nil {
+			return apperror.Translate(err, errAchievementNotFound)
+		}
+		if ownerID != userID {
+			return apperror.Forbidden(apperror.CodeAchievementNotOwner, "achievement.history_forbidden")
 		}
 	} else if role == model.RoleDosenWali {
-		isAdvisor, err := s.repo.IsAdvisor(userID, id)
-		if err != nil || !isAdvisor {
-			return c.Status(403).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Anda bukan dosen wali dari mahasiswa ini",
-			})
+		allowed, err := s.canViewAsLecturer(c.UserContext(), userID, id)
+		if err != nil {
+			return apperror.Translate(err, errAchievementNotFound)
+		}
+		if !allowed {
+			return apperror.Forbidden(apperror.CodeNotStudentAdvisor, "advisor.not_advisor")
 		}
 	}
 
-	history, err := s.repo.GetHistory(id)
+	history, err := s.repo.GetHistory(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "achievement tidak ditemukan",
-		})
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	history.Stages, err = s.repo.FindStages(c.UserContext(), id)
+	if err != nil {
+		return apperror.From(err)
+	}
+	history.PreviousStages, err = s.repo.FindPreviousStages(c.UserContext(), id)
+	if err != nil {
+		return apperror.From(err)
+	}
+	history.Comments, err = s.repo.FindComments(c.UserContext(), id)
+	if err != nil {
+		return apperror.From(err)
+	}
+	history.ChangeRequests, err = s.repo.FindChangeItems(c.UserContext(), id)
+	if err != nil {
+		return apperror.From(err)
 	}
 
 	return c.JSON(model.SuccessResponse[*model.AchievementHistoryResponse]{
@@ -518,99 +605,224 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "achievement_id tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
 	}
 	userID := c.Locals("user_id").(uuid.UUID)
 
-	ownerID, err := s.repo.GetOwnerID(id)
+	ownerID, err := s.repo.GetOwnerID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	if ownerID != userID {
-		return c.Status(403).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Anda tidak berhak mengunggah attachment untuk achievement ini",
-		})
+		return apperror.Forbidden(apperror.CodeAchievementNotOwner, "achievement.upload_forbidden")
+	}
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
 	}
 
-	currentStatus, err := s.repo.GetStatus(id)
+	currentStatus, err := s.repo.GetStatus(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		return apperror.Translate(err, errAchievementNotFound)
 	}
 	if currentStatus != "draft" {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Hanya achievement dengan status 'draft' yang dapat mengunggah attachment",
-		})
+		return apperror.Conflict(apperror.CodeAchievementNotDraft, "achievement.upload_not_draft")
 	}
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "File wajib diisi",
-		})
+		return apperror.BadRequest(apperror.CodeAttachmentRequired, "attachment.required").Wrap(err)
 	}
 
 	const maxFileSize = 5 * 1024 * 1024
 	if file.Size > maxFileSize {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Ukuran file maksimal 5MB",
-		})
+		return apperror.BadRequest(apperror.CodeAttachmentTooLarge, "attachment.too_large")
 	}
 
 	ext := strings.ToLower(filepath.Ext(file.Filename))
 	if ext != ".pdf" {
-		return c.Status(400).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Hanya file PDF yang diizinkan",
-		})
+		return apperror.BadRequest(apperror.CodeAttachmentTypeNotAllowed, "attachment.type_not_allowed")
 	}
 
 	storedFilename := fmt.Sprintf("%s_%s", id.String(), file.Filename)
-	uploadDir := "./uploads"
+	uploadDir := config.GetUploadDir()
 	path := filepath.Join(uploadDir, storedFilename)
 
 	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal membuat direktori uploads",
-		})
+		return apperror.From(err)
 	}
 	if err := c.SaveFile(file, path); err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal menyimpan file",
-		})
+		return apperror.From(err)
+	}
+
+	metrics.UploadSize.Observe(float64(file.Size))
+
+	checksum, err := fileChecksum(file)
+	if err != nil {
+		return apperror.From(err)
 	}
 
 	attachment := model.Attachment{
 		FileName:   file.Filename,
 		FileURL:    "/uploads/" + storedFilename,
 		FileType:   filepath.Ext(file.Filename),
+		Checksum:   checksum,
 		UploadedAt: time.Now(),
 	}
 
-	if err := s.repo.AddAttachment(id, attachment); err != nil {
-		return c.Status(500).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal memperbarui database",
-		})
+	if err := s.repo.AddAttachment(c.UserContext(), id, attachment); err != nil {
+		return apperror.From(err)
 	}
 
 	return c.JSON(model.SuccessResponse[*model.Attachment]{
 		Success: true,
-		Message: "Attachment berhasil diunggah",
+		Message: i18n.T(c.UserContext(), "attachment.uploaded"),
 		Data:    &attachment,
 	})
 }
+
+// fileChecksum menghitung SHA-256 file upload untuk pengecekan duplikat.
+func fileChecksum(file *multipart.FileHeader) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// legacyDetails mengubah input details per tipe (competition_details dan
+// sejenisnya) menjadi bentuk details yang divalidasi oleh schema tipe.
+func legacyDetails(comp *model.CompetitionRequest, pub *model.PublicationRequest, org *model.OrganizationRequest, cert *model.CertificationRequest) map[string]interface{} {
+	details := map[string]interface{}{}
+	setString := func(key, value string) {
+		if value != "" {
+			details[key] = value
+		}
+	}
+
+	if comp != nil {
+		setString("competitionName", comp.CompetitionName)
+		setString("competitionLevel", comp.CompetitionLevel)
+		setString("medalType", comp.MedalType)
+		if comp.Rank > 0 {
+			details["rank"] = float64(comp.Rank)
+		}
+	}
+	if pub != nil {
+		setString("publicationTitle", pub.PublicationTitle)
+		setString("publisher", pub.Publisher)
+		setString("issn", pub.ISSN)
+		authors := make([]interface{}, 0, len(pub.Authors))
+		for _, author := range pub.Authors {
+			authors = append(authors, author)
+		}
+		details["authors"] = authors
+	}
+	if org != nil {
+		setString("organizationName", org.OrganizationName)
+		setString("position", org.Position)
+		details["period"] = map[string]interface{}{
+			"start": org.StartDate,
+			"end":   org.EndDate,
+		}
+	}
+	if cert != nil {
+		setString("certificationName", cert.CertificationName)
+		setString("issuedBy", cert.IssuedBy)
+		setString("certificationNumber", cert.CertificationNumber)
+		setString("validUntil", cert.ValidUntil)
+	}
+	return details
+}
+
+// addHistory mencatat aksi ke riwayat achievement. Kegagalan hanya dicatat di
+// log karena transisi status sudah tersimpan.
+func (s *AchievementService) addHistory(ctx context.Context, id uuid.UUID, action string, actorID uuid.UUID, note string, points *int) {
+	s.addDecisionHistory(ctx, id, action, actorID, nil, note, points)
+}
+
+// addDecisionHistory mencatat riwayat keputusan verifikasi; onBehalfOf diisi
+// bila aktor memutus sebagai penerima delegasi dosen wali.
+func (s *AchievementService) addDecisionHistory(ctx context.Context, id uuid.UUID, action string, actorID uuid.UUID, onBehalfOf *uuid.UUID, note string, points *int) {
+	err := s.repo.AddEvent(ctx, model.AchievementEvent{
+		AchievementID: id,
+		Action:        action,
+		ActorID:       &actorID,
+		OnBehalfOf:    onBehalfOf,
+		Note:          note,
+		Points:        points,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("Gagal mencatat riwayat achievement", "achievement_id", id, "action", action, "error", err)
+	}
+}
+
+func (s *AchievementService) notifyRevoked(ctx context.Context, id uuid.UUID, reason string) {
+	log := logging.FromContext(ctx)
+
+	data, err := s.repo.FindByAchievementID(ctx, id)
+	if err != nil {
+		log.Error("Gagal memuat achievement untuk notifikasi pencabutan", "achievement_id", id, "error", err)
+		return
+	}
+	userIDs, err := s.repo.FindStudentUserIDs(ctx, id)
+	if err != nil {
+		log.Error("Gagal memuat mahasiswa untuk notifikasi pencabutan", "achievement_id", id, "error", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		n := &model.Notification{
+			UserID:        userID,
+			Type:          model.NotificationAchievementRevoked,
+			AchievementID: &id,
+			Params:        []string{data.Title, reason},
+		}
+		if err := s.notificationRepo.Create(ctx, n); err != nil {
+			log.Error("Gagal membuat notifikasi pencabutan", "achievement_id", id, "error", err)
+		}
+	}
+}
+
+func (s *AchievementService) recordEvent(ctx context.Context, event string, id uuid.UUID) {
+	achievementType, err := s.repo.GetAchievementType(ctx, id)
+	if err != nil {
+		achievementType = ""
+	}
+	metrics.AchievementEvent(event, achievementType)
+}
+
+// ensureSubmissionWindow menolak submit di luar jendela pengajuan periode
+// achievement, kecuali mahasiswa punya pengecualian yang masih berlaku.
+func (s *AchievementService) ensureSubmissionWindow(ctx context.Context, id uuid.UUID) error {
+	gate, err := s.periodRepo.FindSubmissionGate(ctx, id, time.Now())
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if gate.Allowed() {
+		return nil
+	}
+	if gate.NextOpensAt != nil {
+		return apperror.Conflict(apperror.CodeSubmissionWindowClosed, "submission_window.closed_until",
+			gate.PeriodLabel, gate.NextOpensAt.Format("2006-01-02 15:04"))
+	}
+	return apperror.Conflict(apperror.CodeSubmissionWindowClosed, "submission_window.closed", gate.PeriodLabel)
+}
+
+// ensureNotFrozen menolak perubahan atas achievement milik mahasiswa yang
+// rekam SKP-nya sudah dibekukan.
+func (s *AchievementService) ensureNotFrozen(ctx context.Context, id uuid.UUID) error {
+	frozen, err := s.repo.HasFrozenStudent(ctx, id)
+	if err != nil {
+		return apperror.From(err)
+	}
+	if frozen {
+		return apperror.Conflict(apperror.CodeSKPFrozen, "student.skp_frozen")
+	}
+	return nil
+}
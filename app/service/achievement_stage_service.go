@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/config"
+	"fiber/skp/logging"
+
+	"github.com/google/uuid"
+)
+
+// verificationStages memilih tahap verifikasi dari rantai yang cocok dengan
+// tipe dan tingkat achievement, atau satu tahap dosen wali bila tidak ada.
+func (s *AchievementService) verificationStages(ctx context.Context, id uuid.UUID) (string, []model.VerificationStage, error) {
+	data, err := s.repo.FindByAchievementID(ctx, id)
+	if err != nil {
+		return "", nil, apperror.Translate(err, errAchievementNotFound)
+	}
+
+	chain, err := s.chainRepo.Match(ctx, data.AchievementType, data.Details)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", []model.VerificationStage{
+			{Name: model.DefaultStageName, Approver: model.ApproverAdvisor, SLAHours: int(config.GetVerificationSLA().Hours())},
+		}, nil
+	}
+	if err != nil {
+		return "", nil, apperror.From(err)
+	}
+	return chain.Name, chain.Stages, nil
+}
+
+// currentStage mengembalikan tahap yang menunggu keputusan. Achievement yang
+// disubmit sebelum ada rantai verifikasi diperlakukan sebagai satu tahap
+// dosen wali dengan Position 0.
+func (s *AchievementService) currentStage(ctx context.Context, id uuid.UUID) (*model.AchievementStage, error) {
+	stage, err := s.repo.GetCurrentStage(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &model.AchievementStage{Name: model.DefaultStageName, Approver: model.ApproverAdvisor}, nil
+	}
+	if err != nil {
+		return nil, apperror.From(err)
+	}
+	return stage, nil
+}
+
+// ensureStageApprover memastikan user adalah pihak yang berwenang memutus
+// tahap verifikasi saat ini.
+func (s *AchievementService) ensureStageApprover(ctx context.Context, userID uuid.UUID, role string, id uuid.UUID, stage *model.AchievementStage) error {
+	allowed := false
+	switch stage.Approver {
+	case model.ApproverAdvisor:
+		isAdvisor, err := s.repo.IsAdvisor(ctx, userID, id)
+		if err != nil {
+			return apperror.Translate(err, errAchievementNotFound)
+		}
+		if !isAdvisor {
+			return apperror.Forbidden(apperror.CodeNotStudentAdvisor, "advisor.not_advisor")
+		}
+		return nil
+	case model.ApproverDepartmentHead:
+		if role == model.RoleDosenWali {
+			isHead, err := s.repo.IsDepartmentHead(ctx, userID, id)
+			if err != nil {
+				return apperror.Translate(err, errAchievementNotFound)
+			}
+			allowed = isHead
+		}
+	case model.ApproverAdmin:
+		allowed = role == model.RoleAdmin
+	}
+
+	if !allowed {
+		return apperror.Forbidden(apperror.CodeNotStageApprover, "verification.not_stage_approver", stage.Name)
+	}
+	return nil
+}
+
+// delegatedFor mengembalikan user_id dosen wali yang diwakili bila user memutus
+// tahap dosen wali sebagai penerima delegasi, atau nil.
+func (s *AchievementService) delegatedFor(ctx context.Context, userID uuid.UUID, id uuid.UUID, stage *model.AchievementStage) *uuid.UUID {
+	if stage.Approver != model.ApproverAdvisor {
+		return nil
+	}
+	advisorID, err := s.repo.GetAdvisorUserID(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("Gagal memuat dosen wali untuk riwayat delegasi", "achievement_id", id, "error", err)
+		return nil
+	}
+	if advisorID == uuid.Nil || advisorID == userID {
+		return nil
+	}
+	return &advisorID
+}
+
+// canViewAsLecturer mengizinkan dosen wali mahasiswa dan kepala jurusan yang
+// menjadi tahap verifikasi untuk melihat achievement.
+func (s *AchievementService) canViewAsLecturer(ctx context.Context, userID uuid.UUID, id uuid.UUID) (bool, error) {
+	isAdvisor, err := s.repo.IsAdvisor(ctx, userID, id)
+	if err != nil || isAdvisor {
+		return isAdvisor, err
+	}
+	return s.repo.IsStageApprover(ctx, userID, id)
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+	"fiber/skp/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// PUT /api/v1/achievements/:id/team
+func (s *AchievementService) SetTeam(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	if err := s.ensureEditableByOwner(c.UserContext(), id, userID); err != nil {
+		return err
+	}
+	if err := s.ensureNotTeamMember(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	var req model.SetTeamRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	nims := make([]string, len(req.Members))
+	for i, m := range req.Members {
+		nims[i] = strings.TrimSpace(m.StudentID)
+	}
+	students, err := s.studentRepo.FindByStudentIDs(c.UserContext(), nims)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	var fields []model.FieldError
+	seen := make(map[string]bool, len(nims))
+	leaders, totalShare := 0, 0
+	members := make([]model.TeamMember, 0, len(req.Members))
+
+	for i, m := range req.Members {
+		field := fmt.Sprintf("members[%d].student_id", i)
+		nim := nims[i]
+		totalShare += m.PointsShare
+
+		if seen[nim] {
+			fields = append(fields, model.FieldError{Field: field, Rule: "unique"})
+			continue
+		}
+		seen[nim] = true
+
+		student, ok := students[nim]
+		if !ok {
+			fields = append(fields, model.FieldError{Field: field, Rule: "exists"})
+			continue
+		}
+		if m.Role == model.TeamRoleLeader {
+			leaders++
+			if student.UserID != userID {
+				fields = append(fields, model.FieldError{Field: field, Rule: "leader_self"})
+			}
+		}
+
+		members = append(members, model.TeamMember{
+			StudentID:   student.ID,
+			NIM:         student.StudentID,
+			StudentName: student.User.FullName,
+			Role:        m.Role,
+			PointsShare: m.PointsShare,
+		})
+	}
+	if leaders != 1 {
+		fields = append(fields, model.FieldError{Field: "members", Rule: "one_leader"})
+	}
+	if totalShare != 100 {
+		fields = append(fields, model.FieldError{Field: "members", Rule: "points_sum", Param: "100"})
+	}
+	if len(fields) > 0 {
+		return apperror.Validation(apperror.CodeTeamInvalid, "team.invalid", fields)
+	}
+
+	added, err := s.repo.SetTeam(c.UserContext(), id, members)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	s.notifyTeamInvitation(c.UserContext(), id, added, students)
+
+	team, err := s.repo.FindTeam(c.UserContext(), id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.TeamMember]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "team.updated"),
+		Data:    team,
+	})
+}
+
+// POST /api/v1/achievements/:id/team/confirm
+func (s *AchievementService) ConfirmParticipation(c *fiber.Ctx) error {
+	id, err := s.memberAchievementID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.ConfirmParticipation(c.UserContext(), id); err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "team.confirmed"),
+	})
+}
+
+// POST /api/v1/achievements/:id/team/decline
+func (s *AchievementService) DeclineParticipation(c *fiber.Ctx) error {
+	id, err := s.memberAchievementID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeclineParticipation(c.UserContext(), id); err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "team.declined"),
+	})
+}
+
+// memberAchievementID memastikan :id adalah baris anggota tim milik pengguna
+// yang masih berstatus draft.
+func (s *AchievementService) memberAchievementID(c *fiber.Ctx) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, apperror.BadRequest(apperror.CodeInvalidAchievementID, "achievement.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	if err := s.ensureEditableByOwner(c.UserContext(), id, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	role, err := s.repo.GetTeamRole(c.UserContext(), id)
+	if err != nil {
+		return uuid.Nil, apperror.Translate(err, errAchievementNotFound)
+	}
+	if role != model.TeamRoleMember {
+		return uuid.Nil, apperror.Conflict(apperror.CodeTeamMemberOnly, "team.member_only")
+	}
+	return id, nil
+}
+
+func (s *AchievementService) ensureEditableByOwner(ctx context.Context, id, userID uuid.UUID) error {
+	ownerID, err := s.repo.GetOwnerID(ctx, id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if ownerID != userID {
+		return apperror.Forbidden(apperror.CodeAchievementNotOwner, "achievement.update_forbidden")
+	}
+
+	status, err := s.repo.GetStatus(ctx, id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if status != string(model.StatusDraft) {
+		return apperror.Conflict(apperror.CodeAchievementNotDraft, "team.not_draft")
+	}
+	return nil
+}
+
+// ensureNotTeamMember menolak aksi yang hanya boleh dilakukan lewat baris
+// ketua tim, mis. mengubah dokumen bersama atau memverifikasi.
+func (s *AchievementService) ensureNotTeamMember(ctx context.Context, id uuid.UUID) error {
+	role, err := s.repo.GetTeamRole(ctx, id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if role == model.TeamRoleMember {
+		return apperror.Forbidden(apperror.CodeTeamLeaderOnly, "team.leader_only")
+	}
+	return nil
+}
+
+// ensureTeamReady memastikan seluruh anggota tim sudah konfirmasi dan
+// pembagian poinnya genap 100% sebelum submit.
+func (s *AchievementService) ensureTeamReady(ctx context.Context, id uuid.UUID) error {
+	team, err := s.repo.FindTeam(ctx, id)
+	if err != nil {
+		return apperror.Translate(err, errAchievementNotFound)
+	}
+	if len(team) == 0 {
+		return nil
+	}
+
+	totalShare := 0
+	var pending []string
+	for _, m := range team {
+		totalShare += m.PointsShare
+		if !m.Confirmed {
+			pending = append(pending, m.StudentName)
+		}
+	}
+	if totalShare != 100 {
+		return apperror.Conflict(apperror.CodeTeamInvalid, "team.shares_invalid", strconv.Itoa(totalShare))
+	}
+	if len(pending) > 0 {
+		return apperror.Conflict(apperror.CodeTeamNotConfirmed, "team.not_confirmed", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// teamWithPoints melengkapi data tim beserta poin tiap anggota.
+func (s *AchievementService) teamWithPoints(ctx context.Context, data *model.AchievementResponse) error {
+	team, err := s.repo.FindTeam(ctx, data.ID)
+	if err != nil {
+		return err
+	}
+
+	// Pembagian mengikuti urutan student_id seperti memberPoints, bukan urutan
+	// tampilan, agar sisa pembulatan jatuh ke anggota yang sama.
+	if data.Points > 0 {
+		order := make([]int, len(team))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortFunc(order, func(a, b int) int {
+			return bytes.Compare(team[a].StudentID[:], team[b].StudentID[:])
+		})
+		shares := make([]int, len(order))
+		for k, i := range order {
+			shares[k] = team[i].PointsShare
+		}
+		for k, points := range helper.SplitPoints(data.Points, shares) {
+			team[order[k]].Points = points
+		}
+	}
+	data.Team = team
+	return nil
+}
+
+func (s *AchievementService) notifyTeamInvitation(ctx context.Context, id uuid.UUID, added []model.TeamMember, students map[string]model.Student) {
+	if len(added) == 0 {
+		return
+	}
+
+	data, err := s.repo.FindByAchievementID(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("Gagal memuat achievement untuk notifikasi tim", "achievement_id", id, "error", err)
+		return
+	}
+
+	for _, m := range added {
+		achievementID := m.AchievementID
+		n := &model.Notification{
+			UserID:        students[m.NIM].UserID,
+			Type:          model.NotificationTeamInvitation,
+			AchievementID: &achievementID,
+			Params:        []string{data.StudentName, data.Title},
+		}
+		if err := s.notificationRepo.Create(ctx, n); err != nil {
+			logging.FromContext(ctx).Error("Gagal membuat notifikasi undangan tim", "achievement_id", achievementID, "error", err)
+		}
+	}
+}
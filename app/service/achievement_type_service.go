@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+var errAchievementTypeNotFound = apperror.NotFound(apperror.CodeAchievementTypeNotFound, "achievement_type.not_found")
+
+type AchievementTypeService struct {
+	typeRepo repo.AchievementTypeRepository
+}
+
+func NewAchievementTypeService(typeRepo repo.AchievementTypeRepository) *AchievementTypeService {
+	return &AchievementTypeService{typeRepo: typeRepo}
+}
+
+// GET /api/v1/achievement-types
+func (s *AchievementTypeService) List(c *fiber.Ctx) error {
+	includeInactive := c.QueryBool("all", false) && c.Locals("role") == model.RoleAdmin
+
+	types, err := s.typeRepo.FindAll(c.UserContext(), includeInactive)
+	if err != nil {
+		return apperror.From(err)
+	}
+	if types == nil {
+		types = []model.AchievementType{}
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.AchievementType]{
+		Success: true,
+		Data:    types,
+	})
+}
+
+// GET /api/v1/achievement-types/:code/schema
+func (s *AchievementTypeService) GetSchema(c *fiber.Ctx) error {
+	t, err := s.typeRepo.FindByCode(c.UserContext(), c.Params("code"))
+	if err != nil {
+		return apperror.Translate(err, errAchievementTypeNotFound)
+	}
+
+	return c.JSON(model.SuccessResponse[model.AchievementTypeSchemaResponse]{
+		Success: true,
+		Data: model.AchievementTypeSchemaResponse{
+			Code:   t.Code,
+			Name:   t.Name,
+			Schema: t.DetailsSchema,
+		},
+	})
+}
+
+// POST /api/v1/achievement-types
+func (s *AchievementTypeService) Create(c *fiber.Ctx) error {
+	var req model.CreateAchievementTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	if _, err := helper.CompileSchema(req.DetailsSchema); err != nil {
+		return invalidSchema(err)
+	}
+
+	t := model.AchievementType{
+		Code:          req.Code,
+		Name:          req.Name,
+		Description:   req.Description,
+		DetailsSchema: req.DetailsSchema,
+	}
+	if err := s.typeRepo.Create(c.UserContext(), &t); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return apperror.Conflict(apperror.CodeAchievementTypeExists, "achievement_type.exists", req.Code).Wrap(err)
+		}
+		return apperror.From(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.SuccessResponse[model.AchievementType]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "achievement_type.created"),
+		Data:    t,
+	})
+}
+
+// PUT /api/v1/achievement-types/:code
+func (s *AchievementTypeService) Update(c *fiber.Ctx) error {
+	var req model.UpdateAchievementTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	t, err := s.typeRepo.FindByCode(c.UserContext(), c.Params("code"))
+	if err != nil {
+		return apperror.Translate(err, errAchievementTypeNotFound)
+	}
+
+	if req.Name != nil {
+		t.Name = *req.Name
+	}
+	if req.Description != nil {
+		t.Description = *req.Description
+	}
+	if len(req.DetailsSchema) > 0 {
+		if _, err := helper.CompileSchema(req.DetailsSchema); err != nil {
+			return invalidSchema(err)
+		}
+		t.DetailsSchema = req.DetailsSchema
+	}
+	if req.IsActive != nil {
+		t.IsActive = *req.IsActive
+	}
+
+	if err := s.typeRepo.Update(c.UserContext(), t); err != nil {
+		return apperror.Translate(err, errAchievementTypeNotFound)
+	}
+
+	return c.JSON(model.SuccessResponse[*model.AchievementType]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "achievement_type.updated"),
+		Data:    t,
+	})
+}
+
+// DELETE /api/v1/achievement-types/:code
+func (s *AchievementTypeService) Delete(c *fiber.Ctx) error {
+	if err := s.typeRepo.Deactivate(c.UserContext(), c.Params("code")); err != nil {
+		return apperror.Translate(err, errAchievementTypeNotFound)
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "achievement_type.deactivated"),
+	})
+}
+
+func invalidSchema(err error) error {
+	return apperror.Validation(apperror.CodeInvalidDetailsSchema, "achievement_type.invalid_schema", []model.FieldError{
+		{Field: "details_schema", Rule: "schema", Param: err.Error()},
+	}).Wrap(err)
+}
+
+// validateDetails memeriksa details terhadap schema tipe achievement yang aktif
+// dan mengembalikan details yang siap disimpan ke MongoDB.
+func validateDetails(ctx context.Context, typeRepo repo.AchievementTypeRepository, code string, details map[string]interface{}) (map[string]interface{}, error) {
+	t, err := typeRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, apperror.Translate(err, apperror.Validation(apperror.CodeAchievementTypeNotFound, "achievement_type.not_found", []model.FieldError{
+			{Field: "achievement_type", Rule: "exists"},
+		}))
+	}
+	if !t.IsActive {
+		return nil, apperror.Validation(apperror.CodeAchievementTypeInactive, "achievement_type.inactive", []model.FieldError{
+			{Field: "achievement_type", Rule: "active"},
+		})
+	}
+
+	schema, err := helper.CompileSchema(t.DetailsSchema)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	if err := schema.Validate(details); err != nil {
+		fields := helper.SchemaFieldErrors("details", err)
+		if fields == nil {
+			return nil, apperror.Internal(err)
+		}
+		return nil, apperror.Validation(apperror.CodeInvalidDetails, "achievement.invalid_details", fields).Wrap(err)
+	}
+
+	// Schema tidak bisa membandingkan dua field, jadi urutan period diperiksa
+	// di sini untuk semua tipe yang memakainya.
+	if period, ok := details["period"].(map[string]interface{}); ok {
+		start, _ := period["start"].(string)
+		end, _ := period["end"].(string)
+		startDate, startErr := time.Parse(helper.DateLayout, start)
+		endDate, endErr := time.Parse(helper.DateLayout, end)
+		if startErr == nil && endErr == nil && endDate.Before(startDate) {
+			return nil, apperror.Validation(apperror.CodeInvalidDetails, "achievement.invalid_details", []model.FieldError{
+				{Field: "details.period.end", Rule: "date_after", Param: "details.period.start"},
+			})
+		}
+	}
+
+	return helper.CoerceValues(schema, details).(map[string]interface{}), nil
+}
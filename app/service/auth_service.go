@@ -1,14 +1,17 @@
 package service
 
 import (
-	"log"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 
+	"fiber/skp/app/apperror"
 	"fiber/skp/app/model"
 	"fiber/skp/app/repo"
 	"fiber/skp/helper"
+	"fiber/skp/i18n"
+	"fiber/skp/logging"
+	"fiber/skp/metrics"
 )
 
 type AuthService struct {
@@ -23,32 +26,25 @@ func NewAuthService(repo repo.UserRepository) *AuthService {
 func (s *AuthService) Login(c *fiber.Ctx) error {
 	var req model.LoginRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Input tidak valid",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
 	}
 
-	if req.Username == "" || req.Password == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Username dan Password harus diisi",
-		})
+	if err := helper.ValidateStruct(req); err != nil {
+		metrics.LoginFailures.WithLabelValues("missing_credentials").Inc()
+		return apperror.Validation(apperror.CodeCredentialsRequired, "auth.credentials_required", helper.FieldErrors(err))
 	}
 
-	user, err := s.repo.FindByUsername(req.Username)
+	user, err := s.repo.FindByUsername(c.UserContext(), req.Username)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Kredensial tidak valid",
-		})
+		metrics.LoginFailures.WithLabelValues("unknown_user").Inc()
+		logging.FromContext(c.UserContext()).Warn("Login gagal", "username", req.Username, "reason", "unknown_user")
+		return apperror.Translate(err, apperror.Unauthorized(apperror.CodeInvalidCredentials, "auth.invalid_credentials"))
 	}
 
 	if !helper.CheckPasswordHash(req.Password, user.PasswordHash) {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Kredensial tidak valid",
-		})
+		metrics.LoginFailures.WithLabelValues("invalid_password").Inc()
+		logging.FromContext(c.UserContext()).Warn("Login gagal", "username", req.Username, "reason", "invalid_password")
+		return apperror.Unauthorized(apperror.CodeInvalidCredentials, "auth.invalid_credentials")
 	}
 
 	var permissions []string
@@ -58,31 +54,22 @@ func (s *AuthService) Login(c *fiber.Ctx) error {
 
 	token, err := helper.GenerateToken(*user, permissions)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal menghasilkan token",
-		})
+		return apperror.Internal(err)
 	}
 
 	refreshToken, err := helper.GenerateRefreshToken(*user)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal menghasilkan token refresh",
-		})
+		return apperror.Internal(err)
 	}
 
 	user.RefreshToken = refreshToken
-	if err := s.repo.Update(user); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal menyimpan token refresh",
-		})
+	if err := s.repo.Update(c.UserContext(), user); err != nil {
+		return apperror.From(err)
 	}
 
 	return c.JSON(model.LoginSuccessResponse{
 		Success: true,
-		Message: "Login berhasil",
+		Message: i18n.T(c.UserContext(), "auth.login_success"),
 		Data: model.LoginResponse{
 			User: model.LoginUser{
 				ID:          user.ID.String(),
@@ -102,40 +89,29 @@ func (s *AuthService) Refresh(c *fiber.Ctx) error {
 	var req model.RefreshTokenRequest
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Token refresh diperlukan",
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "auth.refresh_token_required").Wrap(err)
+	}
+
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "auth.refresh_token_required", helper.FieldErrors(err))
 	}
 
 	claims, err := helper.ValidateToken(req.RefreshToken)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Token refresh tidak valid",
-		})
+		return apperror.Unauthorized(apperror.CodeRefreshTokenInvalid, "auth.refresh_token_invalid")
 	}
 
 	if claims.Type != "refresh" {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Token refresh tidak valid",
-		})
+		return apperror.Unauthorized(apperror.CodeRefreshTokenInvalid, "auth.refresh_token_invalid")
 	}
 
-	user, err := s.repo.FindByUserID(claims.UserID)
+	user, err := s.repo.FindByUserID(c.UserContext(), claims.UserID)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "User tidak ditemukan",
-		})
+		return apperror.Translate(err, apperror.Unauthorized(apperror.CodeRefreshTokenInvalid, "user.not_found"))
 	}
 
 	if user.RefreshToken != req.RefreshToken {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Token refresh tidak valid",
-		})
+		return apperror.Unauthorized(apperror.CodeRefreshTokenInvalid, "auth.refresh_token_invalid")
 	}
 
 	var permissions []string
@@ -145,15 +121,12 @@ func (s *AuthService) Refresh(c *fiber.Ctx) error {
 
 	newToken, err := helper.GenerateToken(*user, permissions)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal menghasilkan token",
-		})
+		return apperror.Internal(err)
 	}
 
 	return c.JSON(model.SuccessResponse[model.RefreshTokenResponse]{
 		Success: true,
-		Message: "Token refresh berhasil",
+		Message: i18n.T(c.UserContext(), "auth.refresh_success"),
 		Data: model.RefreshTokenResponse{
 			Token: newToken,
 		},
@@ -164,27 +137,18 @@ func (s *AuthService) Refresh(c *fiber.Ctx) error {
 func (s *AuthService) Logout(c *fiber.Ctx) error {
 	bearer := strings.TrimSpace(c.Get("Authorization"))
 	if bearer == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Token diperlukan",
-		})
+		return apperror.Unauthorized(apperror.CodeTokenMissing, "auth.token_required")
 	}
 
 	if len(bearer) < 7 || !strings.HasPrefix(strings.ToLower(bearer), "bearer ") {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Format token tidak valid",
-		})
+		return apperror.Unauthorized(apperror.CodeTokenInvalidFormat, "auth.token_invalid_format")
 	}
 
 	tokenString := strings.TrimSpace(bearer[7:])
 
 	claims, err := helper.ValidateToken(tokenString)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Token tidak valid",
-		})
+		return apperror.Unauthorized(apperror.CodeTokenInvalid, "auth.token_invalid").Wrap(err)
 	}
 
 	blacklistedToken := model.BlacklistedToken{
@@ -192,11 +156,8 @@ func (s *AuthService) Logout(c *fiber.Ctx) error {
 		ExpiresAt: claims.ExpiresAt.Time,
 	}
 
-	if err := s.repo.AddBlacklistToken(blacklistedToken); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal logout",
-		})
+	if err := s.repo.AddBlacklistToken(c.UserContext(), blacklistedToken); err != nil {
+		return apperror.From(err)
 	}
 
 	var req model.RefreshTokenRequest
@@ -208,18 +169,20 @@ func (s *AuthService) Logout(c *fiber.Ctx) error {
 				Token:     req.RefreshToken,
 				ExpiresAt: refreshClaims.ExpiresAt.Time,
 			}
-			s.repo.AddBlacklistToken(blacklistedRefreshToken)
+			s.repo.AddBlacklistToken(c.UserContext(), blacklistedRefreshToken)
 		}
 	}
 
-	if err := s.repo.ClearRefreshToken(claims.UserID); err != nil {
-		log.Printf("Gagal menghapus refresh token untuk user %s: %v", claims.UserID, err)
-
+	if err := s.repo.ClearRefreshToken(c.UserContext(), claims.UserID); err != nil {
+		logging.FromContext(c.UserContext()).Error("Gagal menghapus refresh token",
+			"user_id", claims.UserID,
+			"error", err,
+		)
 	}
 
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "Logout berhasil",
+		Message: i18n.T(c.UserContext(), "auth.logout_success"),
 	})
 }
 
@@ -232,10 +195,7 @@ func (s *AuthService) Profile(c *fiber.Ctx) error {
 	case interface{ String() string }:
 		userID = v.String()
 	default:
-		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "User tidak ditemukan",
-		})
+		return apperror.Unauthorized(apperror.CodeTokenClaimsIncomplete, "user.not_found")
 	}
 
 	username, _ := c.Locals("username").(string)
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+	"fiber/skp/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var (
+	errDelegationNotFound  = apperror.NotFound(apperror.CodeDelegationNotFound, "delegation.not_found")
+	errDelegationForbidden = apperror.Forbidden(apperror.CodePermissionDenied, "delegation.forbidden")
+)
+
+type DelegationService struct {
+	delegationRepo   repo.DelegationRepository
+	lecturerRepo     repo.LecturerRepository
+	notificationRepo repo.NotificationRepository
+}
+
+func NewDelegationService(delegationRepo repo.DelegationRepository, lecturerRepo repo.LecturerRepository, notificationRepo repo.NotificationRepository) *DelegationService {
+	return &DelegationService{
+		delegationRepo:   delegationRepo,
+		lecturerRepo:     lecturerRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// GET /api/v1/delegations
+func (s *DelegationService) List(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	// Dosen hanya melihat delegasi yang ia berikan atau terima.
+	var lecturerID *uuid.UUID
+	if role != model.RoleAdmin {
+		lecturer, err := s.lecturerRepo.FindByUserID(c.UserContext(), userID)
+		if err != nil {
+			return apperror.Translate(err, errDelegationForbidden)
+		}
+		lecturerID = &lecturer.ID
+	}
+
+	delegations, err := s.delegationRepo.FindAll(c.UserContext(), lecturerID, c.QueryBool("active", false))
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.AdvisorDelegation]{
+		Success: true,
+		Data:    delegations,
+	})
+}
+
+// POST /api/v1/delegations
+func (s *DelegationService) Create(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	var req model.CreateDelegationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	// Admin memilih dosen wali yang didelegasikan, dosen hanya dirinya sendiri.
+	var advisorID uuid.UUID
+	if role == model.RoleAdmin {
+		if req.AdvisorID == "" {
+			return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+				{Field: "advisor_id", Rule: "required"},
+			})
+		}
+		advisorID = uuid.MustParse(req.AdvisorID)
+	} else {
+		self, err := s.lecturerRepo.FindByUserID(c.UserContext(), userID)
+		if err != nil {
+			return apperror.Translate(err, errDelegationForbidden)
+		}
+		if req.AdvisorID != "" && uuid.MustParse(req.AdvisorID) != self.ID {
+			return errDelegationForbidden
+		}
+		advisorID = self.ID
+	}
+	delegateID := uuid.MustParse(req.DelegateID)
+
+	if delegateID == advisorID {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+			{Field: "delegate_id", Rule: "invalid"},
+		})
+	}
+	if today := time.Now().Format(helper.DateLayout); req.EndsAt < today {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", []model.FieldError{
+			{Field: "ends_at", Rule: "date_after", Param: today},
+		})
+	}
+
+	advisor, err := s.lecturerRepo.FindByID(c.UserContext(), advisorID)
+	if err != nil {
+		return apperror.Translate(err, apperror.Validation(apperror.CodeLecturerNotFound, "lecturer.not_found", []model.FieldError{
+			{Field: "advisor_id", Rule: "exists"},
+		}))
+	}
+	delegate, err := s.lecturerRepo.FindByID(c.UserContext(), delegateID)
+	if err != nil {
+		return apperror.Translate(err, apperror.Validation(apperror.CodeLecturerNotFound, "lecturer.not_found", []model.FieldError{
+			{Field: "delegate_id", Rule: "exists"},
+		}))
+	}
+
+	overlap, err := s.delegationRepo.HasOverlap(c.UserContext(), advisorID, req.StartsAt, req.EndsAt)
+	if err != nil {
+		return apperror.From(err)
+	}
+	if overlap {
+		return apperror.Conflict(apperror.CodeDelegationOverlap, "delegation.overlap")
+	}
+
+	delegation := model.AdvisorDelegation{
+		AdvisorID:  advisorID,
+		DelegateID: delegateID,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		Reason:     req.Reason,
+	}
+	if err := s.delegationRepo.Create(c.UserContext(), &delegation, userID); err != nil {
+		return apperror.From(err)
+	}
+
+	n := &model.Notification{
+		UserID: delegate.UserID,
+		Type:   model.NotificationAdvisorDelegation,
+		Params: []string{advisor.User.FullName, delegation.StartsAt, delegation.EndsAt},
+	}
+	if err := s.notificationRepo.Create(c.UserContext(), n); err != nil {
+		logging.FromContext(c.UserContext()).Error("Gagal membuat notifikasi delegasi", "delegation_id", delegation.ID, "error", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.SuccessResponse[model.AdvisorDelegation]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "delegation.created"),
+		Data:    delegation,
+	})
+}
+
+// DELETE /api/v1/delegations/:id
+func (s *DelegationService) Revoke(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidDelegationID, "delegation.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	delegation, err := s.delegationRepo.FindByID(c.UserContext(), id)
+	if err != nil {
+		return apperror.Translate(err, errDelegationNotFound)
+	}
+	if role != model.RoleAdmin {
+		self, err := s.lecturerRepo.FindByUserID(c.UserContext(), userID)
+		if err != nil {
+			return apperror.Translate(err, errDelegationForbidden)
+		}
+		if delegation.AdvisorID != self.ID {
+			return errDelegationForbidden
+		}
+	}
+
+	if err := s.delegationRepo.Revoke(c.UserContext(), id); err != nil {
+		return apperror.Translate(err, apperror.Conflict(apperror.CodeDelegationRevoked, "delegation.already_revoked"))
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "delegation.revoked"),
+	})
+}
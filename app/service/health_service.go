@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fiber/skp/app/model"
+	"fiber/skp/config"
+	"fiber/skp/db"
+	"fiber/skp/logging"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	healthOK   = "ok"
+	healthFail = "fail"
+)
+
+type HealthService struct {
+	pgDB      *sql.DB
+	mongoDB   *mongo.Database
+	startedAt time.Time
+}
+
+func NewHealthService(pgDB *sql.DB, mongoDB *mongo.Database) *HealthService {
+	return &HealthService{
+		pgDB:      pgDB,
+		mongoDB:   mongoDB,
+		startedAt: time.Now(),
+	}
+}
+
+// GET /healthz
+func (s *HealthService) Liveness(c *fiber.Ctx) error {
+	return c.JSON(model.HealthResponse{Status: healthOK})
+}
+
+// GET /readyz
+func (s *HealthService) Readiness(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	checks := map[string]string{
+		"postgres":   s.check(ctx, "postgres", s.pingPostgres),
+		"mongo":      s.check(ctx, "mongo", s.pingMongo),
+		"migrations": s.check(ctx, "migrations", s.checkMigrations),
+		"storage":    s.check(ctx, "storage", s.checkStorage),
+	}
+
+	status := healthOK
+	for _, check := range checks {
+		if check != healthOK {
+			status = healthFail
+			break
+		}
+	}
+
+	code := fiber.StatusOK
+	if status != healthOK {
+		code = fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(code).JSON(model.HealthResponse{
+		Status: status,
+		Checks: checks,
+	})
+}
+
+// GET /status
+func (s *HealthService) Status(c *fiber.Ctx) error {
+	stats := s.pgDB.Stats()
+
+	return c.JSON(model.SuccessResponse[model.StatusResponse]{
+		Success: true,
+		Data: model.StatusResponse{
+			Version:   config.Version,
+			StartedAt: s.startedAt,
+			Uptime:    time.Since(s.startedAt).Round(time.Second).String(),
+			Postgres: model.PostgresPoolStats{
+				MaxOpenConnections: stats.MaxOpenConnections,
+				OpenConnections:    stats.OpenConnections,
+				InUse:              stats.InUse,
+				Idle:               stats.Idle,
+				WaitCount:          stats.WaitCount,
+				WaitDuration:       stats.WaitDuration.String(),
+				MaxIdleClosed:      stats.MaxIdleClosed,
+				MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+			},
+			Mongo: s.mongoTopology(c.UserContext()),
+		},
+	})
+}
+
+// check menjalankan fn dengan batas waktu dan mengembalikan ok/fail; error
+// hanya dicatat di log.
+func (s *HealthService) check(ctx context.Context, name string, fn func(ctx context.Context) error) string {
+	ctx, cancel := context.WithTimeout(ctx, config.GetHealthCheckTimeout())
+	defer cancel()
+
+	start := time.Now()
+	if err := fn(ctx); err != nil {
+		logging.FromContext(ctx).Error("Pemeriksaan kesiapan gagal", "dependency", name, "latency", time.Since(start).String(), "error", err)
+		return healthFail
+	}
+	return healthOK
+}
+
+func (s *HealthService) pingPostgres(ctx context.Context) error {
+	return s.pgDB.PingContext(ctx)
+}
+
+func (s *HealthService) pingMongo(ctx context.Context) error {
+	return s.mongoDB.Client().Ping(ctx, nil)
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) error {
+	pending, err := db.PendingMigrations(ctx, s.pgDB)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("migrasi belum diterapkan: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+func (s *HealthService) checkStorage(ctx context.Context) error {
+	dir := config.GetUploadDir()
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".readyz-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+func (s *HealthService) mongoTopology(ctx context.Context) model.MongoTopology {
+	ctx, cancel := context.WithTimeout(ctx, config.GetHealthCheckTimeout())
+	defer cancel()
+
+	client := s.mongoDB.Client()
+	var topology model.MongoTopology
+
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&topology)
+	if err != nil {
+		topology.Error = err.Error()
+	}
+	topology.SessionsInProgress = client.NumberSessionsInProgress()
+
+	return topology
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+	"fiber/skp/i18n"
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type NotificationService struct {
+	notificationRepo repo.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repo.NotificationRepository) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+// GET /api/v1/notifications
+func (s *NotificationService) List(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	unreadOnly := c.QueryBool("unread", false)
+
+	data, total, err := s.notificationRepo.FindByUser(c.UserContext(), userID, unreadOnly, page, limit)
+	if err != nil {
+		return apperror.From(err)
+	}
+	if data == nil {
+		data = []model.Notification{}
+	}
+	for i := range data {
+		data[i].Message = renderNotification(c.UserContext(), data[i])
+	}
+
+	return c.JSON(model.SuccessResponse[model.PaginationData[model.Notification]]{
+		Success: true,
+		Data: model.PaginationData[model.Notification]{
+			Items: data,
+			Meta: model.MetaInfo{
+				Page:   page,
+				Limit:  limit,
+				Total:  total,
+				Pages:  int(math.Ceil(float64(total) / float64(limit))),
+				SortBy: "created_at",
+				Order:  "desc",
+			},
+		},
+	})
+}
+
+// PATCH /api/v1/notifications/:id/read
+func (s *NotificationService) MarkRead(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidNotificationID, "notification.invalid_id")
+	}
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	if err := s.notificationRepo.MarkRead(c.UserContext(), id, userID); err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeNotificationNotFound, "notification.not_found"))
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "notification.marked_read"),
+	})
+}
+
+// PATCH /api/v1/notifications/read-all
+func (s *NotificationService) MarkAllRead(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	if err := s.notificationRepo.MarkAllRead(c.UserContext(), userID); err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "notification.marked_read"),
+	})
+}
+
+func renderNotification(ctx context.Context, n model.Notification) string {
+	args := make([]any, len(n.Params))
+	for i, p := range n.Params {
+		args[i] = p
+	}
+	return i18n.T(ctx, "notification."+n.Type, args...)
+}
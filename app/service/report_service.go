@@ -0,0 +1,130 @@
+package service
+
+import (
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ReportService struct {
+	reportRepo  repo.ReportRepository
+	studentRepo repo.StudentRepository
+}
+
+func NewReportService(reportRepo repo.ReportRepository, studentRepo repo.StudentRepository) *ReportService {
+	return &ReportService{
+		reportRepo:  reportRepo,
+		studentRepo: studentRepo,
+	}
+}
+
+// GET /api/v1/reports/statistics
+func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	filter, err := reportFilter(c)
+	if err != nil {
+		return err
+	}
+
+	stats, err := s.reportRepo.GetStatistics(c.UserContext(), role, userID, filter)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[*model.StatsResponse]{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// GET /api/v1/reports/advisors
+func (s *ReportService) GetAdvisorAttribution(c *fiber.Ctx) error {
+	unit, err := academicUnitFilter(c)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.reportRepo.GetAdvisorAttribution(c.UserContext(), unit)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.AdvisorAttribution]{
+		Success: true,
+		Data:    items,
+	})
+}
+
+// GET /api/v1/reports/student/:id
+func (s *ReportService) GetStudentStats(c *fiber.Ctx) error {
+	studentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidStudentID, "student.invalid_id")
+	}
+
+	student, err := s.studentRepo.FindByID(c.UserContext(), studentID)
+	if err != nil {
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeStudentNotFound, "student.not_found"))
+	}
+
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	if role == model.RoleMahasiswa && student.UserID != userID {
+		return apperror.Forbidden(apperror.CodePermissionDenied, "report.not_own")
+	} else if role == model.RoleDosenWali {
+		isAdvisor, err := s.studentRepo.IsAdvisedBy(c.UserContext(), studentID, userID)
+		if err != nil {
+			return apperror.From(err)
+		}
+		if !isAdvisor {
+			return apperror.Forbidden(apperror.CodeNotStudentAdvisor, "advisor.not_advisor")
+		}
+	}
+
+	filter, err := reportFilter(c)
+	if err != nil {
+		return err
+	}
+
+	stats, err := s.reportRepo.GetStudentStats(c.UserContext(), studentID, filter)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	totalPoints := 0
+	for _, ts := range stats.TopStudents {
+		totalPoints += ts.TotalPoints
+	}
+
+	return c.JSON(model.SuccessResponse[model.StudentStatsResponse]{
+		Success: true,
+		Data: model.StudentStatsResponse{
+			StudentProfile: model.TopStudent{
+				StudentID:         student.StudentID,
+				StudentName:       student.User.FullName,
+				Program:           student.ProgramStudy,
+				TotalAchievements: int(stats.TotalAchievements),
+				TotalPoints:       totalPoints,
+			},
+			Stats: *stats,
+		},
+	})
+}
+
+// reportFilter membaca filter laporan dari query string.
+func reportFilter(c *fiber.Ctx) (model.ReportFilter, error) {
+	unit, err := academicUnitFilter(c)
+	if err != nil {
+		return model.ReportFilter{}, err
+	}
+	return model.ReportFilter{
+		ExcludeExpired: c.QueryBool("exclude_expired", false),
+		Unit:           unit,
+	}, nil
+}
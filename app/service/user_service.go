@@ -1,10 +1,14 @@
 package service
 
 import (
+	"context"
+	"fiber/skp/app/apperror"
 	"fiber/skp/app/model"
 	"fiber/skp/app/repo"
 	"fiber/skp/helper"
+	"fiber/skp/i18n"
 	"math"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -14,13 +18,15 @@ type UserService struct {
 	userRepo     repo.UserRepository
 	studentRepo  repo.StudentRepository
 	lecturerRepo repo.LecturerRepository
+	unitRepo     repo.AcademicUnitRepository
 }
 
-func NewUserService(userRepo repo.UserRepository, studentRepo repo.StudentRepository, lecturerRepo repo.LecturerRepository) *UserService {
+func NewUserService(userRepo repo.UserRepository, studentRepo repo.StudentRepository, lecturerRepo repo.LecturerRepository, unitRepo repo.AcademicUnitRepository) *UserService {
 	return &UserService{
 		userRepo:     userRepo,
 		studentRepo:  studentRepo,
 		lecturerRepo: lecturerRepo,
+		unitRepo:     unitRepo,
 	}
 }
 
@@ -37,13 +43,9 @@ func (s *UserService) GetAllUsers(c *fiber.Ctx) error {
 		sortBy = "created_at"
 	}
 
-	users, total, err := s.userRepo.FindAll(page, limit, search, sortBy, order)
+	users, total, err := s.userRepo.FindAll(c.UserContext(), page, limit, search, sortBy, order)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal memuat data user",
-			Error:   err.Error(),
-		})
+		return apperror.From(err)
 	}
 
 	var userResponses []model.UserResponse
@@ -81,20 +83,12 @@ func (s *UserService) GetUser(c *fiber.Ctx) error {
 	id := c.Params("id")
 	userUUID, err := uuid.Parse(id)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "user_id tidak valid",
-			Error:   err.Error(),
-		})
+		return apperror.BadRequest(apperror.CodeInvalidUserID, "user.invalid_id")
 	}
 
-	user, err := s.userRepo.FindByUserIDSimple(userUUID)
+	user, err := s.userRepo.FindByUserIDSimple(c.UserContext(), userUUID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "User tidak ditemukan",
-			Error:   err.Error(),
-		})
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeUserNotFound, "user.not_found"))
 	}
 
 	return c.JSON(model.SuccessResponse[model.UserResponse]{
@@ -113,85 +107,58 @@ func (s *UserService) GetUser(c *fiber.Ctx) error {
 func (s *UserService) CreateUser(c *fiber.Ctx) error {
 	var req model.CreateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Input tidak valid",
-			Error:   err.Error(),
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
 	}
 
 	if err := helper.ValidateStruct(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Validasi gagal",
-			Error:   helper.FormatValidationErrors(err),
-		})
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
 	}
 
+	var unit *model.AcademicUnit
 	if req.Role == model.RoleMahasiswa {
 		if req.Student == nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Data khusus mahasiswa diperlukan untuk role mahasiswa",
-			})
+			return apperror.BadRequest(apperror.CodeStudentDataRequired, "user.student_data_required")
 		}
 		if err := helper.ValidateStruct(*req.Student); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Validasi data mahasiswa gagal",
-				Error:   helper.FormatValidationErrors(err),
-			})
+			return apperror.Validation(apperror.CodeValidationFailed, "validation.student_failed", helper.FieldErrors(err))
 		}
+		resolved, err := s.resolveUnit(c.UserContext(), model.UnitStudyProgram, req.Student.StudyProgramID, req.Student.ProgramStudy)
+		if err != nil {
+			return err
+		}
+		unit = resolved
 	} else if req.Role == model.RoleDosenWali {
 		if req.Lecturer == nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Data khusus dosen wali diperlukan untuk role dosen_wali",
-			})
+			return apperror.BadRequest(apperror.CodeLecturerDataRequired, "user.lecturer_data_required")
 		}
 		if err := helper.ValidateStruct(*req.Lecturer); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Validasi data dosen wali gagal",
-				Error:   helper.FormatValidationErrors(err),
-			})
+			return apperror.Validation(apperror.CodeValidationFailed, "validation.lecturer_failed", helper.FieldErrors(err))
 		}
+		resolved, err := s.resolveUnit(c.UserContext(), model.UnitDepartment, req.Lecturer.DepartmentID, req.Lecturer.Department)
+		if err != nil {
+			return err
+		}
+		unit = resolved
 	}
 
-	roleData, err := s.userRepo.FindRoleByName(req.Role)
+	roleData, err := s.userRepo.FindRoleByName(c.UserContext(), req.Role)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Role tidak valid: " + req.Role,
-			Error:   err.Error(),
-		})
+		return apperror.Translate(err, apperror.BadRequest(apperror.CodeRoleInvalid, "user.role_invalid", req.Role))
 	}
 
 	if roleData.Name == model.RoleMahasiswa {
-		if exists, _ := s.studentRepo.ExistsByStudentID(req.Username); exists {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Username (student_id) sudah terdaftar",
-				Error:   "Data duplicated",
-			})
+		if exists, _ := s.studentRepo.ExistsByStudentID(c.UserContext(), req.Username); exists {
+			return apperror.Conflict(apperror.CodeUsernameTaken, "user.student_id_taken")
 		}
 	} else if roleData.Name == model.RoleDosenWali {
-		if exists, _ := s.lecturerRepo.ExistsByLecturerID(req.Username); exists {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Username (lecturer_id) sudah terdaftar",
-				Error:   "Data duplicated",
-			})
+		if exists, _ := s.lecturerRepo.ExistsByLecturerID(c.UserContext(), req.Username); exists {
+			return apperror.Conflict(apperror.CodeUsernameTaken, "user.lecturer_id_taken")
 		}
 	}
 
 	hashedPwd, err := helper.HashPassword(req.Password)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal menghash password",
-			Error:   err.Error(),
-		})
+		return apperror.Internal(err)
 	}
 
 	newUser := model.User{
@@ -202,48 +169,38 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 		RoleID:       &roleData.ID,
 	}
 
-	if err := s.userRepo.Create(&newUser); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal membuat user",
-			Error:   err.Error(),
-		})
+	if err := s.userRepo.Create(c.UserContext(), &newUser); err != nil {
+		return apperror.From(err)
 	}
 
 	if roleData.Name == model.RoleMahasiswa {
 		student := model.Student{
-			UserID:       newUser.ID,
-			StudentID:    req.Username,
-			ProgramStudy: req.Student.ProgramStudy,
-			AcademicYear: req.Student.AcademicYear,
+			UserID:         newUser.ID,
+			StudentID:      req.Username,
+			ProgramStudy:   unit.Name,
+			StudyProgramID: &unit.ID,
+			AcademicYear:   req.Student.AcademicYear,
 		}
-		if err := s.studentRepo.Create(&student); err != nil {
-			_ = s.userRepo.Delete(newUser.ID)
-			return c.Status(500).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "User berhasil dibuat tetapi gagal membuat profile mahasiswa. Rolled back.",
-				Error:   err.Error(),
-			})
+		if err := s.studentRepo.Create(c.UserContext(), &student); err != nil {
+			_ = s.userRepo.Delete(c.UserContext(), newUser.ID)
+			return apperror.From(err)
 		}
 	} else if roleData.Name == model.RoleDosenWali {
 		lecturer := model.Lecturer{
-			UserID:     newUser.ID,
-			LecturerID: req.Username,
-			Department: req.Lecturer.Department,
+			UserID:       newUser.ID,
+			LecturerID:   req.Username,
+			Department:   unit.Name,
+			DepartmentID: &unit.ID,
 		}
-		if err := s.lecturerRepo.Create(&lecturer); err != nil {
-			_ = s.userRepo.Delete(newUser.ID)
-			return c.Status(500).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "User berhasil dibuat tetapi gagal membuat profile dosen wali. Rolled back.",
-				Error:   err.Error(),
-			})
+		if err := s.lecturerRepo.Create(c.UserContext(), &lecturer); err != nil {
+			_ = s.userRepo.Delete(c.UserContext(), newUser.ID)
+			return apperror.From(err)
 		}
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "User berhasil dibuat",
+		Message: i18n.T(c.UserContext(), "user.created"),
 	})
 }
 
@@ -252,37 +209,21 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 	id := c.Params("id")
 	userUUID, err := uuid.Parse(id)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "user_id tidak valid",
-			Error:   err.Error(),
-		})
+		return apperror.BadRequest(apperror.CodeInvalidUserID, "user.invalid_id")
 	}
 
 	var req model.UpdateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Input tidak valid",
-			Error:   err.Error(),
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
 	}
 
 	if err := helper.ValidateStruct(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Validasi gagal",
-			Error:   helper.FormatValidationErrors(err),
-		})
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
 	}
 
-	user, err := s.userRepo.FindByUserID(userUUID)
+	user, err := s.userRepo.FindByUserID(c.UserContext(), userUUID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "User tidak ditemukan",
-			Error:   err.Error(),
-		})
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeUserNotFound, "user.not_found"))
 	}
 
 	if req.Username != "" {
@@ -297,26 +238,18 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 	if req.Password != "" {
 		hashedPwd, err := helper.HashPassword(req.Password)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Gagal menghash password",
-				Error:   err.Error(),
-			})
+			return apperror.Internal(err)
 		}
 		user.PasswordHash = hashedPwd
 	}
 
-	if err := s.userRepo.Update(user); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal mengupdate user",
-			Error:   err.Error(),
-		})
+	if err := s.userRepo.Update(c.UserContext(), user); err != nil {
+		return apperror.From(err)
 	}
 
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "User berhasil diupdate",
+		Message: i18n.T(c.UserContext(), "user.updated"),
 	})
 }
 
@@ -325,27 +258,19 @@ func (s *UserService) DeleteUser(c *fiber.Ctx) error {
 	id := c.Params("id")
 	userUUID, err := uuid.Parse(id)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "user_id tidak valid",
-			Error:   err.Error(),
-		})
+		return apperror.BadRequest(apperror.CodeInvalidUserID, "user.invalid_id")
 	}
 
-	if err := s.userRepo.Delete(userUUID); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal menghapus user",
-			Error:   err.Error(),
-		})
+	if err := s.userRepo.Delete(c.UserContext(), userUUID); err != nil {
+		return apperror.From(err)
 	}
 
-	_ = s.studentRepo.DeleteByUserID(userUUID)
-	_ = s.lecturerRepo.DeleteByUserID(userUUID)
+	_ = s.studentRepo.DeleteByUserID(c.UserContext(), userUUID)
+	_ = s.lecturerRepo.DeleteByUserID(c.UserContext(), userUUID)
 
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "User berhasil dihapus",
+		Message: i18n.T(c.UserContext(), "user.deleted"),
 	})
 }
 
@@ -354,120 +279,113 @@ func (s *UserService) ChangeRole(c *fiber.Ctx) error {
 	id := c.Params("id")
 	userUUID, err := uuid.Parse(id)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "user_id tidak valid",
-			Error:   err.Error(),
-		})
+		return apperror.BadRequest(apperror.CodeInvalidUserID, "user.invalid_id")
 	}
 
 	var req model.ChangeRoleRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Input tidak valid",
-			Error:   err.Error(),
-		})
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
 	}
 
 	if err := helper.ValidateStruct(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Validasi gagal",
-			Error:   helper.FormatValidationErrors(err),
-		})
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
 	}
 
+	var unit *model.AcademicUnit
 	if req.Role == model.RoleMahasiswa {
 		if req.Student == nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Data khusus mahasiswa diperlukan untuk role mahasiswa",
-			})
+			return apperror.BadRequest(apperror.CodeStudentDataRequired, "user.student_data_required")
 		}
 		if err := helper.ValidateStruct(*req.Student); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Validasi data mahasiswa gagal",
-				Error:   helper.FormatValidationErrors(err),
-			})
+			return apperror.Validation(apperror.CodeValidationFailed, "validation.student_failed", helper.FieldErrors(err))
+		}
+		resolved, err := s.resolveUnit(c.UserContext(), model.UnitStudyProgram, req.Student.StudyProgramID, req.Student.ProgramStudy)
+		if err != nil {
+			return err
 		}
+		unit = resolved
 	} else if req.Role == model.RoleDosenWali {
 		if req.Lecturer == nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Data khusus dosen wali diperlukan untuk role dosen_wali",
-			})
+			return apperror.BadRequest(apperror.CodeLecturerDataRequired, "user.lecturer_data_required")
 		}
 		if err := helper.ValidateStruct(*req.Lecturer); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Validasi data dosen wali gagal",
-				Error:   helper.FormatValidationErrors(err),
-			})
+			return apperror.Validation(apperror.CodeValidationFailed, "validation.lecturer_failed", helper.FieldErrors(err))
+		}
+		resolved, err := s.resolveUnit(c.UserContext(), model.UnitDepartment, req.Lecturer.DepartmentID, req.Lecturer.Department)
+		if err != nil {
+			return err
 		}
+		unit = resolved
 	}
 
-	roleData, err := s.userRepo.FindRoleByName(req.Role)
+	roleData, err := s.userRepo.FindRoleByName(c.UserContext(), req.Role)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Role tidak valid: " + req.Role,
-			Error:   err.Error(),
-		})
+		return apperror.Translate(err, apperror.BadRequest(apperror.CodeRoleInvalid, "user.role_invalid", req.Role))
 	}
 
-	user, err := s.userRepo.FindByUserID(userUUID)
+	user, err := s.userRepo.FindByUserID(c.UserContext(), userUUID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "User tidak ditemukan",
-			Error:   err.Error(),
-		})
+		return apperror.Translate(err, apperror.NotFound(apperror.CodeUserNotFound, "user.not_found"))
 	}
 
-	if err := s.userRepo.UpdateRole(userUUID, roleData.ID); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Gagal mengupdate role",
-			Error:   err.Error(),
-		})
+	if err := s.userRepo.UpdateRole(c.UserContext(), userUUID, roleData.ID); err != nil {
+		return apperror.From(err)
 	}
 
-	_ = s.studentRepo.DeleteByUserID(user.ID)
-	_ = s.lecturerRepo.DeleteByUserID(user.ID)
+	_ = s.studentRepo.DeleteByUserID(c.UserContext(), user.ID)
+	_ = s.lecturerRepo.DeleteByUserID(c.UserContext(), user.ID)
 
 	if roleData.Name == model.RoleMahasiswa {
 		student := model.Student{
-			UserID:       user.ID,
-			StudentID:    user.Username,
-			ProgramStudy: req.Student.ProgramStudy,
-			AcademicYear: req.Student.AcademicYear,
+			UserID:         user.ID,
+			StudentID:      user.Username,
+			ProgramStudy:   unit.Name,
+			StudyProgramID: &unit.ID,
+			AcademicYear:   req.Student.AcademicYear,
 		}
-		if err := s.studentRepo.Create(&student); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Role berhasil diupdate tetapi gagal membuat profile mahasiswa",
-				Error:   err.Error(),
-			})
+		if err := s.studentRepo.Create(c.UserContext(), &student); err != nil {
+			return apperror.From(err)
 		}
 	} else if roleData.Name == model.RoleDosenWali {
 		lecturer := model.Lecturer{
-			UserID:     user.ID,
-			LecturerID: user.Username,
-			Department: req.Lecturer.Department,
+			UserID:       user.ID,
+			LecturerID:   user.Username,
+			Department:   unit.Name,
+			DepartmentID: &unit.ID,
 		}
-		if err := s.lecturerRepo.Create(&lecturer); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Role berhasil diupdate tetapi gagal membuat profile dosen wali",
-				Error:   err.Error(),
-			})
+		if err := s.lecturerRepo.Create(c.UserContext(), &lecturer); err != nil {
+			return apperror.From(err)
 		}
 	}
 
 	return c.JSON(model.SuccessMessageResponse{
 		Success: true,
-		Message: "Role berhasil diupdate",
+		Message: i18n.T(c.UserContext(), "user.role_updated"),
 	})
 }
+
+// resolveUnit mencari unit akademik dari id, atau dari nama/kode untuk klien
+// yang masih mengirim teks bebas.
+func (s *UserService) resolveUnit(ctx context.Context, kind, rawID, name string) (*model.AcademicUnit, error) {
+	field, key := "study_program_id", "validation.student_failed"
+	if kind == model.UnitDepartment {
+		field, key = "department_id", "validation.lecturer_failed"
+	}
+
+	var unit *model.AcademicUnit
+	var err error
+	switch {
+	case rawID != "":
+		unit, err = s.unitRepo.FindByID(ctx, kind, uuid.MustParse(rawID))
+	case strings.TrimSpace(name) != "":
+		unit, err = s.unitRepo.FindByName(ctx, kind, name)
+	default:
+		return nil, apperror.Validation(apperror.CodeValidationFailed, key, []model.FieldError{{Field: field, Rule: "required"}})
+	}
+	if err != nil {
+		return nil, apperror.Translate(err, apperror.Validation(apperror.CodeAcademicUnitNotFound, key, []model.FieldError{
+			{Field: field, Rule: "exists"},
+		}))
+	}
+	return unit, nil
+}
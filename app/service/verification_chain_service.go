@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+	"fiber/skp/helper"
+	"fiber/skp/i18n"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var errVerificationChainNotFound = apperror.NotFound(apperror.CodeVerificationChainNotFound, "verification_chain.not_found")
+
+type VerificationChainService struct {
+	chainRepo repo.VerificationChainRepository
+	typeRepo  repo.AchievementTypeRepository
+}
+
+func NewVerificationChainService(chainRepo repo.VerificationChainRepository, typeRepo repo.AchievementTypeRepository) *VerificationChainService {
+	return &VerificationChainService{chainRepo: chainRepo, typeRepo: typeRepo}
+}
+
+// GET /api/v1/verification-chains
+func (s *VerificationChainService) List(c *fiber.Ctx) error {
+	chains, err := s.chainRepo.FindAll(c.UserContext(), c.QueryBool("all", false))
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.VerificationChain]{
+		Success: true,
+		Data:    chains,
+	})
+}
+
+// GET /api/v1/verification-chains/:id
+func (s *VerificationChainService) Get(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidVerificationChainID, "verification_chain.invalid_id")
+	}
+
+	chain, err := s.chainRepo.FindByID(c.UserContext(), id)
+	if err != nil {
+		return apperror.Translate(err, errVerificationChainNotFound)
+	}
+
+	return c.JSON(model.SuccessResponse[*model.VerificationChain]{
+		Success: true,
+		Data:    chain,
+	})
+}
+
+// POST /api/v1/verification-chains
+func (s *VerificationChainService) Create(c *fiber.Ctx) error {
+	var req model.CreateVerificationChainRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	if _, err := s.typeRepo.FindByCode(c.UserContext(), req.AchievementType); err != nil {
+		return apperror.Translate(err, apperror.Validation(apperror.CodeAchievementTypeNotFound, "achievement_type.not_found", []model.FieldError{
+			{Field: "achievement_type", Rule: "exists"},
+		}))
+	}
+
+	chain := model.VerificationChain{
+		Name:            req.Name,
+		AchievementType: req.AchievementType,
+		LevelField:      req.LevelField,
+		LevelValue:      req.LevelValue,
+		Stages:          chainStages(req.Stages),
+	}
+	if err := s.chainRepo.Create(c.UserContext(), &chain); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return apperror.Conflict(apperror.CodeVerificationChainExists, "verification_chain.exists").Wrap(err)
+		}
+		return apperror.From(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.SuccessResponse[model.VerificationChain]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "verification_chain.created"),
+		Data:    chain,
+	})
+}
+
+// PUT /api/v1/verification-chains/:id
+func (s *VerificationChainService) Update(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidVerificationChainID, "verification_chain.invalid_id")
+	}
+
+	var req model.UpdateVerificationChainRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidInput, "error.invalid_input").Wrap(err)
+	}
+
+	if err := helper.ValidateStruct(req); err != nil {
+		return apperror.Validation(apperror.CodeValidationFailed, "validation.failed", helper.FieldErrors(err))
+	}
+
+	chain, err := s.chainRepo.FindByID(c.UserContext(), id)
+	if err != nil {
+		return apperror.Translate(err, errVerificationChainNotFound)
+	}
+
+	if req.Name != nil {
+		chain.Name = *req.Name
+	}
+	if req.Stages != nil {
+		chain.Stages = chainStages(req.Stages)
+	}
+	if req.IsActive != nil {
+		chain.IsActive = *req.IsActive
+	}
+
+	if err := s.chainRepo.Update(c.UserContext(), chain); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return apperror.Conflict(apperror.CodeVerificationChainExists, "verification_chain.exists").Wrap(err)
+		}
+		return apperror.Translate(err, errVerificationChainNotFound)
+	}
+
+	return c.JSON(model.SuccessResponse[*model.VerificationChain]{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "verification_chain.updated"),
+		Data:    chain,
+	})
+}
+
+// DELETE /api/v1/verification-chains/:id
+func (s *VerificationChainService) Delete(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.BadRequest(apperror.CodeInvalidVerificationChainID, "verification_chain.invalid_id")
+	}
+
+	if err := s.chainRepo.Deactivate(c.UserContext(), id); err != nil {
+		return apperror.Translate(err, errVerificationChainNotFound)
+	}
+
+	return c.JSON(model.SuccessMessageResponse{
+		Success: true,
+		Message: i18n.T(c.UserContext(), "verification_chain.deactivated"),
+	})
+}
+
+func chainStages(reqs []model.VerificationStageRequest) []model.VerificationStage {
+	stages := make([]model.VerificationStage, len(reqs))
+	for i, r := range reqs {
+		stages[i] = model.VerificationStage{
+			Position: i + 1,
+			Name:     r.Name,
+			Approver: r.Approver,
+			SLAHours: r.SLAHours,
+		}
+	}
+	return stages
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GET /api/v1/verification/queue
+func (s *AchievementService) VerificationQueue(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uuid.UUID)
+	role := c.Locals("role").(string)
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	items, total, err := s.repo.FindVerificationQueue(c.UserContext(), userID, role, page, limit)
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[model.PaginationData[model.QueueItem]]{
+		Success: true,
+		Data: model.PaginationData[model.QueueItem]{
+			Items: items,
+			Meta: model.MetaInfo{
+				Page:   page,
+				Limit:  limit,
+				Total:  total,
+				Pages:  int(math.Ceil(float64(total) / float64(limit))),
+				SortBy: "submitted_at",
+				Order:  "asc",
+			},
+		},
+	})
+}
+
+// GET /api/v1/verification/sla-stats
+func (s *AchievementService) SLAStats(c *fiber.Ctx) error {
+	stats, err := s.repo.FindSLAStats(c.UserContext())
+	if err != nil {
+		return apperror.From(err)
+	}
+
+	return c.JSON(model.SuccessResponse[[]model.LecturerSLAStats]{
+		Success: true,
+		Data:    stats,
+	})
+}
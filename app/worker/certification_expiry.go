@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+	"fiber/skp/helper"
+)
+
+// CertificationExpiry secara berkala memberi tahu mahasiswa tentang sertifikasi
+// terverifikasi yang akan atau sudah melewati validUntil.
+type CertificationExpiry struct {
+	achievementRepo  repo.AchievementRepository
+	notificationRepo repo.NotificationRepository
+	interval         time.Duration
+	warning          time.Duration
+}
+
+func NewCertificationExpiry(achievementRepo repo.AchievementRepository, notificationRepo repo.NotificationRepository, interval, warning time.Duration) *CertificationExpiry {
+	return &CertificationExpiry{
+		achievementRepo:  achievementRepo,
+		notificationRepo: notificationRepo,
+		interval:         interval,
+		warning:          warning,
+	}
+}
+
+func (w *CertificationExpiry) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *CertificationExpiry) check(ctx context.Context) {
+	now := time.Now()
+
+	// Yang sudah kedaluwarsa diproses lebih dulu dan sekaligus ditandai
+	// "expiring" agar mahasiswa tidak menerima dua notifikasi.
+	w.notify(ctx, model.NotificationCertificationExpired, now,
+		model.NotificationCertificationExpired, model.NotificationCertificationExpiring)
+	w.notify(ctx, model.NotificationCertificationExpiring, now.Add(w.warning),
+		model.NotificationCertificationExpiring)
+}
+
+func (w *CertificationExpiry) notify(ctx context.Context, alert string, before time.Time, marks ...string) {
+	alerts, err := w.achievementRepo.FindCertificationAlerts(ctx, alert, before)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Gagal mencari sertifikasi yang kedaluwarsa", "alert", alert, "error", err)
+		}
+		return
+	}
+
+	for _, a := range alerts {
+		achievementID := a.AchievementID
+		n := &model.Notification{
+			UserID:        a.StudentUserID,
+			Type:          alert,
+			AchievementID: &achievementID,
+			Params:        []string{certificationLabel(a), a.ValidUntil.Format(helper.DateLayout)},
+		}
+		if err := w.notificationRepo.Create(ctx, n); err != nil {
+			slog.Error("Gagal membuat notifikasi sertifikasi", "achievement_id", a.AchievementID, "error", err)
+			continue
+		}
+		if err := w.achievementRepo.MarkCertificationAlerted(ctx, a.MongoID, marks...); err != nil {
+			slog.Error("Gagal menandai sertifikasi", "achievement_id", a.AchievementID, "error", err)
+		}
+	}
+
+	if len(alerts) > 0 {
+		slog.Info("Notifikasi sertifikasi dikirim", "alert", alert, "count", len(alerts))
+	}
+}
+
+func certificationLabel(a model.CertificationAlert) string {
+	if a.CertificationName != "" {
+		return a.CertificationName
+	}
+	return a.Title
+}
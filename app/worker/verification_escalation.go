@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"fiber/skp/app/model"
+	"fiber/skp/app/repo"
+	"fiber/skp/helper"
+)
+
+// VerificationEscalation secara berkala memberi tahu admin tentang tahap
+// verifikasi yang melewati SLA. Setiap tahap hanya dieskalasi sekali.
+type VerificationEscalation struct {
+	achievementRepo  repo.AchievementRepository
+	userRepo         repo.UserRepository
+	notificationRepo repo.NotificationRepository
+	interval         time.Duration
+}
+
+func NewVerificationEscalation(achievementRepo repo.AchievementRepository, userRepo repo.UserRepository, notificationRepo repo.NotificationRepository, interval time.Duration) *VerificationEscalation {
+	return &VerificationEscalation{
+		achievementRepo:  achievementRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		interval:         interval,
+	}
+}
+
+func (w *VerificationEscalation) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *VerificationEscalation) check(ctx context.Context) {
+	stages, err := w.achievementRepo.FindOverdueStages(ctx, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Gagal mencari tahap verifikasi yang melewati SLA", "error", err)
+		}
+		return
+	}
+	if len(stages) == 0 {
+		return
+	}
+
+	adminIDs, err := w.userRepo.FindActiveIDsByRole(ctx, model.RoleAdmin)
+	if err != nil {
+		slog.Error("Gagal memuat admin untuk eskalasi verifikasi", "error", err)
+		return
+	}
+
+	for _, st := range stages {
+		achievementID := st.AchievementID
+		for _, adminID := range adminIDs {
+			n := &model.Notification{
+				UserID:        adminID,
+				Type:          model.NotificationVerificationOverdue,
+				AchievementID: &achievementID,
+				Params:        []string{st.Title, st.StudentName, st.StageName, st.DueAt.Format(helper.DateLayout), st.AdvisorName},
+			}
+			if err := w.notificationRepo.Create(ctx, n); err != nil {
+				slog.Error("Gagal membuat notifikasi eskalasi verifikasi", "achievement_id", st.AchievementID, "error", err)
+			}
+		}
+		if err := w.achievementRepo.MarkStageEscalated(ctx, st.AchievementID, st.Position); err != nil {
+			slog.Error("Gagal menandai tahap verifikasi dieskalasi", "achievement_id", st.AchievementID, "error", err)
+		}
+	}
+
+	slog.Info("Tahap verifikasi melewati SLA dieskalasi", "count", len(stages), "admins", len(adminIDs))
+}
@@ -3,14 +3,14 @@ package config
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
-func NewApp() *fiber.App {
-	app := fiber.New()
+func NewApp(errorHandler fiber.ErrorHandler) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: errorHandler,
+	})
 
-	app.Use(logger.New())
 	app.Use(recover.New())
 	app.Use(cors.New())
 
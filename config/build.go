@@ -0,0 +1,6 @@
+package config
+
+// Version diisi saat build, contoh:
+//
+//	go build -ldflags "-X fiber/skp/config.Version=1.2.0"
+var Version = "dev"
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +15,29 @@ type EnvConfig struct {
 	MongoURI  string
 	MongoDB   string
 	JWTSecret string
+
+	DBConnectRetries int
+	DBConnectBackoff time.Duration
+	ShutdownTimeout  time.Duration
+
+	DBAutoMigrate      bool
+	UploadDir          string
+	HealthCheckTimeout time.Duration
+
+	ServiceName    string
+	TracesExporter string
+	TraceFile      string
+
+	LogLevel string
+
+	RequestTimeout time.Duration
+	DBQueryTimeout time.Duration
+
+	CertExpiryInterval time.Duration
+	CertExpiryWarning  time.Duration
+
+	VerificationSLA                time.Duration
+	VerificationEscalationInterval time.Duration
 }
 
 var Env EnvConfig
@@ -27,6 +52,68 @@ func LoadEnv() {
 	Env.MongoURI = os.Getenv("MONGO_URI")
 	Env.MongoDB = os.Getenv("MONGO_DB_NAME")
 	Env.JWTSecret = os.Getenv("JWT_SECRET")
+
+	Env.DBConnectRetries = getEnvInt("DB_CONNECT_RETRIES", 5)
+	Env.DBConnectBackoff = getEnvDuration("DB_CONNECT_BACKOFF", time.Second)
+	Env.ShutdownTimeout = getEnvDuration("SHUTDOWN_TIMEOUT", 15*time.Second)
+
+	Env.DBAutoMigrate = getEnvBool("DB_AUTO_MIGRATE", true)
+	Env.UploadDir = os.Getenv("UPLOAD_DIR")
+	Env.HealthCheckTimeout = getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second)
+
+	Env.ServiceName = os.Getenv("OTEL_SERVICE_NAME")
+	Env.TracesExporter = os.Getenv("OTEL_TRACES_EXPORTER")
+	Env.TraceFile = os.Getenv("OTEL_TRACES_FILE")
+
+	Env.LogLevel = os.Getenv("LOG_LEVEL")
+
+	Env.RequestTimeout = getEnvDuration("REQUEST_TIMEOUT", 30*time.Second)
+	Env.DBQueryTimeout = getEnvDuration("DB_QUERY_TIMEOUT", 5*time.Second)
+
+	Env.CertExpiryInterval = getEnvDuration("CERT_EXPIRY_CHECK_INTERVAL", 24*time.Hour)
+	Env.CertExpiryWarning = getEnvDuration("CERT_EXPIRY_WARNING", 30*24*time.Hour)
+
+	Env.VerificationSLA = getEnvDuration("VERIFICATION_SLA", 72*time.Hour)
+	Env.VerificationEscalationInterval = getEnvDuration("VERIFICATION_ESCALATION_INTERVAL", time.Hour)
+}
+
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: %s tidak valid (%q), memakai default %d", key, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: %s tidak valid (%q), memakai default %t", key, value, fallback)
+		return fallback
+	}
+	return b
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: %s tidak valid (%q), memakai default %s", key, value, fallback)
+		return fallback
+	}
+	return d
 }
 
 func GetDBDSN() string {
@@ -49,3 +136,83 @@ func GetAppPort() string {
 	}
 	return Env.AppPort
 }
+
+func GetDBConnectRetries() int {
+	if Env.DBConnectRetries < 1 {
+		return 1
+	}
+	return Env.DBConnectRetries
+}
+
+func GetDBConnectBackoff() time.Duration {
+	return Env.DBConnectBackoff
+}
+
+func GetShutdownTimeout() time.Duration {
+	return Env.ShutdownTimeout
+}
+
+func GetDBAutoMigrate() bool {
+	return Env.DBAutoMigrate
+}
+
+func GetUploadDir() string {
+	if Env.UploadDir == "" {
+		return "./uploads"
+	}
+	return Env.UploadDir
+}
+
+func GetHealthCheckTimeout() time.Duration {
+	return Env.HealthCheckTimeout
+}
+
+func GetServiceName() string {
+	if Env.ServiceName == "" {
+		return "skor-kredit-prestasi"
+	}
+	return Env.ServiceName
+}
+
+func GetTracesExporter() string {
+	if Env.TracesExporter == "" {
+		return "none"
+	}
+	return Env.TracesExporter
+}
+
+func GetTraceFile() string {
+	if Env.TraceFile == "" {
+		return "traces.json"
+	}
+	return Env.TraceFile
+}
+
+func GetRequestTimeout() time.Duration {
+	return Env.RequestTimeout
+}
+
+func GetDBQueryTimeout() time.Duration {
+	return Env.DBQueryTimeout
+}
+
+// GetCertExpiryInterval mengembalikan jeda pemeriksaan sertifikasi; 0 menonaktifkan worker.
+func GetCertExpiryInterval() time.Duration {
+	return Env.CertExpiryInterval
+}
+
+func GetCertExpiryWarning() time.Duration {
+	return Env.CertExpiryWarning
+}
+
+// GetVerificationSLA mengembalikan batas waktu tahap dosen wali bawaan, dipakai
+// bila achievement tidak cocok dengan rantai verifikasi mana pun.
+func GetVerificationSLA() time.Duration {
+	return Env.VerificationSLA
+}
+
+// GetVerificationEscalationInterval mengembalikan jeda pemeriksaan tahap yang
+// melewati SLA; 0 menonaktifkan worker.
+func GetVerificationEscalationInterval() time.Duration {
+	return Env.VerificationEscalationInterval
+}
@@ -1,11 +1,56 @@
 package config
 
 import (
-	"log"
+	"log/slog"
 	"os"
+	"strings"
 )
 
+const redacted = "[REDACTED]"
+
+// sensitiveKeys adalah nama atribut log yang nilainya tidak boleh tercetak.
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"password_hash": true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"refreshtoken":  true,
+	"authorization": true,
+	"jwt_secret":    true,
+	"secret":        true,
+}
+
+// Logger memasang slog JSON handler sebagai logger default. Pemanggilan
+// package log standar juga diteruskan ke handler ini.
 func Logger() {
-	log.SetOutput(os.Stdout)
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       parseLogLevel(Env.LogLevel),
+		ReplaceAttr: redactAttr,
+	})
+	slog.SetDefault(slog.New(handler))
+}
+
+func parseLogLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	if a.Value.Kind() == slog.KindString && strings.HasPrefix(strings.ToLower(a.Value.String()), "bearer ") {
+		return slog.String(a.Key, redacted)
+	}
+	return a
 }
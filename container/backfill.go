@@ -0,0 +1,65 @@
+package container
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"fiber/skp/app/repo"
+	"fiber/skp/app/service"
+	"fiber/skp/db"
+)
+
+// backfill adalah pengisian data yang cukup dijalankan sekali setelah
+// migrasi. Run harus aman diulang bila gagal sebelum tercatat.
+type backfill struct {
+	name string
+	run  func(ctx context.Context) (int, error)
+}
+
+// runBackfills menjalankan backfill yang belum tercatat di data_backfills.
+// Backfill membutuhkan skema terbaru, jadi ditunda ke start berikutnya bila
+// masih ada migrasi yang belum diterapkan.
+func runBackfills(ctx context.Context, pgDB *sql.DB, mongoDB *mongo.Database) error {
+	pending, err := db.PendingMigrations(ctx, pgDB)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		slog.Warn("Backfill ditunda karena migrasi belum diterapkan", "pending", pending)
+		return nil
+	}
+
+	achievementRepo := repo.NewAchievementRepo(pgDB, mongoDB)
+	backfills := []backfill{
+		{name: "event_dates", run: achievementRepo.BackfillEventDates},
+		{name: "fingerprints", run: func(ctx context.Context) (int, error) {
+			return service.BackfillFingerprints(ctx, achievementRepo)
+		}},
+	}
+
+	for _, b := range backfills {
+		var done bool
+		err := pgDB.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM data_backfills WHERE name = $1)`, b.name).Scan(&done)
+		if err != nil {
+			return err
+		}
+		if done {
+			continue
+		}
+
+		affected, err := b.run(ctx)
+		if err != nil {
+			return fmt.Errorf("backfill %s gagal: %w", b.name, err)
+		}
+		if _, err := pgDB.ExecContext(ctx, `INSERT INTO data_backfills (name, affected) VALUES ($1, $2)`, b.name, affected); err != nil {
+			return fmt.Errorf("gagal mencatat backfill %s: %w", b.name, err)
+		}
+
+		slog.Info("Backfill berhasil dijalankan", "name", b.name, "affected", affected)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package container
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+
+	"fiber/skp/app/repo"
+	"fiber/skp/app/worker"
+	"fiber/skp/config"
+	"fiber/skp/db"
+	"fiber/skp/metrics"
+	"fiber/skp/middleware"
+	"fiber/skp/route"
+	"fiber/skp/tracing"
+)
+
+// Worker adalah proses latar belakang yang berjalan selama aplikasi hidup.
+// Run harus kembali setelah ctx dibatalkan.
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// Container memiliki seluruh dependency aplikasi beserta siklus hidupnya.
+type Container struct {
+	App         *fiber.App
+	DB          *sql.DB
+	MongoClient *mongo.Client
+	Mongo       *mongo.Database
+
+	shutdownTracing func(context.Context) error
+
+	workers      []Worker
+	workerCancel context.CancelFunc
+	workerWG     sync.WaitGroup
+}
+
+func New(ctx context.Context) (*Container, error) {
+	shutdownTracing, err := tracing.Setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pgDB, err := db.ConnectPostgres(ctx, config.GetDBDSN())
+	if err != nil {
+		shutdownTracing(context.Background())
+		return nil, err
+	}
+
+	mongoClient, err := db.ConnectMongo(ctx, config.GetMongoURI(), metrics.MongoCommandMonitor(), otelmongo.NewMonitor())
+	if err != nil {
+		pgDB.Close()
+		shutdownTracing(context.Background())
+		return nil, err
+	}
+
+	// Backfill tetap dijalankan walau migrasi diterapkan di luar aplikasi.
+	err = db.WithMigrationLock(ctx, pgDB, func() error {
+		if config.GetDBAutoMigrate() {
+			if err := db.Migrate(ctx, pgDB); err != nil {
+				return err
+			}
+		}
+		return runBackfills(ctx, pgDB, mongoClient.Database(config.GetMongoDB()))
+	})
+	if err != nil {
+		pgDB.Close()
+		mongoClient.Disconnect(context.Background())
+		shutdownTracing(context.Background())
+		return nil, err
+	}
+
+	metrics.RegisterDBStats(pgDB)
+
+	c := &Container{
+		DB:              pgDB,
+		MongoClient:     mongoClient,
+		Mongo:           mongoClient.Database(config.GetMongoDB()),
+		shutdownTracing: shutdownTracing,
+	}
+
+	c.App = config.NewApp(middleware.ErrorHandler)
+	route.SetupRoutes(c.App, c.DB, c.Mongo)
+
+	if interval := config.GetCertExpiryInterval(); interval > 0 {
+		c.AddWorker(worker.NewCertificationExpiry(
+			repo.NewAchievementRepo(c.DB, c.Mongo),
+			repo.NewNotificationRepo(c.DB),
+			interval,
+			config.GetCertExpiryWarning(),
+		))
+	}
+
+	if interval := config.GetVerificationEscalationInterval(); interval > 0 {
+		c.AddWorker(worker.NewVerificationEscalation(
+			repo.NewAchievementRepo(c.DB, c.Mongo),
+			repo.NewUserRepo(c.DB),
+			repo.NewNotificationRepo(c.DB),
+			interval,
+		))
+	}
+
+	return c, nil
+}
+
+// AddWorker mendaftarkan worker yang akan dijalankan oleh Run.
+func (c *Container) AddWorker(w Worker) {
+	c.workers = append(c.workers, w)
+}
+
+// Run menjalankan worker dan HTTP server sampai ctx dibatalkan atau listener gagal,
+// lalu mematikan semuanya dengan urutan yang aman.
+func (c *Container) Run(ctx context.Context) error {
+	workerCtx, cancel := context.WithCancel(context.Background())
+	c.workerCancel = cancel
+
+	for _, w := range c.workers {
+		c.workerWG.Add(1)
+		go func(w Worker) {
+			defer c.workerWG.Done()
+			w.Run(workerCtx)
+		}(w)
+	}
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- c.App.Listen(":" + config.GetAppPort())
+	}()
+
+	select {
+	case err := <-listenErr:
+		return errors.Join(err, c.Shutdown())
+	case <-ctx.Done():
+		slog.Info("Sinyal shutdown diterima, menghentikan server")
+	}
+
+	return c.Shutdown()
+}
+
+// Shutdown menunggu request yang sedang berjalan selesai, menghentikan worker,
+// menutup koneksi PostgreSQL dan MongoDB, lalu mengirim span yang tersisa.
+func (c *Container) Shutdown() error {
+	timeout := config.GetShutdownTimeout()
+	var errs []error
+
+	if err := c.App.ShutdownWithTimeout(timeout); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.workerCancel != nil {
+		c.workerCancel()
+	}
+	c.workerWG.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := c.MongoClient.Disconnect(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.DB.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.shutdownTracing(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	slog.Info("Semua dependency berhasil ditutup")
+	return errors.Join(errs...)
+}
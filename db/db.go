@@ -3,74 +3,134 @@ package db
 import (
 	"context"
 	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"fiber/skp/config"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
-var (
-	DB    *sql.DB
-	Mongo *mongo.Database
+const (
+	pingTimeout = 10 * time.Second
+	maxBackoff  = 30 * time.Second
 )
 
-func ConnectDB() {
-	config.LoadEnv()
-
-	connectPostgres()
-	connectMongo()
-}
-
-func connectPostgres() {
-	dsn := config.GetDBDSN()
-
-	var err error
-	DB, err = sql.Open("postgres", dsn)
+// ConnectPostgres membuka pool PostgreSQL dan menunggu sampai server dapat di-ping.
+func ConnectPostgres(ctx context.Context, dsn string) (*sql.DB, error) {
+	pgDB, err := otelsql.Open("postgres", dsn,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true}),
+	)
 	if err != nil {
-		log.Fatal("Gagal terhubung ke PostgreSQL:", err)
+		return nil, fmt.Errorf("gagal membuka koneksi PostgreSQL: %w", err)
 	}
 
-	if err = DB.Ping(); err != nil {
-		log.Fatal("Gagal ping PostgreSQL:", err)
-	}
+	pgDB.SetMaxOpenConns(25)
+	pgDB.SetMaxIdleConns(5)
+	pgDB.SetConnMaxLifetime(5 * time.Minute)
 
-	DB.SetMaxOpenConns(25)
-	DB.SetMaxIdleConns(5)
-	DB.SetConnMaxLifetime(5 * time.Minute)
+	err = withRetry(ctx, "PostgreSQL", func(ctx context.Context) error {
+		return pgDB.PingContext(ctx)
+	})
+	if err != nil {
+		pgDB.Close()
+		return nil, err
+	}
 
-	log.Println("Berhasil terhubung ke PostgreSQL")
+	slog.Info("Berhasil terhubung ke PostgreSQL")
+	return pgDB, nil
 }
 
-func connectMongo() {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	mongoURI := config.GetMongoURI()
-	clientOptions := options.Client().ApplyURI(mongoURI)
-
-	client, err := mongo.Connect(ctx, clientOptions)
+// ConnectMongo membuat client MongoDB dan menunggu sampai server dapat di-ping.
+// Setiap monitor menerima seluruh event command dari driver.
+func ConnectMongo(ctx context.Context, uri string, monitors ...*event.CommandMonitor) (*mongo.Client, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetMonitor(combineMonitors(monitors)))
 	if err != nil {
-		log.Fatal("Gagal terhubung ke MongoDB:", err)
+		return nil, fmt.Errorf("gagal membuat client MongoDB: %w", err)
 	}
 
-	err = client.Ping(ctx, nil)
+	err = withRetry(ctx, "MongoDB", func(ctx context.Context) error {
+		return client.Ping(ctx, nil)
+	})
 	if err != nil {
-		log.Fatal("Gagal ping MongoDB:", err)
+		client.Disconnect(context.Background())
+		return nil, err
 	}
 
-	dbName := config.GetMongoDB()
-	Mongo = client.Database(dbName)
-	log.Println("Berhasil terhubung ke MongoDB")
+	slog.Info("Berhasil terhubung ke MongoDB")
+	return client, nil
 }
 
-func GetDB() *sql.DB {
-	return DB
+func combineMonitors(monitors []*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				if m.Started != nil {
+					m.Started(ctx, evt)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				if m.Succeeded != nil {
+					m.Succeeded(ctx, evt)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				if m.Failed != nil {
+					m.Failed(ctx, evt)
+				}
+			}
+		},
+	}
 }
 
-func GetMongo() *mongo.Database {
-	return Mongo
+// withRetry menjalankan fn dengan exponential backoff sampai berhasil,
+// jumlah percobaan habis, atau ctx dibatalkan.
+func withRetry(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	attempts := config.GetDBConnectRetries()
+	backoff := config.GetDBConnectBackoff()
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		err = fn(attemptCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		slog.Warn("Gagal terhubung ke database, mencoba lagi",
+			"database", name,
+			"attempt", attempt,
+			"max_attempts", attempts,
+			"backoff", backoff.String(),
+			"error", err,
+		)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("koneksi ke %s dibatalkan: %w", name, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	return fmt.Errorf("gagal terhubung ke %s setelah %d percobaan: %w", name, attempts, err)
 }
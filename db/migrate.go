@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+const createMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR(255) PRIMARY KEY,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+
+// migrationLockKey adalah kunci pg_advisory_lock yang dipegang selama migrasi
+// dan backfill agar replika yang start bersamaan tidak menjalankannya ganda.
+const migrationLockKey int64 = 0x736b705f6d6967 // "skp_mig"
+
+// WithMigrationLock menjalankan fn sambil memegang advisory lock migrasi.
+// Replika lain menunggu sampai fn selesai, lalu mendapati migrasi dan backfill
+// sudah tercatat.
+func WithMigrationLock(ctx context.Context, pgDB *sql.DB, fn func() error) error {
+	// Advisory lock terikat ke sesi, jadi dipegang lewat satu koneksi khusus.
+	conn, err := pgDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("gagal mengambil lock migrasi: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			slog.Error("Gagal melepas lock migrasi", "error", err)
+		}
+	}()
+
+	return fn()
+}
+
+// migrationVersions mengembalikan nama file migrasi yang di-embed, terurut naik.
+func migrationVersions() ([]string, error) {
+	entries, err := fs.ReadDir(migrationFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		versions = append(versions, strings.TrimSuffix(e.Name(), ".sql"))
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+func appliedVersions(ctx context.Context, pgDB *sql.DB) (map[string]bool, error) {
+	rows, err := pgDB.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// Migrate menjalankan migrasi yang belum diterapkan, masing-masing di dalam transaksi.
+func Migrate(ctx context.Context, pgDB *sql.DB) error {
+	if _, err := pgDB.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("gagal membuat tabel schema_migrations: %w", err)
+	}
+
+	pending, err := PendingMigrations(ctx, pgDB)
+	if err != nil {
+		return err
+	}
+
+	for _, version := range pending {
+		script, err := migrationFS.ReadFile("migrations/" + version + ".sql")
+		if err != nil {
+			return err
+		}
+
+		tx, err := pgDB.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrasi %s gagal: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("gagal mencatat migrasi %s: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		slog.Info("Migrasi berhasil diterapkan", "version", version)
+	}
+
+	return nil
+}
+
+// PendingMigrations mengembalikan migrasi yang belum tercatat di schema_migrations.
+func PendingMigrations(ctx context.Context, pgDB *sql.DB) ([]string, error) {
+	versions, err := migrationVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(ctx, pgDB)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, v := range versions {
+		if !applied[v] {
+			pending = append(pending, v)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,11 @@
+-- Kolom & tabel token yang dipakai AuthService namun belum ada di skema awal
+ALTER TABLE users ADD COLUMN IF NOT EXISTS refresh_token TEXT;
+
+CREATE TABLE IF NOT EXISTS blacklisted_tokens (
+    id SERIAL PRIMARY KEY,
+    token TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_blacklisted_tokens_token ON blacklisted_tokens(token);
@@ -0,0 +1,126 @@
+-- Registry tipe achievement beserta JSON Schema untuk field details
+CREATE TABLE IF NOT EXISTS achievement_types (
+    code VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    details_schema JSONB NOT NULL DEFAULT '{"type": "object"}',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO achievement_types (code, name, description, details_schema) VALUES
+('academic', 'Akademik', 'Prestasi akademik seperti IPK atau penghargaan akademik', '{
+    "type": "object",
+    "properties": {
+        "score": {"type": "number", "minimum": 0},
+        "location": {"type": "string", "maxLength": 200},
+        "organizer": {"type": "string", "maxLength": 200}
+    },
+    "additionalProperties": false
+}'),
+('competition', 'Kompetisi', 'Lomba atau kompetisi', '{
+    "type": "object",
+    "properties": {
+        "competitionName": {"type": "string", "minLength": 1, "maxLength": 200},
+        "competitionLevel": {"type": "string", "minLength": 1, "maxLength": 50},
+        "rank": {"type": "integer", "minimum": 1},
+        "medalType": {"type": "string", "maxLength": 50},
+        "location": {"type": "string", "maxLength": 200},
+        "organizer": {"type": "string", "maxLength": 200}
+    },
+    "required": ["competitionName", "competitionLevel"],
+    "additionalProperties": false
+}'),
+('organization', 'Organisasi', 'Kepengurusan organisasi', '{
+    "type": "object",
+    "properties": {
+        "organizationName": {"type": "string", "minLength": 1, "maxLength": 200},
+        "position": {"type": "string", "minLength": 1, "maxLength": 100},
+        "period": {
+            "type": "object",
+            "properties": {
+                "start": {"type": "string", "format": "date"},
+                "end": {"type": "string", "format": "date"}
+            },
+            "required": ["start", "end"],
+            "additionalProperties": false
+        }
+    },
+    "required": ["organizationName", "position", "period"],
+    "additionalProperties": false
+}'),
+('publication', 'Publikasi', 'Publikasi ilmiah', '{
+    "type": "object",
+    "properties": {
+        "publicationType": {"type": "string", "maxLength": 50},
+        "publicationTitle": {"type": "string", "minLength": 1, "maxLength": 300},
+        "authors": {"type": "array", "minItems": 1, "maxItems": 20, "items": {"type": "string", "minLength": 1, "maxLength": 100}},
+        "publisher": {"type": "string", "maxLength": 200},
+        "issn": {"type": "string", "maxLength": 20}
+    },
+    "required": ["publicationTitle", "authors"],
+    "additionalProperties": false
+}'),
+('certification', 'Sertifikasi', 'Sertifikasi kompetensi', '{
+    "type": "object",
+    "properties": {
+        "certificationName": {"type": "string", "minLength": 1, "maxLength": 200},
+        "issuedBy": {"type": "string", "minLength": 1, "maxLength": 200},
+        "certificationNumber": {"type": "string", "maxLength": 100},
+        "validUntil": {"type": "string", "format": "date"}
+    },
+    "required": ["certificationName", "issuedBy"],
+    "additionalProperties": false
+}'),
+('other', 'Lainnya', 'Prestasi lain', '{"type": "object"}'),
+('community_service', 'Pengabdian Masyarakat', 'Kegiatan pengabdian kepada masyarakat', '{
+    "type": "object",
+    "properties": {
+        "activityName": {"type": "string", "minLength": 1, "maxLength": 200},
+        "organizer": {"type": "string", "maxLength": 200},
+        "location": {"type": "string", "maxLength": 200},
+        "hours": {"type": "integer", "minimum": 1}
+    },
+    "required": ["activityName"],
+    "additionalProperties": false
+}'),
+('patent', 'Paten', 'Paten atau hak kekayaan intelektual', '{
+    "type": "object",
+    "properties": {
+        "patentTitle": {"type": "string", "minLength": 1, "maxLength": 300},
+        "patentNumber": {"type": "string", "maxLength": 100},
+        "inventors": {"type": "array", "minItems": 1, "items": {"type": "string", "minLength": 1, "maxLength": 100}},
+        "patentStatus": {"type": "string", "enum": ["filed", "granted"]}
+    },
+    "required": ["patentTitle", "patentStatus"],
+    "additionalProperties": false
+}'),
+('internship', 'Magang', 'Program magang', '{
+    "type": "object",
+    "properties": {
+        "company": {"type": "string", "minLength": 1, "maxLength": 200},
+        "position": {"type": "string", "minLength": 1, "maxLength": 100},
+        "period": {
+            "type": "object",
+            "properties": {
+                "start": {"type": "string", "format": "date"},
+                "end": {"type": "string", "format": "date"}
+            },
+            "required": ["start", "end"],
+            "additionalProperties": false
+        }
+    },
+    "required": ["company", "position", "period"],
+    "additionalProperties": false
+}')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('achievement_type:manage', 'achievement_type', 'manage', 'Mengelola tipe achievement')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'achievement_type:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+-- Notifikasi untuk pengguna, mis. sertifikasi yang akan/sudah kedaluwarsa
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    achievement_id UUID REFERENCES achievement_references(id) ON DELETE CASCADE,
+    params JSONB NOT NULL DEFAULT '[]',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, created_at DESC);
@@ -0,0 +1,7 @@
+-- Achievement tim: satu dokumen MongoDB dirujuk oleh beberapa achievement_references
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS team_role VARCHAR(10) CHECK (team_role IN ('leader', 'member')),
+    ADD COLUMN IF NOT EXISTS points_share SMALLINT CHECK (points_share BETWEEN 1 AND 100),
+    ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_mongo ON achievement_references(mongo_achievement_id);
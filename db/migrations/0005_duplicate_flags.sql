@@ -0,0 +1,9 @@
+-- Dugaan duplikat yang ditemukan saat submit, untuk ditinjau dosen verifikator
+CREATE TABLE IF NOT EXISTS achievement_duplicate_flags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    matched_achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    reasons TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (achievement_id, matched_achievement_id)
+);
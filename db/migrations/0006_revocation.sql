@@ -0,0 +1,29 @@
+-- Pencabutan achievement terverifikasi dan log riwayat perubahan status.
+-- ADD VALUE di dalam transaksi membutuhkan PostgreSQL 12 atau lebih baru.
+ALTER TYPE achievement_status_enum ADD VALUE IF NOT EXISTS 'revoked';
+
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS revocation_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS achievement_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    action VARCHAR(30) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    points INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_events_achievement ON achievement_events(achievement_id, created_at);
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('achievement:revoke', 'achievement', 'revoke', 'Mencabut achievement yang sudah diverifikasi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name IN ('admin', 'dosen_wali') AND p.name = 'achievement:revoke'
+ON CONFLICT DO NOTHING;
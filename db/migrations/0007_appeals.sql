@@ -0,0 +1,29 @@
+-- Banding mahasiswa atas achievement yang ditolak
+ALTER TABLE lecturers ADD COLUMN IF NOT EXISTS is_appeal_reviewer BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS achievement_appeals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    student_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    statement TEXT NOT NULL,
+    rejection_note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'upheld', 'overturned')),
+    original_verifier_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    decision_note TEXT NOT NULL DEFAULT '',
+    points INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_achievement_appeals_pending
+    ON achievement_appeals(achievement_id) WHERE status = 'pending';
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('achievement:appeal_review', 'achievement', 'appeal_review', 'Meninjau banding atas achievement yang ditolak')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name IN ('admin', 'dosen_wali') AND p.name = 'achievement:appeal_review'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,52 @@
+-- Rantai verifikasi bertahap per tipe dan tingkat achievement
+ALTER TABLE lecturers ADD COLUMN IF NOT EXISTS is_department_head BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS verification_chains (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    achievement_type VARCHAR(50) NOT NULL REFERENCES achievement_types(code) ON UPDATE CASCADE,
+    level_field VARCHAR(50) NOT NULL DEFAULT '',
+    level_value VARCHAR(100) NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_chains_match
+    ON verification_chains(achievement_type, level_field, LOWER(level_value)) WHERE is_active;
+
+CREATE TABLE IF NOT EXISTS verification_stages (
+    chain_id UUID NOT NULL REFERENCES verification_chains(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    approver VARCHAR(20) NOT NULL CHECK (approver IN ('advisor', 'department_head', 'admin')),
+    sla_hours INT NOT NULL,
+    PRIMARY KEY (chain_id, position)
+);
+
+-- Salinan tahap rantai saat submit agar perubahan konfigurasi tidak
+-- memengaruhi achievement yang sedang diverifikasi.
+CREATE TABLE IF NOT EXISTS achievement_stages (
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    chain_name VARCHAR(100) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    approver VARCHAR(20) NOT NULL,
+    sla_hours INT NOT NULL,
+    started_at TIMESTAMP,
+    decision VARCHAR(20),
+    decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    points INT,
+    decided_at TIMESTAMP,
+    PRIMARY KEY (achievement_id, position)
+);
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('verification_chain:manage', 'verification_chain', 'manage', 'Mengelola rantai verifikasi achievement')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name IN ('verification_chain:manage', 'achievement:verify')
+ON CONFLICT DO NOTHING;
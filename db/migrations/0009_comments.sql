@@ -0,0 +1,24 @@
+-- Diskusi achievement dan permintaan perbaikan dari peninjau
+CREATE TABLE IF NOT EXISTS achievement_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES achievement_comments(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_comments_achievement ON achievement_comments(achievement_id);
+
+CREATE TABLE IF NOT EXISTS achievement_change_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    description TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'addressed')),
+    response TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    addressed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_change_items_achievement ON achievement_change_items(achievement_id);
@@ -0,0 +1,23 @@
+-- Pelacakan SLA verifikasi dan eskalasi tahap yang terlambat ke admin
+ALTER TABLE achievement_stages ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_stages_open
+    ON achievement_stages(started_at) WHERE decided_at IS NULL;
+
+-- Achievement yang disubmit sebelum ada rantai verifikasi diberi satu tahap
+-- dosen wali agar ikut antrean dan eskalasi.
+INSERT INTO achievement_stages (achievement_id, position, name, approver, sla_hours, started_at)
+SELECT ar.id, 1, 'Dosen Wali', 'advisor', 72, COALESCE(ar.submitted_at, ar.updated_at, CURRENT_TIMESTAMP)
+FROM achievement_references ar
+WHERE ar.status = 'submitted' AND COALESCE(ar.team_role, '') != 'member'
+  AND NOT EXISTS (SELECT 1 FROM achievement_stages st WHERE st.achievement_id = ar.id)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('verification:report', 'verification', 'report', 'Melihat statistik SLA verifikasi per dosen')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'verification:report'
+ON CONFLICT DO NOTHING;
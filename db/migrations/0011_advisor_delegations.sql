@@ -0,0 +1,32 @@
+-- Delegasi hak verifikasi dosen wali selama cuti
+CREATE TABLE IF NOT EXISTS advisor_delegations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    advisor_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+    delegate_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+    starts_at DATE NOT NULL,
+    ends_at DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    CHECK (advisor_id != delegate_id),
+    CHECK (ends_at >= starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_advisor_delegations_delegate
+    ON advisor_delegations(delegate_id, starts_at, ends_at) WHERE revoked_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_advisor_delegations_advisor
+    ON advisor_delegations(advisor_id, starts_at, ends_at) WHERE revoked_at IS NULL;
+
+-- Dosen wali asli yang diwakili saat keputusan diambil oleh penerima delegasi.
+ALTER TABLE achievement_events ADD COLUMN IF NOT EXISTS on_behalf_of UUID REFERENCES users(id) ON DELETE SET NULL;
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('advisor:delegate', 'advisor', 'delegate', 'Mendelegasikan hak verifikasi dosen wali')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name IN ('admin', 'dosen_wali') AND p.name = 'advisor:delegate'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,26 @@
+-- Riwayat penugasan dosen wali; baris dengan ends_at NULL adalah penugasan aktif
+-- dan selalu sama dengan students.advisor_id.
+CREATE TABLE IF NOT EXISTS advisor_assignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    lecturer_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at TIMESTAMP,
+    assigned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    CHECK (ends_at IS NULL OR ends_at >= starts_at)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_advisor_assignments_open
+    ON advisor_assignments(student_id) WHERE ends_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_advisor_assignments_lecturer
+    ON advisor_assignments(lecturer_id, starts_at);
+
+-- Penugasan yang sudah ada dianggap berlaku sejak data mahasiswa dibuat.
+INSERT INTO advisor_assignments (student_id, lecturer_id, starts_at)
+SELECT s.id, s.advisor_id, s.created_at
+FROM students s
+WHERE s.advisor_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM advisor_assignments a WHERE a.student_id = s.id)
+ON CONFLICT DO NOTHING;
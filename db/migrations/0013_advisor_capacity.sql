@@ -0,0 +1,5 @@
+-- Batas jumlah mahasiswa bimbingan per dosen wali; NULL berarti tidak dibatasi.
+ALTER TABLE lecturers ADD COLUMN IF NOT EXISTS max_advisees INTEGER CHECK (max_advisees > 0);
+
+CREATE INDEX IF NOT EXISTS idx_students_unassigned
+    ON students(program_study, student_id) WHERE advisor_id IS NULL;
@@ -0,0 +1,105 @@
+-- Data master fakultas, jurusan, dan program studi
+CREATE TABLE IF NOT EXISTS faculties (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS departments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    faculty_id UUID NOT NULL REFERENCES faculties(id) ON DELETE RESTRICT,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS study_programs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    department_id UUID NOT NULL REFERENCES departments(id) ON DELETE RESTRICT,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Nama dipakai untuk mencocokkan input teks lama, jadi harus unik tanpa
+-- membedakan huruf besar/kecil.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_faculties_name ON faculties(LOWER(name));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_departments_name ON departments(LOWER(name));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_study_programs_name ON study_programs(LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_departments_faculty ON departments(faculty_id);
+CREATE INDEX IF NOT EXISTS idx_study_programs_department ON study_programs(department_id);
+
+-- students.program_study dan lecturers.department tetap ada sebagai nama
+-- tampilan yang diselaraskan dengan data master.
+ALTER TABLE students ADD COLUMN IF NOT EXISTS study_program_id UUID REFERENCES study_programs(id) ON DELETE RESTRICT;
+ALTER TABLE lecturers ADD COLUMN IF NOT EXISTS department_id UUID REFERENCES departments(id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS idx_students_study_program ON students(study_program_id);
+CREATE INDEX IF NOT EXISTS idx_lecturers_department ON lecturers(department_id);
+
+-- Pemetaan teks lama. Penulisan yang hanya berbeda huruf besar/kecil atau spasi
+-- digabung; singkatan seperti "TI" tetap menjadi data tersendiri dan dapat
+-- digabung admin lewat endpoint merge. Karena selama ini program studi
+-- mahasiswa dicocokkan dengan jurusan dosen, setiap program studi lama
+-- mendapat jurusan dengan nama yang sama di bawah fakultas sementara.
+INSERT INTO faculties (code, name)
+SELECT 'UMUM', 'Belum Dikelompokkan'
+WHERE EXISTS (SELECT 1 FROM lecturers WHERE TRIM(COALESCE(department, '')) != '')
+   OR EXISTS (SELECT 1 FROM students WHERE TRIM(COALESCE(program_study, '')) != '')
+ON CONFLICT (code) DO NOTHING;
+
+WITH names AS (
+    SELECT LOWER(REGEXP_REPLACE(TRIM(name), '\s+', ' ', 'g')) AS key,
+           MIN(REGEXP_REPLACE(TRIM(name), '\s+', ' ', 'g')) AS name
+    FROM (
+        SELECT department FROM lecturers
+        UNION ALL
+        SELECT program_study FROM students
+    ) legacy(name)
+    WHERE TRIM(COALESCE(name, '')) != ''
+    GROUP BY 1
+)
+INSERT INTO departments (faculty_id, code, name)
+SELECT f.id, 'DEP' || LPAD((ROW_NUMBER() OVER (ORDER BY n.key))::TEXT, 3, '0'), n.name
+FROM names n
+JOIN faculties f ON f.code = 'UMUM'
+WHERE NOT EXISTS (SELECT 1 FROM departments d WHERE LOWER(d.name) = n.key)
+ON CONFLICT DO NOTHING;
+
+WITH names AS (
+    SELECT LOWER(REGEXP_REPLACE(TRIM(program_study), '\s+', ' ', 'g')) AS key,
+           MIN(REGEXP_REPLACE(TRIM(program_study), '\s+', ' ', 'g')) AS name
+    FROM students
+    WHERE TRIM(COALESCE(program_study, '')) != ''
+    GROUP BY 1
+)
+INSERT INTO study_programs (department_id, code, name)
+SELECT d.id, 'PRG' || LPAD((ROW_NUMBER() OVER (ORDER BY n.key))::TEXT, 3, '0'), n.name
+FROM names n
+JOIN departments d ON LOWER(d.name) = n.key
+WHERE NOT EXISTS (SELECT 1 FROM study_programs sp WHERE LOWER(sp.name) = n.key)
+ON CONFLICT DO NOTHING;
+
+UPDATE students s
+SET study_program_id = sp.id, program_study = sp.name
+FROM study_programs sp
+WHERE s.study_program_id IS NULL
+  AND LOWER(sp.name) = LOWER(REGEXP_REPLACE(TRIM(s.program_study), '\s+', ' ', 'g'));
+
+UPDATE lecturers l
+SET department_id = d.id, department = d.name
+FROM departments d
+WHERE l.department_id IS NULL
+  AND LOWER(d.name) = LOWER(REGEXP_REPLACE(TRIM(l.department), '\s+', ' ', 'g'));
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('academic_unit:manage', 'academic_unit', 'manage', 'Mengelola data fakultas, jurusan, dan program studi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'academic_unit:manage'
+ON CONFLICT DO NOTHING;
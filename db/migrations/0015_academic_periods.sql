@@ -0,0 +1,58 @@
+-- Kalender akademik: semester ganjil/genap beserta rentang tanggalnya
+CREATE TABLE IF NOT EXISTS academic_periods (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    year VARCHAR(9) NOT NULL,
+    semester VARCHAR(4) NOT NULL CHECK (semester IN ('odd', 'even')),
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (year, semester),
+    CHECK (ends_on >= starts_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_academic_periods_range ON academic_periods(starts_on, ends_on);
+
+-- Jendela pengajuan per periode; submit hanya diterima di dalam salah satunya.
+CREATE TABLE IF NOT EXISTS submission_windows (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    period_id UUID NOT NULL REFERENCES academic_periods(id) ON DELETE CASCADE,
+    opens_at TIMESTAMP NOT NULL,
+    closes_at TIMESTAMP NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (closes_at > opens_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_submission_windows_period ON submission_windows(period_id, opens_at, closes_at);
+
+-- Pengecualian jendela pengajuan bagi mahasiswa tertentu; period_id NULL
+-- berlaku untuk semua periode.
+CREATE TABLE IF NOT EXISTS submission_exemptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    period_id UUID REFERENCES academic_periods(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_submission_exemptions_student ON submission_exemptions(student_id, expires_at);
+
+-- Tanggal kegiatan disalin dari MongoDB agar periode bisa ditentukan di
+-- PostgreSQL. Data lama diisi oleh backfill event_dates saat aplikasi start.
+ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS event_date DATE;
+ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS period_id UUID REFERENCES academic_periods(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_period ON achievement_references(period_id);
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('academic_period:manage', 'academic_period', 'manage', 'Mengelola periode akademik dan jendela pengajuan')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'academic_period:manage'
+ON CONFLICT DO NOTHING;
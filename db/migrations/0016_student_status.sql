@@ -0,0 +1,28 @@
+-- Status studi mahasiswa beserta riwayat perubahannya
+ALTER TABLE students ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'on_leave', 'suspended', 'graduated', 'dropped_out'));
+ALTER TABLE students ADD COLUMN IF NOT EXISTS status_since DATE NOT NULL DEFAULT CURRENT_DATE;
+-- Diisi saat lulus; setelahnya rekam SKP mahasiswa tidak boleh berubah.
+ALTER TABLE students ADD COLUMN IF NOT EXISTS skp_frozen_at TIMESTAMP;
+
+UPDATE students SET status_since = created_at::date WHERE created_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_students_status ON students(status);
+
+CREATE TABLE IF NOT EXISTS student_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    effective_date DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_student_status_history_student ON student_status_history(student_id, effective_date);
+
+-- Mahasiswa lama tercatat aktif sejak profilnya dibuat.
+INSERT INTO student_status_history (student_id, status, effective_date, created_at)
+SELECT s.id, s.status, s.status_since, COALESCE(s.created_at, CURRENT_TIMESTAMP)
+FROM students s
+WHERE NOT EXISTS (SELECT 1 FROM student_status_history h WHERE h.student_id = s.id);
@@ -0,0 +1,7 @@
+-- Penanda backfill data yang dijalankan sekali oleh aplikasi setelah migrasi,
+-- untuk pengisian yang membutuhkan data dari MongoDB.
+CREATE TABLE IF NOT EXISTS data_backfills (
+    name VARCHAR(100) PRIMARY KEY,
+    affected INTEGER NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
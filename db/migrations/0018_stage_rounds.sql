@@ -0,0 +1,20 @@
+-- Tahap verifikasi dari pengajuan sebelumnya dipindahkan ke sini saat
+-- achievement disubmit ulang agar keputusan tiap putaran tetap tercatat.
+CREATE TABLE IF NOT EXISTS achievement_stage_rounds (
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    round SMALLINT NOT NULL,
+    position SMALLINT NOT NULL,
+    chain_name VARCHAR(100) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    approver VARCHAR(20) NOT NULL,
+    sla_hours INT NOT NULL,
+    started_at TIMESTAMP,
+    decision VARCHAR(20),
+    decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    points INT,
+    decided_at TIMESTAMP,
+    escalated_at TIMESTAMP,
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (achievement_id, round, position)
+);
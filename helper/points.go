@@ -0,0 +1,37 @@
+package helper
+
+import "sort"
+
+// SplitPoints membagi total poin sesuai persentase shares dengan metode sisa
+// terbesar sehingga jumlah hasilnya tetap sama dengan total.
+func SplitPoints(total int, shares []int) []int {
+	result := make([]int, len(shares))
+	sum := 0
+	for _, share := range shares {
+		sum += share
+	}
+	if sum == 0 {
+		return result
+	}
+
+	remainders := make([]int, len(shares))
+	allocated := 0
+	for i, share := range shares {
+		result[i] = total * share / sum
+		remainders[i] = total * share % sum
+		allocated += result[i]
+	}
+
+	order := make([]int, len(shares))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for i := 0; allocated < total; i++ {
+		result[order[i%len(order)]]++
+		allocated++
+	}
+	return result
+}
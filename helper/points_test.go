@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitPoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  int
+		shares []int
+		want   []int
+	}{
+		{name: "habis dibagi", total: 100, shares: []int{50, 30, 20}, want: []int{50, 30, 20}},
+		{name: "sisa terbesar mendapat sisa", total: 10, shares: []int{45, 35, 20}, want: []int{5, 3, 2}},
+		{name: "sisa sama jatuh ke urutan pertama", total: 10, shares: []int{1, 1, 1}, want: []int{4, 3, 3}},
+		{name: "sisa sama dua anggota", total: 5, shares: []int{50, 50}, want: []int{3, 2}},
+		{name: "sisa sama setelah yang lebih besar", total: 7, shares: []int{40, 30, 30}, want: []int{3, 2, 2}},
+		{name: "total nol", total: 0, shares: []int{60, 40}, want: []int{0, 0}},
+		{name: "semua share nol", total: 10, shares: []int{0, 0}, want: []int{0, 0}},
+		{name: "tanpa anggota", total: 10, shares: []int{}, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitPoints(tt.total, tt.shares)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SplitPoints(%d, %v) = %v, want %v", tt.total, tt.shares, got, tt.want)
+			}
+		})
+	}
+}
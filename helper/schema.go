@@ -0,0 +1,95 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"fiber/skp/app/model"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const schemaResource = "details.schema.json"
+
+// CompileSchema mengompilasi JSON Schema details. $ref ke URL luar ditolak
+// agar schema dari admin tidak bisa memicu request keluar.
+func CompileSchema(raw []byte) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("$ref ke %s tidak diizinkan", url)
+	}
+
+	if err := compiler.AddResource(schemaResource, bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaResource)
+}
+
+// SchemaFieldErrors mengubah error validasi JSON Schema menjadi error per field
+// dengan prefix, mis. "details.period.start".
+func SchemaFieldErrors(prefix string, err error) []model.FieldError {
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return nil
+	}
+
+	var fields []model.FieldError
+	for _, unit := range ve.BasicOutput().Errors {
+		if unit.Error == "" || strings.HasPrefix(unit.Error, "doesn't validate with") {
+			continue
+		}
+		keyword := unit.KeywordLocation[strings.LastIndex(unit.KeywordLocation, "/")+1:]
+		fields = append(fields, model.FieldError{
+			Field: prefix + strings.ReplaceAll(unit.InstanceLocation, "/", "."),
+			Rule:  "schema",
+			Param: keyword + ": " + unit.Error,
+		})
+	}
+	return fields
+}
+
+// CoerceValues menyesuaikan nilai hasil decode JSON dengan schema sebelum
+// disimpan ke MongoDB: string ber-format "date" menjadi time.Time dan angka
+// bertipe "integer" menjadi int64.
+func CoerceValues(schema *jsonschema.Schema, value interface{}) interface{} {
+	if schema == nil {
+		return value
+	}
+	if schema.Ref != nil {
+		value = CoerceValues(schema.Ref, value)
+	}
+
+	switch v := value.(type) {
+	case string:
+		if schema.Format == "date" {
+			if t, err := time.Parse(DateLayout, v); err == nil {
+				return t
+			}
+		}
+	case float64:
+		if len(schema.Types) == 1 && schema.Types[0] == "integer" && v == float64(int64(v)) {
+			return int64(v)
+		}
+	case map[string]interface{}:
+		for key, prop := range schema.Properties {
+			if child, ok := v[key]; ok {
+				v[key] = CoerceValues(prop, child)
+			}
+		}
+	case []interface{}:
+		items, _ := schema.Items.(*jsonschema.Schema)
+		if items == nil {
+			items = schema.Items2020
+		}
+		for i := range v {
+			v[i] = CoerceValues(items, v[i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeText menyamakan teks untuk perbandingan: huruf kecil, tanda baca
+// dibuang, dan spasi berlebih dirapikan.
+func NormalizeText(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		space = true
+	}
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package helper
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "kosong", in: "", want: ""},
+		{name: "huruf kecil", in: "Juara 1 LOMBA", want: "juara 1 lomba"},
+		{name: "tanda baca dibuang", in: "Juara-1, Lomba (Nasional)!", want: "juara 1 lomba nasional"},
+		{name: "spasi berlebih", in: "  juara \t 1\n lomba  ", want: "juara 1 lomba"},
+		{name: "hanya tanda baca", in: "-- !! --", want: ""},
+		{name: "unicode", in: "Kompetisi Ürün Çözüm", want: "kompetisi ürün çözüm"},
+		{name: "kode", in: "ISSN 1234-567X", want: "issn 1234 567x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeText(tt.in); got != tt.want {
+				t.Errorf("NormalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,44 +1,152 @@
 package helper
 
 import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"fiber/skp/app/model"
+
 	"github.com/go-playground/validator/v10"
 )
 
+const DateLayout = "2006-01-02"
+
 var validate *validator.Validate
 
+// slugPattern dipakai untuk kode seperti achievement_type: huruf kecil, angka dan underscore.
+var slugPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// academicYearPattern dipakai untuk tahun akademik seperti 2025/2026.
+var academicYearPattern = regexp.MustCompile(`^(\d{4})/(\d{4})$`)
+
 func init() {
-	validate = validator.New()
+	validate = validator.New(validator.WithRequiredStructEnabled())
+
+	// Nama field di error mengikuti tag json agar sama dengan body request.
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+
+	validate.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	})
+	validate.RegisterValidation("academic_year", func(fl validator.FieldLevel) bool {
+		m := academicYearPattern.FindStringSubmatch(fl.Field().String())
+		if m == nil {
+			return false
+		}
+		first, _ := strconv.Atoi(m[1])
+		second, _ := strconv.Atoi(m[2])
+		return second == first+1
+	})
+	validate.RegisterStructValidation(validateOrganizationPeriod, model.OrganizationRequest{})
+	validate.RegisterStructValidation(validateDelegationPeriod, model.CreateDelegationRequest{})
+	validate.RegisterStructValidation(validateAcademicPeriod, model.AcademicPeriodRequest{})
+	validate.RegisterStructValidation(validateSubmissionWindow, model.SubmissionWindowRequest{})
 }
 
 func ValidateStruct(s interface{}) error {
 	return validate.Struct(s)
 }
 
-func FormatValidationErrors(err error) string {
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		var errMsg string
-		for i, e := range validationErrors {
-			if i > 0 {
-				errMsg += "; "
-			}
-			switch e.Tag() {
-			case "required":
-				errMsg += e.Field() + " wajib diisi"
-			case "email":
-				errMsg += e.Field() + " harus email"
-			case "min":
-				errMsg += e.Field() + " minimal " + e.Param() + " karakter"
-			case "max":
-				errMsg += e.Field() + " maksimal " + e.Param() + " karakter"
-			case "oneof":
-				errMsg += e.Field() + " harus salah satu: " + e.Param()
-			case "gt":
-				errMsg += e.Field() + " harus lebih besar dari " + e.Param()
-			default:
-				errMsg += e.Field() + " tidak valid"
-			}
+// FieldErrors mengubah error validator menjadi daftar error per field. Pesan
+// tiap field diisi saat dirender sesuai bahasa request.
+func FieldErrors(err error) []model.FieldError {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil
+	}
+
+	fields := make([]model.FieldError, 0, len(validationErrors))
+	for _, e := range validationErrors {
+		fields = append(fields, model.FieldError{
+			Field: fieldPath(e.Namespace()),
+			Rule:  ruleName(e),
+			Param: e.Param(),
+		})
+	}
+	return fields
+}
+
+// fieldPath membuang nama struct root, mis. "CreateAchievementRequest.tags[0]" menjadi "tags[0]".
+func fieldPath(namespace string) string {
+	if _, rest, ok := strings.Cut(namespace, "."); ok {
+		return rest
+	}
+	return namespace
+}
+
+// ruleName membedakan batas jumlah item (slice) dari batas panjang teks.
+func ruleName(e validator.FieldError) string {
+	switch e.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if e.Tag() == "min" || e.Tag() == "max" {
+			return e.Tag() + "_items"
 		}
-		return errMsg
 	}
-	return err.Error()
+	return e.Tag()
+}
+
+// validateOrganizationPeriod memastikan end_date tidak sebelum start_date.
+func validateOrganizationPeriod(sl validator.StructLevel) {
+	org := sl.Current().Interface().(model.OrganizationRequest)
+	if org.StartDate == "" || org.EndDate == "" {
+		return
+	}
+
+	start, errStart := time.Parse(DateLayout, org.StartDate)
+	end, errEnd := time.Parse(DateLayout, org.EndDate)
+	if errStart != nil || errEnd != nil {
+		return
+	}
+	if end.Before(start) {
+		sl.ReportError(org.EndDate, "end_date", "EndDate", "date_after", "start_date")
+	}
+}
+
+// validateDelegationPeriod memastikan ends_at tidak sebelum starts_at.
+func validateDelegationPeriod(sl validator.StructLevel) {
+	req := sl.Current().Interface().(model.CreateDelegationRequest)
+	start, errStart := time.Parse(DateLayout, req.StartsAt)
+	end, errEnd := time.Parse(DateLayout, req.EndsAt)
+	if errStart != nil || errEnd != nil {
+		return
+	}
+	if end.Before(start) {
+		sl.ReportError(req.EndsAt, "ends_at", "EndsAt", "date_after", "starts_at")
+	}
+}
+
+// validateAcademicPeriod memastikan ends_on tidak sebelum starts_on.
+func validateAcademicPeriod(sl validator.StructLevel) {
+	req := sl.Current().Interface().(model.AcademicPeriodRequest)
+	start, errStart := time.Parse(DateLayout, req.StartsOn)
+	end, errEnd := time.Parse(DateLayout, req.EndsOn)
+	if errStart != nil || errEnd != nil {
+		return
+	}
+	if end.Before(start) {
+		sl.ReportError(req.EndsOn, "ends_on", "EndsOn", "date_after", "starts_on")
+	}
+}
+
+// validateSubmissionWindow memastikan closes_at setelah opens_at.
+func validateSubmissionWindow(sl validator.StructLevel) {
+	req := sl.Current().Interface().(model.SubmissionWindowRequest)
+	if req.OpensAt.IsZero() || req.ClosesAt.IsZero() {
+		return
+	}
+	if !req.ClosesAt.After(req.OpensAt) {
+		sl.ReportError(req.ClosesAt, "closes_at", "ClosesAt", "date_after", "opens_at")
+	}
 }
@@ -0,0 +1,157 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"fiber/skp/app/model"
+)
+
+func TestValidateDateRanges(t *testing.T) {
+	opens := time.Date(2025, 8, 1, 8, 0, 0, 0, time.UTC)
+
+	organization := func(start, end string) model.OrganizationRequest {
+		return model.OrganizationRequest{OrganizationName: "BEM", Position: "Ketua", StartDate: start, EndDate: end}
+	}
+	delegation := func(start, end string) model.CreateDelegationRequest {
+		return model.CreateDelegationRequest{DelegateID: "9f1c2b7e-3d4a-4f5b-8c6d-7e8f9a0b1c2d", StartsAt: start, EndsAt: end}
+	}
+	academicPeriod := func(start, end string) model.AcademicPeriodRequest {
+		return model.AcademicPeriodRequest{Year: "2025/2026", Semester: "odd", StartsOn: start, EndsOn: end}
+	}
+
+	tests := []struct {
+		name string
+		req  interface{}
+		// want berisi field dan rule yang dilaporkan, kosong bila valid.
+		want []model.FieldError
+	}{
+		{name: "organisasi berakhir sebelum mulai", req: organization("2025-09-01", "2025-08-31"),
+			want: []model.FieldError{{Field: "end_date", Rule: "date_after", Param: "start_date"}}},
+		{name: "organisasi berakhir di hari yang sama", req: organization("2025-09-01", "2025-09-01")},
+		{name: "organisasi tanggal tidak valid", req: organization("2025-09-01", "2025-13-01"),
+			want: []model.FieldError{{Field: "end_date", Rule: "datetime", Param: DateLayout}}},
+		{name: "organisasi di dalam achievement", req: model.CreateAchievementRequest{
+			Title:               "Ketua BEM",
+			AchievementType:     "organization",
+			OrganizationDetails: ptr(organization("2025-09-01", "2025-08-31")),
+		}, want: []model.FieldError{{Field: "organization_details.end_date", Rule: "date_after", Param: "start_date"}}},
+
+		{name: "delegasi berakhir sebelum mulai", req: delegation("2025-09-10", "2025-09-09"),
+			want: []model.FieldError{{Field: "ends_at", Rule: "date_after", Param: "starts_at"}}},
+		{name: "delegasi berakhir di hari yang sama", req: delegation("2025-09-10", "2025-09-10")},
+		{name: "delegasi tanggal tidak valid", req: delegation("10-09-2025", "2025-09-10"),
+			want: []model.FieldError{{Field: "starts_at", Rule: "datetime", Param: DateLayout}}},
+
+		{name: "periode akademik berakhir sebelum mulai", req: academicPeriod("2025-08-01", "2025-07-31"),
+			want: []model.FieldError{{Field: "ends_on", Rule: "date_after", Param: "starts_on"}}},
+		{name: "periode akademik berakhir di hari yang sama", req: academicPeriod("2025-08-01", "2025-08-01")},
+		{name: "periode akademik tanggal tidak valid", req: academicPeriod("2025-08-01", "2026-02-30"),
+			want: []model.FieldError{{Field: "ends_on", Rule: "datetime", Param: DateLayout}}},
+
+		{name: "jendela ditutup sebelum dibuka", req: model.SubmissionWindowRequest{OpensAt: opens, ClosesAt: opens.Add(-time.Hour)},
+			want: []model.FieldError{{Field: "closes_at", Rule: "date_after", Param: "opens_at"}}},
+		{name: "jendela ditutup saat dibuka", req: model.SubmissionWindowRequest{OpensAt: opens, ClosesAt: opens},
+			want: []model.FieldError{{Field: "closes_at", Rule: "date_after", Param: "opens_at"}}},
+		{name: "jendela valid", req: model.SubmissionWindowRequest{OpensAt: opens, ClosesAt: opens.Add(time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []model.FieldError
+			if err := ValidateStruct(tt.req); err != nil {
+				got = FieldErrors(err)
+			}
+			assertFieldErrors(t, got, tt.want)
+		})
+	}
+}
+
+func TestValidateTypeDetails(t *testing.T) {
+	competition := []byte(`{
+		"type": "object",
+		"properties": {
+			"competitionName": {"type": "string", "minLength": 1},
+			"competitionLevel": {"type": "string", "minLength": 1}
+		},
+		"required": ["competitionName", "competitionLevel"],
+		"additionalProperties": false
+	}`)
+	organization := []byte(`{
+		"type": "object",
+		"properties": {
+			"period": {
+				"type": "object",
+				"properties": {
+					"start": {"type": "string", "format": "date"},
+					"end": {"type": "string", "format": "date"}
+				},
+				"required": ["start", "end"],
+				"additionalProperties": false
+			}
+		},
+		"required": ["period"],
+		"additionalProperties": false
+	}`)
+
+	tests := []struct {
+		name    string
+		schema  []byte
+		details map[string]interface{}
+		// want berisi field yang dilaporkan, kosong bila valid.
+		want []string
+	}{
+		{name: "details lengkap", schema: competition, details: map[string]interface{}{
+			"competitionName": "Gemastik", "competitionLevel": "nasional",
+		}},
+		{name: "details wajib tidak diisi", schema: competition, details: map[string]interface{}{
+			"competitionName": "Gemastik",
+		}, want: []string{"details"}},
+		{name: "details tipe lain tidak diizinkan", schema: competition, details: map[string]interface{}{
+			"competitionName": "Gemastik", "competitionLevel": "nasional", "issuedBy": "BNSP",
+		}, want: []string{"details"}},
+		{name: "tanggal period tidak valid", schema: organization, details: map[string]interface{}{
+			"period": map[string]interface{}{"start": "2025-09-01", "end": "2025-09-31"},
+		}, want: []string{"details.period.end"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := CompileSchema(tt.schema)
+			if err != nil {
+				t.Fatalf("CompileSchema: %v", err)
+			}
+
+			var got []string
+			if err := schema.Validate(tt.details); err != nil {
+				for _, f := range SchemaFieldErrors("details", err) {
+					got = append(got, f.Field)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("field %d: got %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func assertFieldErrors(t *testing.T, got, want []model.FieldError) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("error %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	LangID = "id"
+	LangEN = "en"
+
+	DefaultLang = LangID
+)
+
+// Supported berisi bahasa yang punya katalog, urutan pertama adalah default.
+var Supported = []string{LangID, LangEN}
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var catalogs = map[string]map[string]string{}
+
+func init() {
+	for _, lang := range Supported {
+		raw, err := localeFS.ReadFile(path.Join("locales", lang+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: katalog %s tidak ditemukan: %v", lang, err))
+		}
+		catalog := map[string]string{}
+		if err := json.Unmarshal(raw, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: katalog %s tidak valid: %v", lang, err))
+		}
+		catalogs[lang] = catalog
+	}
+}
+
+type ctxKey struct{}
+
+// WithLang menyimpan bahasa request di ctx.
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// LangFromContext mengembalikan bahasa request, atau DefaultLang bila tidak ada.
+func LangFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if lang, ok := ctx.Value(ctxKey{}).(string); ok && lang != "" {
+			return lang
+		}
+	}
+	return DefaultLang
+}
+
+// Normalize memetakan tag bahasa seperti "en-US" ke bahasa yang didukung.
+func Normalize(tag string) (string, bool) {
+	base := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	if _, ok := catalogs[base]; ok {
+		return base, true
+	}
+	return "", false
+}
+
+// T menerjemahkan key ke bahasa request. Bila key tidak ada di katalog bahasa
+// tersebut dipakai katalog default, lalu key itu sendiri.
+func T(ctx context.Context, key string, args ...any) string {
+	return Translate(LangFromContext(ctx), key, args...)
+}
+
+// Translate seperti T, tetapi dengan bahasa yang ditentukan langsung.
+func Translate(lang, key string, args ...any) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[DefaultLang][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
@@ -1,21 +1,31 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"fiber/skp/config"
-	"fiber/skp/db"
-	"fiber/skp/route"
+	"fiber/skp/container"
 )
 
 func main() {
-	db.ConnectDB()
+	config.LoadEnv()
 	config.Logger()
 
-	app := config.NewApp()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	route.SetupRoutes(app, db.GetDB(), db.GetMongo())
+	c, err := container.New(ctx)
+	if err != nil {
+		slog.Error("Gagal menginisialisasi aplikasi", "error", err)
+		os.Exit(1)
+	}
 
-	port := ":" + config.GetAppPort()
-	log.Fatal(app.Listen(port))
+	if err := c.Run(ctx); err != nil {
+		slog.Error("Server berhenti dengan error", "error", err)
+		os.Exit(1)
+	}
 }
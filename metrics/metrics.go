@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const namespace = "skp"
+
+// Event achievement yang dihitung oleh AchievementEvents.
+const (
+	EventCreated   = "created"
+	EventSubmitted = "submitted"
+	EventVerified  = "verified"
+	EventRejected  = "rejected"
+	EventRevoked   = "revoked"
+)
+
+var (
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latensi request HTTP per route dan status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	MongoCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongo_command_duration_seconds",
+		Help:      "Durasi command MongoDB per nama command dan hasil.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"command", "result"})
+
+	AchievementEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "achievement_events_total",
+		Help:      "Jumlah achievement yang dibuat, disubmit, diverifikasi, ditolak dan dicabut per tipe.",
+	}, []string{"event", "type"})
+
+	LoginFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_failures_total",
+		Help:      "Jumlah login yang gagal per alasan.",
+	}, []string{"reason"})
+
+	DuplicateSuspects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_suspects_total",
+		Help:      "Jumlah dugaan duplikat yang ditemukan saat submit per alasan.",
+	}, []string{"reason"})
+
+	UploadSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upload_size_bytes",
+		Help:      "Ukuran file attachment yang diunggah.",
+		Buckets:   prometheus.ExponentialBuckets(16*1024, 2, 10),
+	})
+)
+
+// RegisterDBStats mengekspos statistik pool sql.DB sebagai gauge.
+func RegisterDBStats(db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// AchievementEvent menaikkan counter event achievement untuk tipe tertentu.
+func AchievementEvent(event, achievementType string) {
+	if achievementType == "" {
+		achievementType = "unknown"
+	}
+	AchievementEvents.WithLabelValues(event, achievementType).Inc()
+}
+
+// MongoCommandMonitor mencatat durasi setiap command MongoDB.
+func MongoCommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			observeMongo(evt.CommandName, "ok", evt.Duration)
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			observeMongo(evt.CommandName, "error", evt.Duration)
+		},
+	}
+}
+
+func observeMongo(command, result string, d time.Duration) {
+	MongoCommandDuration.WithLabelValues(command, result).Observe(d.Seconds())
+}
+
+// Handler mengekspos seluruh metrik dalam format Prometheus.
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.Handler())
+}
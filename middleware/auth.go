@@ -3,61 +3,47 @@ package middleware
 import (
 	"strings"
 
+	"fiber/skp/app/apperror"
 	"fiber/skp/app/model"
-	"fiber/skp/db"
+	"fiber/skp/app/repo"
 	"fiber/skp/helper"
+	"fiber/skp/logging"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
-func AuthRequired() fiber.Handler {
+func AuthRequired(userRepo repo.UserRepository) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		bearer := strings.TrimSpace(c.Get("Authorization"))
 		if bearer == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Token tidak ditemukan",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenMissing, "auth.token_missing")
 		}
 
 		if len(bearer) < 7 || !strings.EqualFold(bearer[:7], "Bearer ") {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Format (Bearer) token tidak valid",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenInvalidFormat, "auth.token_invalid_format")
 		}
 		token := strings.TrimSpace(bearer[7:])
 
 		claims, err := helper.ValidateToken(token)
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Token tidak valid",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenInvalid, "auth.token_invalid").Wrap(err)
 		}
 
 		if claims.Type != "access" {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Tipe token tidak valid",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenTypeInvalid, "auth.token_type_invalid")
 		}
 
-		var exists bool
-		blacklistErr := db.GetDB().QueryRow("SELECT EXISTS(SELECT 1 FROM blacklisted_tokens WHERE token = $1)", token).Scan(&exists)
+		exists, blacklistErr := userRepo.IsTokenBlacklisted(c.UserContext(), token)
+		if blacklistErr != nil {
+			logging.FromContext(c.UserContext()).Error("Gagal memeriksa blacklist token", "error", blacklistErr)
+		}
 		if blacklistErr == nil && exists {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Token telah di blacklist",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenRevoked, "auth.token_revoked")
 		}
 
 		if claims == nil || claims.UserID == uuid.Nil || claims.Username == "" || claims.Role == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Claim token tidak lengkap",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenClaimsIncomplete, "auth.claims_incomplete")
 		}
 
 		role := strings.ToLower(claims.Role)
@@ -75,18 +61,12 @@ func PermissionsRequired(requiredPermissions ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claims := c.Locals("user")
 		if claims == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Claim user tidak ditemukan",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenClaimsIncomplete, "auth.claims_missing")
 		}
 
 		jwtClaims, ok := claims.(*model.JWTClaims)
 		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
-				Success: false,
-				Message: "Format claim tidak valid",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenClaimsIncomplete, "auth.claims_invalid")
 		}
 
 		userPermissions := jwtClaims.Permissions
@@ -99,9 +79,6 @@ func PermissionsRequired(requiredPermissions ...string) fiber.Handler {
 			}
 		}
 
-		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
-			Success: false,
-			Message: "Akses dilarang",
-		})
+		return apperror.Forbidden(apperror.CodePermissionDenied, "auth.forbidden")
 	}
 }
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/app/model"
+	"fiber/skp/i18n"
+	"fiber/skp/logging"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrorHandler adalah satu-satunya tempat error dari handler dirender ke
+// client. Pesan diterjemahkan sesuai bahasa request, sedangkan detail internal
+// (Err) hanya dicatat di log, tidak pernah dikirim.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	ctx := c.UserContext()
+	appErr := apperror.From(err)
+	status := appErr.Status()
+
+	if appErr.Err != nil {
+		level := slog.LevelDebug
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logging.FromContext(ctx).Log(ctx, level, "request gagal",
+			"code", appErr.Code,
+			"status", status,
+			"error", appErr.Err.Error(),
+		)
+	}
+
+	traceID, _ := c.Locals("trace_id").(string)
+
+	return c.Status(status).JSON(model.ErrorResponse{
+		Success: false,
+		Code:    appErr.Code,
+		Message: i18n.T(ctx, appErr.Key, appErr.Args...),
+		Errors:  localizeFields(ctx, appErr.Fields),
+		TraceID: traceID,
+	})
+}
+
+func localizeFields(ctx context.Context, fields []model.FieldError) []model.FieldError {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	localized := make([]model.FieldError, len(fields))
+	for i, f := range fields {
+		key := "validation.rule." + f.Rule
+		msg := i18n.T(ctx, key, f.Field, f.Param)
+		if msg == key {
+			msg = i18n.T(ctx, "validation.rule.invalid", f.Field, f.Param)
+		}
+		f.Message = msg
+		localized[i] = f
+	}
+	return localized
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"fiber/skp/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Locale memilih bahasa response dari header Accept-Language (mengikuti bobot
+// q) dan menyimpannya di c.UserContext() untuk dipakai i18n.T.
+func Locale() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lang := preferredLang(c.Get(fiber.HeaderAcceptLanguage))
+
+		c.Locals("lang", lang)
+		c.Set(fiber.HeaderContentLanguage, lang)
+		c.SetUserContext(i18n.WithLang(c.UserContext(), lang))
+
+		return c.Next()
+	}
+}
+
+func preferredLang(header string) string {
+	best, bestQ := i18n.DefaultLang, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, ok := i18n.Normalize(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
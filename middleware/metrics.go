@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics mencatat latensi setiap request per route template dan status.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = apperror.Status(err)
+		}
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Method(), c.Route().Path, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
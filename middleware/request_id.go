@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"fiber/skp/app/apperror"
+	"fiber/skp/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,128}$`)
+
+// RequestID memakai X-Request-ID dari client bila valid atau membuat yang baru,
+// mengirimkannya balik di response, dan memasang logger request di c.UserContext().
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Locals("request_id", requestID)
+		c.Set(RequestIDHeader, requestID)
+
+		logger := slog.Default().With("request_id", requestID)
+		if traceID, ok := c.Locals("trace_id").(string); ok && traceID != "" {
+			logger = logger.With("trace_id", traceID)
+		}
+		c.SetUserContext(logging.WithLogger(c.UserContext(), logger))
+
+		return c.Next()
+	}
+}
+
+// AccessLog mencatat setiap request yang selesai memakai logger request.
+func AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = apperror.Status(err)
+		}
+
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		} else if status >= fiber.StatusBadRequest {
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			"method", c.Method(),
+			"path", c.Path(),
+			"route", c.Route().Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"ip", c.IP(),
+		}
+		if err != nil {
+			attrs = append(attrs, "error_code", apperror.From(err).Code)
+		}
+
+		logging.FromContext(c.UserContext()).Log(c.UserContext(), level, "request selesai", attrs...)
+		return err
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"context"
+
+	"fiber/skp/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deadline memberi batas waktu REQUEST_TIMEOUT pada context request sehingga
+// query yang diturunkan darinya berhenti saat batas tersebut terlewati.
+func Deadline() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), config.GetRequestTimeout())
+		defer cancel()
+
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
@@ -6,12 +6,22 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"fiber/skp/app/model"
 	"fiber/skp/app/repo"
 	"fiber/skp/app/service"
+	"fiber/skp/metrics"
 	"fiber/skp/middleware"
+	"fiber/skp/tracing"
 )
 
 func SetupRoutes(app *fiber.App, pgDB *sql.DB, mongoDB *mongo.Database) {
+	app.Use(tracing.Middleware())
+	app.Use(middleware.RequestID())
+	app.Use(middleware.Locale())
+	app.Use(middleware.Deadline())
+	app.Use(middleware.AccessLog())
+	app.Use(middleware.Metrics())
+
 	api := app.Group("/api")
 	v1 := api.Group("/v1")
 
@@ -20,12 +30,30 @@ func SetupRoutes(app *fiber.App, pgDB *sql.DB, mongoDB *mongo.Database) {
 	lecturerRepo := repo.NewLecturerRepo(pgDB)
 	achievementRepo := repo.NewAchievementRepo(pgDB, mongoDB)
 	reportRepo := repo.NewReportRepo(pgDB, mongoDB)
+	achievementTypeRepo := repo.NewAchievementTypeRepo(pgDB)
+	notificationRepo := repo.NewNotificationRepo(pgDB)
+	verificationChainRepo := repo.NewVerificationChainRepo(pgDB)
+	delegationRepo := repo.NewDelegationRepo(pgDB)
+	academicUnitRepo := repo.NewAcademicUnitRepo(pgDB)
+	academicPeriodRepo := repo.NewAcademicPeriodRepo(pgDB)
 
 	authService := service.NewAuthService(userRepo)
-	userService := service.NewUserService(userRepo, studentRepo, lecturerRepo)
+	userService := service.NewUserService(userRepo, studentRepo, lecturerRepo, academicUnitRepo)
 	academicService := service.NewAcademicService(studentRepo, lecturerRepo, achievementRepo)
-	achievementSvc := service.NewAchievementService(achievementRepo, studentRepo, lecturerRepo)
+	achievementSvc := service.NewAchievementService(achievementRepo, studentRepo, lecturerRepo, achievementTypeRepo, notificationRepo, verificationChainRepo, academicPeriodRepo)
+	achievementTypeService := service.NewAchievementTypeService(achievementTypeRepo)
+	verificationChainService := service.NewVerificationChainService(verificationChainRepo, achievementTypeRepo)
+	notificationService := service.NewNotificationService(notificationRepo)
+	delegationService := service.NewDelegationService(delegationRepo, lecturerRepo, notificationRepo)
+	academicUnitService := service.NewAcademicUnitService(academicUnitRepo)
+	academicPeriodService := service.NewAcademicPeriodService(academicPeriodRepo, studentRepo)
 	reportService := service.NewReportService(reportRepo, studentRepo)
+	healthService := service.NewHealthService(pgDB, mongoDB)
+
+	app.Get("/healthz", healthService.Liveness)
+	app.Get("/readyz", healthService.Readiness)
+	app.Get("/metrics", metrics.Handler())
+	app.Get("/status", middleware.AuthRequired(userRepo), middleware.PermissionsRequired("user:manage"), healthService.Status)
 
 	auth := v1.Group("/auth")
 
@@ -33,7 +61,7 @@ func SetupRoutes(app *fiber.App, pgDB *sql.DB, mongoDB *mongo.Database) {
 	auth.Post("/refresh", authService.Refresh)
 	auth.Post("/logout", authService.Logout)
 
-	protected := v1.Group("", middleware.AuthRequired())
+	protected := v1.Group("", middleware.AuthRequired(userRepo))
 
 	protected.Get("/auth/profile", authService.Profile)
 
@@ -50,14 +78,24 @@ func SetupRoutes(app *fiber.App, pgDB *sql.DB, mongoDB *mongo.Database) {
 	// Students endpoint (Admin only)
 	students := protected.Group("/students", middleware.PermissionsRequired("user:manage"))
 	students.Get("/", academicService.GetAllStudents)
+	students.Post("/advisors/bulk", academicService.BulkAssignAdvisors)
+	students.Post("/advisors/auto-assign/preview", academicService.PreviewAutoAssign)
+	students.Post("/advisors/auto-assign", academicService.ApplyAutoAssign)
 	students.Get("/:id", academicService.GetStudentDetail)
 	students.Get("/:id/achievements", academicService.GetStudentAchievements)
 	students.Put("/:id/advisor", academicService.AssignAdvisor)
+	students.Get("/:id/advisor-history", academicService.GetAdvisorHistory)
+	students.Put("/:id/status", academicService.ChangeStudentStatus)
+	students.Get("/:id/status-history", academicService.GetStatusHistory)
 
 	// Lecturers endpoint (Admin only)
 	lecturers := protected.Group("/lecturers", middleware.PermissionsRequired("user:manage"))
 	lecturers.Get("/", academicService.GetAllLecturers)
 	lecturers.Get("/:id/advisees", academicService.GetAdvisees)
+	lecturers.Post("/:id/reassign-advisees", academicService.ReassignAdvisees)
+	lecturers.Put("/:id/capacity", academicService.SetMaxAdvisees)
+	lecturers.Put("/:id/appeal-reviewer", academicService.SetAppealReviewer)
+	lecturers.Put("/:id/department-head", academicService.SetDepartmentHead)
 
 	// Achievements endpoint
 	achievements := protected.Group("/achievements")
@@ -67,14 +105,110 @@ func SetupRoutes(app *fiber.App, pgDB *sql.DB, mongoDB *mongo.Database) {
 	achievements.Post("/", middleware.PermissionsRequired("achievement:create"), achievementSvc.Create)
 	achievements.Put("/:id", middleware.PermissionsRequired("achievement:update"), achievementSvc.Update)
 	achievements.Delete("/:id", middleware.PermissionsRequired("achievement:delete"), achievementSvc.Delete)
+	// Rute massal didaftarkan sebelum /:id/verify agar "bulk" tidak dibaca sebagai id.
+	achievements.Post("/bulk/verify", middleware.PermissionsRequired("achievement:verify"), achievementSvc.BulkVerify)
+	achievements.Post("/bulk/reject", middleware.PermissionsRequired("achievement:verify"), achievementSvc.BulkReject)
 	achievements.Post("/:id/submit", middleware.PermissionsRequired("achievement:create"), achievementSvc.Submit)
 	achievements.Post("/:id/verify", middleware.PermissionsRequired("achievement:verify"), achievementSvc.Verify)
 	achievements.Post("/:id/reject", middleware.PermissionsRequired("achievement:verify"), achievementSvc.Reject)
+	achievements.Post("/:id/request-changes", middleware.PermissionsRequired("achievement:verify"), achievementSvc.RequestChanges)
+	achievements.Post("/:id/revoke", middleware.PermissionsRequired("achievement:revoke"), achievementSvc.Revoke)
 	achievements.Get("/:id/history", achievementSvc.GetHistory)
+	achievements.Get("/:id/comments", achievementSvc.ListComments)
+	achievements.Post("/:id/comments", achievementSvc.AddComment)
 	achievements.Post("/:id/attachments", middleware.PermissionsRequired("achievement:create"), achievementSvc.UploadAttachment)
+	achievements.Put("/:id/team", middleware.PermissionsRequired("achievement:create"), achievementSvc.SetTeam)
+	achievements.Post("/:id/team/confirm", middleware.PermissionsRequired("achievement:create"), achievementSvc.ConfirmParticipation)
+	achievements.Post("/:id/team/decline", middleware.PermissionsRequired("achievement:create"), achievementSvc.DeclineParticipation)
+	achievements.Post("/:id/appeal", middleware.PermissionsRequired("achievement:create"), achievementSvc.Appeal)
+
+	// Advisor delegations endpoint
+	delegations := protected.Group("/delegations", middleware.PermissionsRequired("advisor:delegate"))
+
+	delegations.Get("/", delegationService.List)
+	delegations.Post("/", delegationService.Create)
+	delegations.Delete("/:id", delegationService.Revoke)
+
+	// Verification endpoint
+	verification := protected.Group("/verification")
+
+	verification.Get("/queue", middleware.PermissionsRequired("achievement:verify"), achievementSvc.VerificationQueue)
+	verification.Get("/sla-stats", middleware.PermissionsRequired("verification:report"), achievementSvc.SLAStats)
+
+	// Appeals endpoint
+	appeals := protected.Group("/appeals", middleware.PermissionsRequired("achievement:appeal_review"))
+
+	appeals.Get("/", achievementSvc.ListAppeals)
+	appeals.Post("/:id/decide", achievementSvc.DecideAppeal)
+
+	// Faculties, departments and study programs endpoint
+	faculties := protected.Group("/faculties")
+	registerAcademicUnitRoutes(faculties, academicUnitService, model.UnitFaculty)
+
+	departments := protected.Group("/departments")
+	registerAcademicUnitRoutes(departments, academicUnitService, model.UnitDepartment)
+
+	studyPrograms := protected.Group("/study-programs")
+	registerAcademicUnitRoutes(studyPrograms, academicUnitService, model.UnitStudyProgram)
+
+	// Academic calendar endpoint
+	academicPeriods := protected.Group("/academic-periods")
+
+	academicPeriods.Get("/", academicPeriodService.List)
+	academicPeriods.Post("/", middleware.PermissionsRequired("academic_period:manage"), academicPeriodService.Create)
+	academicPeriods.Put("/:id", middleware.PermissionsRequired("academic_period:manage"), academicPeriodService.Update)
+	academicPeriods.Delete("/:id", middleware.PermissionsRequired("academic_period:manage"), academicPeriodService.Delete)
+	academicPeriods.Get("/:id/windows", academicPeriodService.ListWindows)
+	academicPeriods.Post("/:id/windows", middleware.PermissionsRequired("academic_period:manage"), academicPeriodService.CreateWindow)
+	academicPeriods.Delete("/:id/windows/:windowId", middleware.PermissionsRequired("academic_period:manage"), academicPeriodService.DeleteWindow)
+
+	// Submission window exemptions endpoint (Admin only)
+	exemptions := protected.Group("/submission-exemptions", middleware.PermissionsRequired("academic_period:manage"))
+
+	exemptions.Get("/", academicPeriodService.ListExemptions)
+	exemptions.Post("/", academicPeriodService.CreateExemption)
+	exemptions.Delete("/:id", academicPeriodService.DeleteExemption)
+
+	// Achievement types endpoint
+	achievementTypes := protected.Group("/achievement-types")
+
+	achievementTypes.Get("/", achievementTypeService.List)
+	achievementTypes.Get("/:code/schema", achievementTypeService.GetSchema)
+	achievementTypes.Post("/", middleware.PermissionsRequired("achievement_type:manage"), achievementTypeService.Create)
+	achievementTypes.Put("/:code", middleware.PermissionsRequired("achievement_type:manage"), achievementTypeService.Update)
+	achievementTypes.Delete("/:code", middleware.PermissionsRequired("achievement_type:manage"), achievementTypeService.Delete)
+
+	// Verification chains endpoint (Admin only)
+	verificationChains := protected.Group("/verification-chains", middleware.PermissionsRequired("verification_chain:manage"))
+
+	verificationChains.Get("/", verificationChainService.List)
+	verificationChains.Get("/:id", verificationChainService.Get)
+	verificationChains.Post("/", verificationChainService.Create)
+	verificationChains.Put("/:id", verificationChainService.Update)
+	verificationChains.Delete("/:id", verificationChainService.Delete)
+
+	// Notifications endpoint
+	notifications := protected.Group("/notifications")
+
+	notifications.Get("/", notificationService.List)
+	notifications.Patch("/read-all", notificationService.MarkAllRead)
+	notifications.Patch("/:id/read", notificationService.MarkRead)
 
 	// Reports endpoint
 	reports := protected.Group("/reports")
 	reports.Get("/statistics", reportService.GetStatistics)
+	reports.Get("/advisors", middleware.PermissionsRequired("verification:report"), reportService.GetAdvisorAttribution)
 	reports.Get("/student/:id", reportService.GetStudentStats)
 }
+
+// registerAcademicUnitRoutes mendaftarkan endpoint yang sama untuk fakultas,
+// jurusan, dan program studi; membaca terbuka untuk semua user login.
+func registerAcademicUnitRoutes(group fiber.Router, svc *service.AcademicUnitService, kind string) {
+	manage := middleware.PermissionsRequired("academic_unit:manage")
+
+	group.Get("/", svc.List(kind))
+	group.Post("/", manage, svc.Create(kind))
+	group.Put("/:id", manage, svc.Update(kind))
+	group.Delete("/:id", manage, svc.Delete(kind))
+	group.Post("/:id/merge", manage, svc.Merge(kind))
+}